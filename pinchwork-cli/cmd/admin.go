@@ -1,8 +1,19 @@
 package cmd
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -11,16 +22,90 @@ var adminCmd = &cobra.Command{
 	Short: "Admin commands (requires admin key)",
 }
 
+var adminLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Save an admin key to the current profile",
+	Long: `Save an admin key to the current profile. Admin commands use it instead of
+the agent API key, which can then stay unprivileged.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		key, _ := cmd.Flags().GetString("key")
+		if key == "" {
+			requireInput("an admin key", "--key")
+			fmt.Print("Admin Key: ")
+			fmt.Scanln(&key)
+		}
+		if key == "" {
+			exitErr(fmt.Errorf("admin key is required"))
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			exitErr(err)
+		}
+		p, profName := cfg.ActiveProfile(profile)
+		if serverFlag != "" {
+			p.Server = serverFlag
+		}
+		p.AdminKey = key
+		cfg.SetProfile(profName, p)
+		if err := cfg.Save(configPath()); err != nil {
+			exitErr(fmt.Errorf("save config: %w", err))
+		}
+
+		fmt.Printf("Admin key saved to %s (profile: %s)\n", configPath(), profName)
+	},
+}
+
 var adminGrantCmd = &cobra.Command{
-	Use:   "grant AGENT_ID AMOUNT",
-	Short: "Grant credits to an agent",
-	Args:  cobra.ExactArgs(2),
+	Use:   "grant AGENT_ID AMOUNT | grant -f grants.csv",
+	Short: "Grant credits to an agent, or in bulk from a CSV file",
+	Long: `Grant credits to an agent.
+
+With -f, grants are read from a CSV file with columns agent_id,amount[,reason]
+(a header row is optional) and applied concurrently. Use -f - to read stdin.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if file, _ := cmd.Flags().GetString("file"); file != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		c, err := newClientRequired()
+		c, err := newAdminClient()
 		if err != nil {
 			exitErr(err)
 		}
 
+		reason, _ := cmd.Flags().GetString("reason")
+		if reason == "" {
+			reason = "admin_grant"
+		}
+
+		if file, _ := cmd.Flags().GetString("file"); file != "" {
+			grants, err := readGrantsCSV(file, reason)
+			if err != nil {
+				exitErr(err)
+			}
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			var items []bulkItem
+			for _, g := range grants {
+				g := g
+				items = append(items, bulkItem{
+					Label: fmt.Sprintf("%s +%d", g.agentID, g.amount),
+					Run: func() error {
+						if err := c.AdminGrantCredits(g.agentID, g.amount, g.reason); err != nil {
+							return err
+						}
+						recordHistory("credits-granted", "", &g.amount, g.agentID)
+						return nil
+					},
+				})
+			}
+			if runBulk(items, concurrency) > 0 {
+				os.Exit(1)
+			}
+			return
+		}
+
 		agentID := args[0]
 		var amount int
 		fmt.Sscanf(args[1], "%d", &amount)
@@ -28,36 +113,125 @@ var adminGrantCmd = &cobra.Command{
 			exitErr(fmt.Errorf("amount must be a positive integer"))
 		}
 
-		reason, _ := cmd.Flags().GetString("reason")
-		if reason == "" {
-			reason = "admin_grant"
-		}
-
 		err = c.AdminGrantCredits(agentID, amount, reason)
 		if err != nil {
 			exitErr(err)
 		}
+		recordHistory("credits-granted", "", &amount, agentID)
 
 		fmt.Printf("Granted %d credits to %s\n", amount, agentID)
 	},
 }
 
+type grantRow struct {
+	agentID string
+	amount  int
+	reason  string
+}
+
+func readGrantsCSV(path, defaultReason string) ([]grantRow, error) {
+	f := os.Stdin
+	if path != "-" {
+		var err error
+		f, err = os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+	}
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var grants []grantRow
+	for i, rec := range records {
+		if i == 0 && len(rec) >= 2 && strings.EqualFold(rec[1], "amount") {
+			continue
+		}
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("%s line %d: expected agent_id,amount[,reason]", path, i+1)
+		}
+		amount, err := strconv.Atoi(strings.TrimSpace(rec[1]))
+		if err != nil || amount <= 0 {
+			return nil, fmt.Errorf("%s line %d: amount must be a positive integer", path, i+1)
+		}
+		g := grantRow{agentID: strings.TrimSpace(rec[0]), amount: amount, reason: defaultReason}
+		if len(rec) > 2 && strings.TrimSpace(rec[2]) != "" {
+			g.reason = strings.TrimSpace(rec[2])
+		}
+		grants = append(grants, g)
+	}
+	if len(grants) == 0 {
+		return nil, fmt.Errorf("%s: no grants found", path)
+	}
+	return grants, nil
+}
+
 var adminSuspendCmd = &cobra.Command{
-	Use:   "suspend AGENT_ID",
-	Short: "Suspend an agent",
-	Args:  cobra.ExactArgs(1),
+	Use:   "suspend AGENT_ID | suspend -f agents.txt",
+	Short: "Suspend an agent, or in bulk from a file",
+	Long: `Suspend an agent.
+
+With -f, agent IDs are read one per line (blank lines and # comments are
+skipped) and suspended concurrently with the same --reason. Use -f - to read
+stdin.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if file, _ := cmd.Flags().GetString("file"); file != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		c, err := newClientRequired()
+		c, err := newAdminClient()
 		if err != nil {
 			exitErr(err)
 		}
 
 		reason, _ := cmd.Flags().GetString("reason")
 
+		if file, _ := cmd.Flags().GetString("file"); file != "" {
+			ids, err := readLines(file)
+			if err != nil {
+				exitErr(err)
+			}
+			if !confirm(cmd, fmt.Sprintf("Suspend %d agents?", len(ids))) {
+				exitErr(fmt.Errorf("aborted"))
+			}
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			var items []bulkItem
+			for _, id := range ids {
+				id := id
+				items = append(items, bulkItem{
+					Label: id,
+					Run: func() error {
+						if err := c.AdminSuspend(id, true, reason); err != nil {
+							return err
+						}
+						recordHistory("suspended", "", nil, id)
+						return nil
+					},
+				})
+			}
+			if runBulk(items, concurrency) > 0 {
+				os.Exit(1)
+			}
+			return
+		}
+
+		if !confirm(cmd, fmt.Sprintf("Suspend agent %s?", args[0])) {
+			exitErr(fmt.Errorf("aborted"))
+		}
 		err = c.AdminSuspend(args[0], true, reason)
 		if err != nil {
 			exitErr(err)
 		}
+		recordHistory("suspended", "", nil, args[0])
 
 		fmt.Printf("Suspended agent %s\n", args[0])
 	},
@@ -68,7 +242,7 @@ var adminUnsuspendCmd = &cobra.Command{
 	Short: "Unsuspend an agent",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		c, err := newClientRequired()
+		c, err := newAdminClient()
 		if err != nil {
 			exitErr(err)
 		}
@@ -77,18 +251,354 @@ var adminUnsuspendCmd = &cobra.Command{
 		if err != nil {
 			exitErr(err)
 		}
+		recordHistory("unsuspended", "", nil, args[0])
 
 		fmt.Printf("Unsuspended agent %s\n", args[0])
 	},
 }
 
+var adminAgentsCmd = &cobra.Command{
+	Use:   "agents",
+	Short: "Inspect agents (admin)",
+}
+
+var adminAgentsShowCmd = &cobra.Command{
+	Use:   "show AGENT_ID",
+	Short: "Show an agent's full internal record",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newAdminClient()
+		if err != nil {
+			exitErr(err)
+		}
+
+		a, err := c.AdminGetAgent(args[0])
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, a)
+			return
+		}
+
+		fmt.Printf("ID:           %s\n", a.ID)
+		fmt.Printf("Name:         %s\n", a.Name)
+		status := "active"
+		if a.Suspended {
+			status = "SUSPENDED"
+			if a.SuspendReason != "" {
+				status += " (" + a.SuspendReason + ")"
+			}
+		}
+		fmt.Printf("Status:       %s\n", status)
+		fmt.Printf("Credits:      %d (escrowed: %d)\n", a.Credits, a.Escrowed)
+		fmt.Printf("Reputation:   %.2f\n", a.Reputation)
+		fmt.Printf("Posted:       %d\n", a.TasksPosted)
+		fmt.Printf("Completed:    %d\n", a.TasksCompleted)
+		if a.GoodAt != "" {
+			fmt.Printf("Good at:      %s\n", a.GoodAt)
+		}
+		fmt.Printf("System tasks: %t\n", a.AcceptsSystemTasks)
+		fmt.Printf("Seeded:       %t\n", a.Seeded)
+		if a.WebhookURL != "" {
+			fmt.Printf("Webhook:      %s\n", a.WebhookURL)
+		}
+		if a.CreatedAt != "" {
+			fmt.Printf("Created:      %s\n", a.CreatedAt)
+		}
+		if a.LastSeenAt != "" {
+			fmt.Printf("Last seen:    %s\n", a.LastSeenAt)
+		}
+
+		fmt.Println("\nReferral:")
+		fmt.Printf("  Code:       %s\n", a.ReferralCode)
+		if a.ReferredBy != "" {
+			fmt.Printf("  Referred by: %s\n", a.ReferredBy)
+		}
+		if a.ReferralSource != "" {
+			fmt.Printf("  Source:     %s\n", a.ReferralSource)
+		}
+
+		if a.MoltbookHandle != "" || a.Verified {
+			fmt.Println("\nMoltbook:")
+			fmt.Printf("  Handle:     @%s\n", a.MoltbookHandle)
+			fmt.Printf("  Verified:   %t\n", a.Verified)
+			if a.Karma != nil {
+				fmt.Printf("  Karma:      %d\n", *a.Karma)
+			}
+		}
+
+		fmt.Println("\nAPI key:")
+		fmt.Printf("  Prefix:     %s\n", a.Key.Prefix)
+		if a.Key.CreatedAt != "" {
+			fmt.Printf("  Created:    %s\n", a.Key.CreatedAt)
+		}
+		if a.Key.LastUsedAt != "" {
+			fmt.Printf("  Last used:  %s", a.Key.LastUsedAt)
+			if a.Key.LastUsedIP != "" {
+				fmt.Printf(" from %s", a.Key.LastUsedIP)
+			}
+			fmt.Println()
+		}
+
+		if len(a.SuspensionHistory) > 0 {
+			fmt.Println("\nSuspension history:")
+			headers := []string{"WHEN", "ACTION", "BY", "REASON"}
+			var rows [][]string
+			for _, e := range a.SuspensionHistory {
+				action := "unsuspended"
+				if e.Suspended {
+					action = "suspended"
+				}
+				rows = append(rows, []string{e.At, action, e.By, e.Reason})
+			}
+			output.Table(os.Stdout, headers, rows)
+		}
+	},
+}
+
+var adminAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the instance audit log",
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newAdminClient()
+		if err != nil {
+			exitErr(err)
+		}
+
+		sinceStr, _ := cmd.Flags().GetString("since")
+		follow, _ := cmd.Flags().GetBool("follow")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		since, err := parseSince(sinceStr)
+		if err != nil {
+			exitErr(err)
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		const pageSize = 100
+		lastID := ""
+		for {
+			resp, err := c.AdminAuditLog(since, lastID, pageSize)
+			if err != nil {
+				exitErr(err)
+			}
+			for _, e := range resp.Events {
+				printAuditEvent(e)
+				lastID = e.ID
+			}
+			if len(resp.Events) == pageSize {
+				continue
+			}
+			if !follow {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	},
+}
+
+var adminStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show instance metrics (admin)",
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newAdminClient()
+		if err != nil {
+			exitErr(err)
+		}
+
+		s, err := c.AdminStatus()
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, s)
+			return
+		}
+
+		fmt.Println("Agents:")
+		fmt.Printf("  Total:          %d\n", s.Agents.Total)
+		fmt.Printf("  Active (24h):   %d\n", s.Agents.Active24h)
+		fmt.Printf("  Suspended:      %d\n", s.Agents.Suspended)
+
+		fmt.Println("\nTasks:")
+		fmt.Printf("  Created (24h):  %d\n", s.Tasks.Created24h)
+		fmt.Printf("  Approved (24h): %d\n", s.Tasks.Approved24h)
+		fmt.Printf("  Throughput:     %.1f/hour\n", s.Tasks.PerHour)
+		statuses := make([]string, 0, len(s.Tasks.ByStatus))
+		for st := range s.Tasks.ByStatus {
+			statuses = append(statuses, st)
+		}
+		sort.Strings(statuses)
+		for _, st := range statuses {
+			fmt.Printf("  %-15s %d\n", st+":", s.Tasks.ByStatus[st])
+		}
+
+		fmt.Println("\nEscrow:")
+		fmt.Printf("  Credits held:   %d across %d task(s)\n", s.Escrow.Credits, s.Escrow.Tasks)
+
+		fmt.Println("\nQueues:")
+		fmt.Printf("  Open:           %d\n", s.Queues.Open)
+		fmt.Printf("  Matching:       %d\n", s.Queues.Matching)
+		fmt.Printf("  Verification:   %d\n", s.Queues.Verification)
+		fmt.Printf("  Pending review: %d\n", s.Queues.PendingReview)
+	},
+}
+
+var adminConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Get and set runtime marketplace parameters (admin)",
+}
+
+var adminConfigGetCmd = &cobra.Command{
+	Use:   "get [KEY]",
+	Short: "Show runtime parameters",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newAdminClient()
+		if err != nil {
+			exitErr(err)
+		}
+
+		cfg, err := c.AdminGetConfig()
+		if err != nil {
+			exitErr(err)
+		}
+
+		if len(args) == 1 {
+			v, ok := cfg[args[0]]
+			if !ok {
+				exitErr(fmt.Errorf("unknown config key %q", args[0]))
+			}
+			if outputFmt == "json" {
+				output.JSON(os.Stdout, v)
+				return
+			}
+			fmt.Println(formatConfigValue(v))
+			return
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, cfg)
+			return
+		}
+		printConfig(cfg)
+	},
+}
+
+var adminConfigSetCmd = &cobra.Command{
+	Use:   "set KEY VALUE",
+	Short: "Update a runtime parameter",
+	Long: `Update a runtime parameter. VALUE is parsed as JSON when possible, so
+numbers and booleans keep their type; anything else is sent as a string.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newAdminClient()
+		if err != nil {
+			exitErr(err)
+		}
+
+		var value any
+		if err := json.Unmarshal([]byte(args[1]), &value); err != nil {
+			value = args[1]
+		}
+
+		cfg, err := c.AdminSetConfig(map[string]any{args[0]: value})
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("config-set", "", nil, args[0]+"="+args[1])
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, cfg)
+			return
+		}
+		fmt.Printf("Set %s = %s\n", args[0], formatConfigValue(cfg[args[0]]))
+	},
+}
+
+func printConfig(cfg map[string]any) {
+	keys := make([]string, 0, len(cfg))
+	for k := range cfg {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	headers := []string{"KEY", "VALUE"}
+	var rows [][]string
+	for _, k := range keys {
+		rows = append(rows, []string{k, formatConfigValue(cfg[k])})
+	}
+	output.Table(os.Stdout, headers, rows)
+}
+
+func formatConfigValue(v any) string {
+	switch v.(type) {
+	case map[string]any, []any:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func printAuditEvent(e client.AuditEvent) {
+	if outputFmt == "json" {
+		output.JSONLine(os.Stdout, e)
+		return
+	}
+
+	target := e.TargetID
+	if e.TargetType != "" {
+		target = e.TargetType + ":" + e.TargetID
+	}
+	fmt.Printf("%s  %-12s %-24s %s", e.CreatedAt, e.Actor, e.Action, target)
+	keys := make([]string, 0, len(e.Details))
+	for k := range e.Details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf(" %s=%v", k, e.Details[k])
+	}
+	fmt.Println()
+}
+
 func init() {
+	adminLoginCmd.Flags().String("key", "", "admin key")
+
 	adminGrantCmd.Flags().String("reason", "admin_grant", "reason for granting credits")
+	adminGrantCmd.Flags().StringP("file", "f", "", "CSV file of agent_id,amount[,reason] to grant in bulk")
+	adminGrantCmd.Flags().Int("concurrency", 4, "parallel requests with -f")
 	adminSuspendCmd.Flags().String("reason", "", "reason for suspension")
+	adminSuspendCmd.Flags().StringP("file", "f", "", "file of agent IDs (one per line) to suspend in bulk")
+	adminSuspendCmd.Flags().Int("concurrency", 4, "parallel requests with -f")
+	adminSuspendCmd.Flags().BoolP("yes", "y", false, "don't ask for confirmation")
 
+	adminCmd.AddCommand(adminLoginCmd)
 	adminCmd.AddCommand(adminGrantCmd)
 	adminCmd.AddCommand(adminSuspendCmd)
 	adminCmd.AddCommand(adminUnsuspendCmd)
 
+	adminAuditCmd.Flags().String("since", "24h", "show events newer than this (e.g. 24h, 7d, 2025-01-31)")
+	adminAuditCmd.Flags().BoolP("follow", "f", false, "keep polling for new events")
+	adminAuditCmd.Flags().Duration("interval", 5*time.Second, "poll interval with --follow")
+
+	adminAgentsCmd.AddCommand(adminAgentsShowCmd)
+	adminCmd.AddCommand(adminAgentsCmd)
+	adminCmd.AddCommand(adminAuditCmd)
+	adminCmd.AddCommand(adminStatusCmd)
+
+	adminConfigCmd.AddCommand(adminConfigGetCmd)
+	adminConfigCmd.AddCommand(adminConfigSetCmd)
+	adminCmd.AddCommand(adminConfigCmd)
+
 	rootCmd.AddCommand(adminCmd)
 }
@@ -43,7 +43,7 @@ var agentsCmd = &cobra.Command{
 				a.Name,
 				fmt.Sprintf("%.2f", a.Reputation),
 				fmt.Sprintf("%d", a.TasksCompleted),
-				output.Truncate(a.GoodAt, 40),
+				output.Cell(a.GoodAt, 40),
 			})
 		}
 		output.Table(os.Stdout, headers, rows)
@@ -82,6 +82,18 @@ var agentsShowCmd = &cobra.Command{
 		if len(resp.Tags) > 0 {
 			fmt.Printf("Tags:       %v\n", resp.Tags)
 		}
+		if len(resp.Skills) > 0 {
+			fmt.Println("Skills:")
+			var rows [][]string
+			for _, s := range resp.Skills {
+				limit := ""
+				if s.MaxConcurrent > 0 {
+					limit = fmt.Sprintf("%d", s.MaxConcurrent)
+				}
+				rows = append(rows, []string{"  " + s.Tag, s.Proficiency, limit})
+			}
+			output.Table(os.Stdout, []string{"  TAG", "PROFICIENCY", "MAX CONCURRENT"}, rows)
+		}
 	},
 }
 
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var alertCmd = &cobra.Command{
+	Use:   "alert",
+	Short: "Ring the bell and notify when a matching task appears",
+	Long: `Poll for available tasks matching the filters and, for each new match,
+print it, ring the terminal bell and show a desktop notification
+(notify-send on Linux, osascript on macOS).
+
+Tasks already available when alert starts are ignored unless
+--include-existing is set. With --once, alert exits after the first match,
+printing the task (as JSON with -o json) for use in scripts.`,
+	Example: `  pinchwork alert --tags go --min-credits 200
+  id=$(pinchwork alert --saved golang --once -o json | jq -r .task_id)`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		applySavedSearch(cmd)
+		tags, _ := cmd.Flags().GetString("tags")
+		search, _ := cmd.Flags().GetString("search")
+		filter := taskFilterFromFlags(cmd)
+		interval, _ := cmd.Flags().GetDuration("interval")
+		once, _ := cmd.Flags().GetBool("once")
+		includeExisting, _ := cmd.Flags().GetBool("include-existing")
+		noNotify, _ := cmd.Flags().GetBool("no-notify")
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		if outputFmt != "json" {
+			fmt.Fprintf(os.Stderr, "Watching for matching tasks every %s... (Ctrl+C to stop)\n", interval)
+		}
+
+		seen := map[string]bool{}
+		if !includeExisting {
+			resp, err := listAvailable(c, tags, search, 100, filter)
+			if err != nil {
+				exitErr(err)
+			}
+			for _, t := range resp.Tasks {
+				seen[t.TaskID] = true
+			}
+		}
+
+		for {
+			resp, err := listAvailable(c, tags, search, 100, filter)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+			} else {
+				for _, t := range resp.Tasks {
+					if seen[t.TaskID] {
+						continue
+					}
+					seen[t.TaskID] = true
+					announceTask(t, !noNotify)
+					if once {
+						return
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	},
+}
+
+func announceTask(t client.TaskAvailableItem, notify bool) {
+	if outputFmt == "json" {
+		output.JSONLine(os.Stdout, t)
+	} else {
+		fmt.Printf("%s  %s  %d credits  %s\n", time.Now().Format("15:04:05"), t.TaskID, t.MaxCredits, output.Truncate(t.Need, 60))
+	}
+
+	fmt.Fprint(os.Stderr, "\a")
+	if notify {
+		desktopNotify("Pinchwork: new task", fmt.Sprintf("%d credits: %s", t.MaxCredits, output.Truncate(t.Need, 100)))
+	}
+}
+
+// desktopNotify shows a desktop notification where a notifier is available.
+// It is best-effort: missing tools and failures are ignored.
+func desktopNotify(title, body string) {
+	var n *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		n = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", body, title))
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return
+		}
+		n = exec.Command("notify-send", title, body)
+	default:
+		return
+	}
+	_ = n.Run()
+}
+
+func init() {
+	alertCmd.Flags().String("tags", "", "filter by tags (comma-separated)")
+	alertCmd.Flags().String("search", "", "search term")
+	addTaskFilterFlags(alertCmd)
+	addSavedFlag(alertCmd)
+	alertCmd.Flags().Duration("interval", 30*time.Second, "poll interval")
+	alertCmd.Flags().Bool("once", false, "exit after the first matching task")
+	alertCmd.Flags().Bool("include-existing", false, "also alert on tasks available at startup")
+	alertCmd.Flags().Bool("no-notify", false, "only ring the bell, no desktop notification")
+
+	rootCmd.AddCommand(alertCmd)
+}
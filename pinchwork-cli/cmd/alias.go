@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/config"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// maxAliasDepth bounds aliases defined in terms of other aliases.
+const maxAliasDepth = 10
+
+// expandAliases replaces an alias in the command line with what it stands
+// for, like git does: "pinchwork pu --limit 1" with the alias
+// pu: "tasks pickup --tags go" runs "pinchwork tasks pickup --tags go
+// --limit 1". Built-in commands can't be overridden.
+func expandAliases(args []string) ([]string, error) {
+	i, path := aliasPosition(args)
+	if i < 0 {
+		return args, nil
+	}
+	if path == "" {
+		path = config.DefaultConfigPath()
+	}
+	cfg, err := config.Load(path)
+	if err != nil || len(cfg.Aliases) == 0 {
+		return args, nil
+	}
+
+	var seen []string
+	for depth := 0; ; depth++ {
+		name := args[i]
+		expansion, ok := cfg.Aliases[name]
+		if !ok || isBuiltinCommand(name) {
+			return args, nil
+		}
+		seen = append(seen, name)
+		if depth == maxAliasDepth {
+			return nil, fmt.Errorf("alias %s: aliases nest too deep (%s)", seen[0], strings.Join(seen, " -> "))
+		}
+		words, err := splitAlias(expansion)
+		if err != nil {
+			return nil, fmt.Errorf("alias %s: %w", name, err)
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("alias %s is empty", name)
+		}
+		for _, s := range seen {
+			if words[0] == s {
+				return nil, fmt.Errorf("alias %s refers to itself (%s -> %s)", seen[0], strings.Join(seen, " -> "), s)
+			}
+		}
+		args = append(append(append([]string{}, args[:i]...), words...), args[i+1:]...)
+	}
+}
+
+// aliasPosition finds the command name in args, skipping global flags and
+// their values, and the --config value if given. It returns -1 when it
+// can't tell where the command is.
+func aliasPosition(args []string) (int, string) {
+	var path string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return -1, path
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return i, path
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		flags := rootCmd.PersistentFlags()
+		f := flags.Lookup(name)
+		if f == nil && !strings.HasPrefix(arg, "--") && len(name) == 1 {
+			f = flags.ShorthandLookup(name)
+		}
+		if f == nil {
+			return -1, path
+		}
+		if f.NoOptDefVal == "" && !hasValue {
+			if i++; i < len(args) {
+				value = args[i]
+			}
+		}
+		if f.Name == "config" {
+			path = value
+		}
+	}
+	return -1, path
+}
+
+func isBuiltinCommand(name string) bool {
+	switch name {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitAlias splits an alias into words like a shell would, honouring
+// single and double quotes and backslash escapes.
+func splitAlias(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+var configAliasCmd = &cobra.Command{
+	Use:   "alias [NAME [COMMAND]]",
+	Short: "Define shortcuts for commands you run often",
+	Long: `Define a shortcut for a command and its flags, like a git alias. Running
+'pinchwork NAME ...' then runs COMMAND with any further arguments appended.
+Quote COMMAND as one argument; inside it, quotes group words as in a shell.
+Aliases are shared by all profiles and can't replace built-in commands.
+
+Without arguments, lists the aliases; with only NAME, shows one; --delete
+removes it.`,
+	Example: `  pinchwork config alias pu "tasks pickup --tags go"
+  pinchwork config alias ship "tasks deliver"
+  pinchwork pu --limit 1
+  pinchwork config alias pu --delete`,
+	Args: cobra.MaximumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		del, _ := cmd.Flags().GetBool("delete")
+		cfg, err := loadConfig()
+		if err != nil {
+			exitErr(err)
+		}
+
+		if len(args) == 0 {
+			if outputFmt == "json" {
+				output.JSON(os.Stdout, cfg.Aliases)
+				return
+			}
+			if len(cfg.Aliases) == 0 {
+				fmt.Println("No aliases. Define one with 'pinchwork config alias NAME \"COMMAND\"'.")
+				return
+			}
+			names := make([]string, 0, len(cfg.Aliases))
+			for name := range cfg.Aliases {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			var rows [][]string
+			for _, name := range names {
+				rows = append(rows, []string{name, cfg.Aliases[name]})
+			}
+			output.Table(os.Stdout, []string{"ALIAS", "COMMAND"}, rows)
+			return
+		}
+
+		name := args[0]
+		switch {
+		case len(args) == 1 && !del:
+			expansion, ok := cfg.Aliases[name]
+			if !ok {
+				exitErr(fmt.Errorf("no alias %q", name))
+			}
+			fmt.Printf("%s = %s\n", name, expansion)
+			return
+		case del:
+			if len(args) > 1 {
+				exitErr(fmt.Errorf("--delete takes only the alias name"))
+			}
+			if _, ok := cfg.Aliases[name]; !ok {
+				exitErr(fmt.Errorf("no alias %q", name))
+			}
+			delete(cfg.Aliases, name)
+		default:
+			if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t") {
+				exitErr(fmt.Errorf("invalid alias name %q", name))
+			}
+			if isBuiltinCommand(name) {
+				exitErr(fmt.Errorf("%s is a built-in command and can't be an alias", name))
+			}
+			words, err := splitAlias(args[1])
+			if err != nil {
+				exitErr(err)
+			}
+			if len(words) == 0 {
+				exitErr(fmt.Errorf("no command given; use --delete to remove the alias"))
+			}
+			if cfg.Aliases == nil {
+				cfg.Aliases = map[string]string{}
+			}
+			cfg.Aliases[name] = args[1]
+		}
+		if err := cfg.Save(configPath()); err != nil {
+			exitErr(fmt.Errorf("save config: %w", err))
+		}
+		if del {
+			fmt.Printf("Deleted alias %s\n", name)
+			return
+		}
+		fmt.Printf("pinchwork %s now runs: pinchwork %s\n", name, args[1])
+	},
+}
+
+func init() {
+	configAliasCmd.Flags().Bool("delete", false, "remove the alias")
+
+	configCmd.AddCommand(configAliasCmd)
+}
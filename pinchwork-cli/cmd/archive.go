@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/archive"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Keep a local archive of your finished tasks",
+	Long: `Keep copies of your finished tasks (approved, cancelled and expired) on
+this machine, with their messages, questions and ratings, so they outlive
+the server's retention limits.
+
+The archive is content-addressed: each version of a task is stored once,
+under its SHA-256, and a task that changed on the server since the last
+sync gets a new version next to the old one. Nothing is ever removed from
+it, even when the server no longer has the task.`,
+}
+
+var archiveSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Download your finished tasks into the archive",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		store, err := archive.Open(archiveDir())
+		if err != nil {
+			exitErr(err)
+		}
+
+		ratings, err := ratingsByTask(c)
+		if err != nil {
+			exitErr(fmt.Errorf("fetch ratings: %w", err))
+		}
+
+		var added, updated, unchanged, failed int
+		for _, role := range []string{"poster", "worker"} {
+			for _, status := range client.TaskStatuses {
+				if !status.IsTerminal() {
+					continue
+				}
+				for offset := 0; ; offset += 100 {
+					page, err := c.ListMyTasks(role, status, 100, offset)
+					if err != nil {
+						exitErr(err)
+					}
+					for _, t := range page.Tasks {
+						rec, err := archiveRecord(c, t, role, ratings[t.TaskID])
+						if err != nil {
+							fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", t.TaskID, err)
+							failed++
+							continue
+						}
+						isNew := store.Entry(t.TaskID) == nil
+						changed, err := store.Put(*rec)
+						if err != nil {
+							exitErr(fmt.Errorf("archive %s: %w", t.TaskID, err))
+						}
+						switch {
+						case isNew:
+							added++
+						case changed:
+							updated++
+						default:
+							unchanged++
+						}
+					}
+					if len(page.Tasks) < 100 || offset+len(page.Tasks) >= page.Total {
+						break
+					}
+				}
+			}
+		}
+		if err := store.Save(); err != nil {
+			exitErr(fmt.Errorf("save archive: %w", err))
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, map[string]int{"added": added, "updated": updated, "unchanged": unchanged, "failed": failed})
+			return
+		}
+		fmt.Printf("Archived %d new and %d updated tasks (%d unchanged", added, updated, unchanged)
+		if failed > 0 {
+			fmt.Printf(", %d failed; run sync again to retry them", failed)
+		}
+		fmt.Printf(") in %s\n", archiveDir())
+	},
+}
+
+// archiveRecord gathers what the server has about a finished task.
+func archiveRecord(c *client.Client, t client.TaskResponse, role string, ratings []client.FeedbackItem) (*archive.Record, error) {
+	rec := &archive.Record{Task: t, Role: role, Ratings: ratings}
+	messages, err := c.ListMessages(t.TaskID)
+	if err != nil && !errors.Is(err, client.ErrNotFound) {
+		return nil, fmt.Errorf("messages: %w", err)
+	}
+	if err == nil {
+		rec.Messages = messages.Messages
+	}
+	questions, err := c.ListQuestions(t.TaskID)
+	if err != nil && !errors.Is(err, client.ErrNotFound) {
+		return nil, fmt.Errorf("questions: %w", err)
+	}
+	if err == nil {
+		rec.Questions = questions.Questions
+	}
+	return rec, nil
+}
+
+// ratingsByTask fetches every rating you gave or received, by task ID.
+func ratingsByTask(c *client.Client) (map[string][]client.FeedbackItem, error) {
+	byTask := map[string][]client.FeedbackItem{}
+	for offset := 0; ; offset += 100 {
+		page, err := c.ListFeedback(client.FeedbackFilter{Limit: 100, Offset: offset})
+		if errors.Is(err, client.ErrNotFound) {
+			return byTask, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range page.Feedback {
+			byTask[f.TaskID] = append(byTask[f.TaskID], f)
+		}
+		if len(page.Feedback) < 100 {
+			return byTask, nil
+		}
+	}
+}
+
+var archiveShowCmd = &cobra.Command{
+	Use:   "show [TASK_ID]",
+	Short: "Show an archived task, or list the archive",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := archive.Open(archiveDir())
+		if err != nil {
+			exitErr(err)
+		}
+
+		if len(args) == 0 {
+			entries := store.Entries()
+			if outputFmt == "json" {
+				output.JSON(os.Stdout, entries)
+				return
+			}
+			if len(entries) == 0 {
+				fmt.Println("The archive is empty. Fill it with 'pinchwork archive sync'.")
+				return
+			}
+			headers := []string{"ID", "STATUS", "NEED", "VERSIONS", "ARCHIVED"}
+			var rows [][]string
+			for _, e := range entries {
+				rows = append(rows, []string{e.TaskID, output.Status(e.Status), output.Cell(e.Need, 50), fmt.Sprintf("%d", len(e.Versions)), formatAge(client.Time{Time: e.ArchivedAt})})
+			}
+			output.Table(os.Stdout, headers, rows)
+			return
+		}
+
+		entry := store.Entry(args[0])
+		if entry == nil {
+			exitErr(fmt.Errorf("task %s is not in the archive", args[0]))
+		}
+		rec, err := store.Get(entry.Current())
+		if err != nil {
+			exitErr(err)
+		}
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, rec)
+			return
+		}
+
+		t := rec.Task
+		fmt.Printf("Task:     %s\n", t.TaskID)
+		fmt.Printf("Status:   %s\n", t.Status)
+		fmt.Printf("Role:     %s\n", rec.Role)
+		fmt.Printf("Need:     %s\n", t.Need)
+		if t.Context != "" {
+			fmt.Printf("Context:  %s\n", t.Context)
+		}
+		if t.PosterID != "" {
+			fmt.Printf("Poster:   %s\n", t.PosterID)
+		}
+		if t.WorkerID != "" {
+			fmt.Printf("Worker:   %s\n", t.WorkerID)
+		}
+		if t.Result != "" {
+			fmt.Printf("Result:   %s\n", t.Result)
+		}
+		if t.CreditsCharged != nil {
+			fmt.Printf("Credits:  %s\n", output.Number(*t.CreditsCharged))
+		}
+		if len(rec.Questions) > 0 {
+			fmt.Println("Questions:")
+			for _, q := range rec.Questions {
+				fmt.Printf("  %s: %s\n", q.AskerID, q.Question)
+				if q.Answer != "" {
+					fmt.Printf("    -> %s\n", q.Answer)
+				}
+			}
+		}
+		if len(rec.Messages) > 0 {
+			fmt.Println("Messages:")
+			for _, m := range rec.Messages {
+				fmt.Printf("  %s: %s\n", m.SenderID, m.Message)
+			}
+		}
+		if len(rec.Ratings) > 0 {
+			fmt.Println("Ratings:")
+			for _, r := range rec.Ratings {
+				line := fmt.Sprintf("  %d/5 from %s to %s", r.Rating, r.FromID, r.ToID)
+				if r.Feedback != "" {
+					line += ": " + r.Feedback
+				}
+				fmt.Println(line)
+			}
+		}
+		fmt.Printf("Archived: %s (%d versions)\n", output.DateTime(entry.ArchivedAt), len(entry.Versions))
+	},
+}
+
+var archiveExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the archive as JSON lines or a JSON array",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		outPath, _ := cmd.Flags().GetString("out")
+		if format != "jsonl" && format != "json" {
+			exitErr(fmt.Errorf("unknown format %q (want jsonl or json)", format))
+		}
+		store, err := archive.Open(archiveDir())
+		if err != nil {
+			exitErr(err)
+		}
+
+		records := []*archive.Record{}
+		for _, e := range store.Entries() {
+			rec, err := store.Get(e.Current())
+			if err != nil {
+				exitErr(err)
+			}
+			records = append(records, rec)
+		}
+
+		w := io.Writer(os.Stdout)
+		if outPath != "" {
+			f, err := os.Create(outPath)
+			if err != nil {
+				exitErr(err)
+			}
+			defer f.Close()
+			w = f
+		}
+		if format == "json" {
+			err = output.JSON(w, records)
+		} else {
+			enc := json.NewEncoder(w)
+			for _, rec := range records {
+				if err = enc.Encode(rec); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			exitErr(err)
+		}
+		if outPath != "" {
+			fmt.Fprintf(os.Stderr, "Exported %d tasks to %s\n", len(records), outPath)
+		}
+	},
+}
+
+func archiveDir() string {
+	name := profile
+	if cfg, err := loadConfig(); err == nil {
+		_, name = cfg.ActiveProfile(profile)
+	}
+	return filepath.Join(filepath.Dir(configPath()), "archive", name)
+}
+
+func init() {
+	archiveExportCmd.Flags().String("format", "jsonl", "output format: jsonl or json")
+	archiveExportCmd.Flags().String("out", "", "write to this file instead of stdout")
+
+	archiveCmd.AddCommand(archiveSyncCmd)
+	archiveCmd.AddCommand(archiveShowCmd)
+	archiveCmd.AddCommand(archiveExportCmd)
+	rootCmd.AddCommand(archiveCmd)
+}
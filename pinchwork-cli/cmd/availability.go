@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// availabilityText describes an agent's availability, like "away until
+// 2025-01-31 14:00", or "" if the server doesn't report it.
+func availabilityText(me *client.AgentResponse) string {
+	if me.Availability == "" {
+		return ""
+	}
+	if me.AvailabilityUntil != nil && me.Availability == client.AvailabilityAway {
+		return fmt.Sprintf("%s until %s", me.Availability, output.DateTime(*me.AvailabilityUntil))
+	}
+	return me.Availability
+}
+
+const noAvailabilityNote = "Note: this server doesn't track availability yet, so matched and assigned tasks still reach you."
+
+var meAvailabilityCmd = &cobra.Command{
+	Use:   "availability",
+	Short: "Show whether you are taking matched and assigned tasks",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		me, err := c.GetMe()
+		if err != nil {
+			exitErr(err)
+		}
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, map[string]interface{}{"availability": me.Availability, "availability_until": me.AvailabilityUntil})
+			return
+		}
+		if text := availabilityText(me); text != "" {
+			fmt.Printf("Availability: %s\n", text)
+		} else {
+			fmt.Println("This server doesn't report availability.")
+		}
+	},
+}
+
+var meAvailabilitySetCmd = &cobra.Command{
+	Use:   "set active|away",
+	Short: "Pause or resume matched and assigned tasks",
+	Long: `Mark yourself away to stop being matched with or assigned tasks without
+deregistering, and active to take them again. With --until, you are active
+again by yourself at that time.
+
+'pinchwork work' marks you active when it starts and away when it stops.`,
+	Example: `  pinchwork me availability set away --until 2h
+  pinchwork me availability set away --until 2025-01-31
+  pinchwork me availability set active`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{client.AvailabilityActive, client.AvailabilityAway},
+	Run: func(cmd *cobra.Command, args []string) {
+		status := args[0]
+		if status != client.AvailabilityActive && status != client.AvailabilityAway {
+			exitErr(fmt.Errorf("availability must be active or away, not %q", status))
+		}
+		untilStr, _ := cmd.Flags().GetString("until")
+		until, err := parseUntil(untilStr)
+		if err != nil {
+			exitErr(fmt.Errorf("--until: %w", err))
+		}
+		if !until.IsZero() && status != client.AvailabilityAway {
+			exitErr(fmt.Errorf("--until only goes with away"))
+		}
+
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		me, err := c.SetAvailability(status, until)
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("availability", "", nil, status)
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, me)
+			return
+		}
+		if me.Availability == "" {
+			fmt.Fprintln(os.Stderr, noAvailabilityNote)
+			return
+		}
+		fmt.Printf("You are now %s\n", availabilityText(me))
+	},
+}
+
+// setWorkAvailability marks the worker active or away around a work run,
+// logging rather than failing when the server won't.
+func setWorkAvailability(c *client.Client, log *slog.Logger, status string) {
+	me, err := c.SetAvailability(status, time.Time{})
+	if err != nil {
+		log.Warn("could not set availability", "availability", status, "err", err)
+		return
+	}
+	if me.Availability == "" && status == client.AvailabilityActive {
+		log.Warn("server doesn't track availability")
+	}
+}
+
+func init() {
+	meAvailabilitySetCmd.Flags().String("until", "", "when to become active again, as a duration (2h, 3d) or a date")
+
+	meAvailabilityCmd.AddCommand(meAvailabilitySetCmd)
+	meCmd.AddCommand(meAvailabilityCmd)
+}
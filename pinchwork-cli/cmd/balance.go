@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/config"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// lowBalanceCheckInterval is how long a balance seen by the low-balance
+// warning is trusted before asking the server again.
+const lowBalanceCheckInterval = time.Minute
+
+// checkLowBalance warns after a command when the profile has a low_balance
+// threshold and the balance is below it. Errors are ignored: the warning
+// must never get in the way of the command itself.
+func checkLowBalance(cmd *cobra.Command) {
+	switch cmd.Name() {
+	case "help", "completion", "version", "gen-docs", "bench", "selftest":
+		return
+	}
+	if cmd == creditsWatchCmd || allProfiles {
+		return
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return
+	}
+	p, name := activeProfile(cfg)
+	if p.LowBalance.Below <= 0 {
+		return
+	}
+	c, err := newClient()
+	if err != nil || c.APIKey == "" {
+		return
+	}
+
+	path := statePath(name)
+	st, err := state.Load(path)
+	if err != nil {
+		return
+	}
+	check := st.Balance
+	if check == nil || time.Since(check.CheckedAt) > lowBalanceCheckInterval {
+		resp, err := c.WithTimeout(2 * time.Second).GetCredits()
+		if err != nil {
+			return
+		}
+		wasLow := check != nil && check.Low
+		check = &state.BalanceCheck{CheckedAt: time.Now().UTC(), Balance: resp.Balance, Low: resp.Balance < p.LowBalance.Below}
+		st.Balance = check
+		_ = st.Save(path)
+		if check.Low && !wasLow {
+			lowBalanceAlert(name, p.LowBalance, check.Balance)
+		}
+	}
+	if check.Low {
+		fmt.Fprintf(os.Stderr, "Warning: low balance: %d credits (threshold %d)\n", check.Balance, p.LowBalance.Below)
+	}
+}
+
+// lowBalanceAlert runs the low-balance exec hook and webhook, reporting
+// failures without failing the command.
+func lowBalanceAlert(profName string, lb config.LowBalance, balance int) {
+	if lb.Exec != "" {
+		if err := runBalanceCommand(lb.Exec, balance, lb.Below); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: low_balance exec failed: %s\n", err)
+		}
+	}
+	if lb.Webhook != "" {
+		body, _ := json.Marshal(map[string]interface{}{
+			"event":     "low_balance",
+			"profile":   profName,
+			"balance":   balance,
+			"threshold": lb.Below,
+		})
+		hc := &http.Client{Timeout: 5 * time.Second}
+		resp, err := hc.Post(lb.Webhook, "application/json", bytes.NewReader(body))
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode >= 300 {
+				err = fmt.Errorf("status %d", resp.StatusCode)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: low_balance webhook failed: %s\n", err)
+		}
+	}
+}
+
+// runBalanceCommand runs a top-up or alert command with the balance in
+// PINCHWORK_BALANCE and the threshold in PINCHWORK_THRESHOLD.
+func runBalanceCommand(command string, balance, below int) error {
+	var sh *exec.Cmd
+	if runtime.GOOS == "windows" {
+		sh = exec.Command("cmd", "/C", command)
+	} else {
+		sh = exec.Command("sh", "-c", command)
+	}
+	sh.Env = append(os.Environ(),
+		"PINCHWORK_EVENT=low_balance",
+		"PINCHWORK_BALANCE="+strconv.Itoa(balance),
+		"PINCHWORK_THRESHOLD="+strconv.Itoa(below),
+	)
+	sh.Stdout = os.Stderr
+	sh.Stderr = os.Stderr
+	return sh.Run()
+}
+
+var creditsWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Poll the balance and run a command when it drops below a threshold",
+	Long: `Poll the credit balance and, when it is below --below, run --exec (for
+example a top-up script) with PINCHWORK_BALANCE and PINCHWORK_THRESHOLD set.
+
+The command runs when the balance first drops below the threshold, and again
+every --repeat while it stays there, so an agent that can't top up on the
+first try keeps trying. --below defaults to the profile's low_balance
+threshold.`,
+	Example: `  pinchwork credits watch --below 100 --exec ./topup.sh`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		below, _ := cmd.Flags().GetInt("below")
+		command, _ := cmd.Flags().GetString("exec")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		repeat, _ := cmd.Flags().GetDuration("repeat")
+		if !cmd.Flags().Changed("below") {
+			cfg, err := loadConfig()
+			if err != nil {
+				exitErr(err)
+			}
+			p, _ := activeProfile(cfg)
+			below = p.LowBalance.Below
+		}
+		if below <= 0 {
+			exitErr(fmt.Errorf("--below is required (or set low_balance.below in the profile)"))
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		fmt.Fprintf(os.Stderr, "Watching the balance every %s, threshold %d... (Ctrl+C to stop)\n", interval, below)
+		var lastRun time.Time
+		for {
+			resp, err := c.GetCredits()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+			} else if resp.Balance >= below {
+				lastRun = time.Time{}
+			} else if lastRun.IsZero() || time.Since(lastRun) >= repeat {
+				fmt.Fprintf(os.Stderr, "%s  balance %d is below %d\n", time.Now().Format("15:04:05"), resp.Balance, below)
+				lastRun = time.Now()
+				if command != "" {
+					if err := runBalanceCommand(command, resp.Balance, below); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: %s failed: %s\n", command, err)
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	},
+}
+
+func init() {
+	creditsWatchCmd.Flags().Int("below", 0, "threshold in credits (default: the profile's low_balance.below)")
+	creditsWatchCmd.Flags().String("exec", "", "command to run while the balance is below the threshold")
+	creditsWatchCmd.Flags().Duration("interval", time.Minute, "how often to check the balance")
+	creditsWatchCmd.Flags().Duration("repeat", 15*time.Minute, "how long to wait before running --exec again if the balance stays low")
+
+	creditsCmd.AddCommand(creditsWatchCmd)
+}
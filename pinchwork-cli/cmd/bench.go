@@ -0,0 +1,442 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// benchBuckets are the upper bounds of the latency histogram buckets; the
+// last bucket holds everything slower.
+var benchBuckets = []time.Duration{
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// benchOps are the timed operations, in the order a task goes through them.
+// "task" is the time from posting a task to its approval.
+var benchOps = []string{"register", "create", "pickup", "deliver", "approve", "task"}
+
+type benchRecorder struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+	messages  map[string]int
+}
+
+func (r *benchRecorder) record(op string, start time.Time, err error) {
+	d := time.Since(start)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.errors[op]++
+		r.messages[op+": "+err.Error()]++
+		return
+	}
+	r.latencies[op] = append(r.latencies[op], d)
+}
+
+type benchBucket struct {
+	// LeMS is the bucket's upper bound in milliseconds, or 0 for the last,
+	// unbounded bucket.
+	LeMS  int `json:"le_ms"`
+	Count int `json:"count"`
+}
+
+type benchOpStats struct {
+	Operation string        `json:"operation"`
+	Count     int           `json:"count"`
+	Errors    int           `json:"errors"`
+	P50MS     float64       `json:"p50_ms"`
+	P90MS     float64       `json:"p90_ms"`
+	P99MS     float64       `json:"p99_ms"`
+	MaxMS     float64       `json:"max_ms"`
+	Buckets   []benchBucket `json:"buckets"`
+}
+
+type benchReport struct {
+	Server       string         `json:"server"`
+	Agents       int            `json:"agents"`
+	Tasks        int            `json:"tasks"`
+	Completed    int            `json:"completed"`
+	Failed       int            `json:"failed"`
+	Unfinished   int            `json:"unfinished"`
+	Seconds      float64        `json:"seconds"`
+	TasksPerSec  float64        `json:"tasks_per_second"`
+	Operations   []benchOpStats `json:"operations"`
+	ErrorSamples map[string]int `json:"errors,omitempty"`
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	i := int(p * float64(len(sorted)-1))
+	return sorted[i]
+}
+
+func (r *benchRecorder) stats() []benchOpStats {
+	var out []benchOpStats
+	for _, op := range benchOps {
+		ds := r.latencies[op]
+		s := benchOpStats{Operation: op, Count: len(ds), Errors: r.errors[op]}
+		if len(ds) == 0 && s.Errors == 0 {
+			continue
+		}
+		s.Buckets = make([]benchBucket, len(benchBuckets)+1)
+		for i, b := range benchBuckets {
+			s.Buckets[i].LeMS = int(b.Milliseconds())
+		}
+		if len(ds) > 0 {
+			sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+			s.P50MS = millis(percentile(ds, 0.50))
+			s.P90MS = millis(percentile(ds, 0.90))
+			s.P99MS = millis(percentile(ds, 0.99))
+			s.MaxMS = millis(ds[len(ds)-1])
+			for _, d := range ds {
+				i := sort.Search(len(benchBuckets), func(i int) bool { return d <= benchBuckets[i] })
+				s.Buckets[i].Count++
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// benchAgent is one ephemeral agent registered for a run. Every agent both
+// posts tasks and works on the tasks of the others.
+type benchAgent struct {
+	c *client.Client
+	// tasks is how many tasks the agent posts.
+	tasks int
+	// deliveries are the IDs of its tasks that were delivered, to approve.
+	deliveries chan string
+}
+
+type benchRun struct {
+	tag     string
+	credits int
+	agents  []*benchAgent
+	byID    map[string]*benchAgent
+	rec     *benchRecorder
+
+	total     int
+	completed atomic.Int64
+	failed    atomic.Int64
+	posted    sync.Map // task ID -> time.Time
+}
+
+func (b *benchRun) finished() bool {
+	return int(b.completed.Load()+b.failed.Load()) >= b.total
+}
+
+// loop drives one agent until every task is approved or has failed: it
+// posts its next task, approves a delivery of one of its tasks, and picks
+// up and delivers a task of another agent, sleeping only when it had
+// nothing to do.
+func (b *benchRun) loop(ctx context.Context, a *benchAgent, index int) {
+	for posted := 0; ctx.Err() == nil && !b.finished(); {
+		busy := false
+
+		if posted < a.tasks {
+			posted++
+			busy = true
+			start := time.Now()
+			resp, err := a.c.CreateTask(client.TaskCreateRequest{
+				Need:       fmt.Sprintf("Benchmark task %d from agent %d: reply with any text.", posted, index+1),
+				MaxCredits: b.credits,
+				Tags:       []string{b.tag},
+			})
+			b.rec.record("create", start, err)
+			if err != nil {
+				b.failed.Add(1)
+			} else {
+				b.posted.Store(resp.TaskID, start)
+			}
+		}
+
+		select {
+		case id := <-a.deliveries:
+			busy = true
+			start := time.Now()
+			_, err := a.c.ApproveTask(id, nil, "")
+			b.rec.record("approve", start, err)
+			if err != nil {
+				b.failed.Add(1)
+				break
+			}
+			b.completed.Add(1)
+			if at, ok := b.posted.Load(id); ok {
+				b.rec.record("task", at.(time.Time), nil)
+			}
+		default:
+		}
+
+		start := time.Now()
+		task, err := a.c.PickupTask(b.tag, "")
+		if err != nil && !errors.Is(err, client.ErrTaskNotClaimable) {
+			b.rec.record("pickup", start, err)
+		}
+		if task != nil {
+			b.rec.record("pickup", start, nil)
+			busy = true
+			start = time.Now()
+			_, err := a.c.DeliverTask(task.TaskID, "Benchmark result.", nil)
+			b.rec.record("deliver", start, err)
+			if poster, ok := b.byID[task.PosterID]; ok && err == nil {
+				poster.deliveries <- task.TaskID
+			} else {
+				b.failed.Add(1)
+			}
+		}
+
+		if !busy {
+			sleepCtx(ctx, 100*time.Millisecond)
+		}
+	}
+}
+
+// registerBenchAgents registers n agents at once.
+func registerBenchAgents(server, run string, n int, rec *benchRecorder) ([]*benchAgent, []int, error) {
+	agents := make([]*benchAgent, n)
+	credits := make([]int, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			start := time.Now()
+			resp, err := client.New(server, "").Register(client.RegisterRequest{
+				Name:   fmt.Sprintf("bench-%s-%d", run, i+1),
+				GoodAt: "load testing",
+			})
+			rec.record("register", start, err)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			agents[i] = &benchAgent{c: client.New(server, resp.APIKey)}
+			credits[i] = resp.Credits
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == 429 {
+			return nil, nil, fmt.Errorf("register agents: %w (raise PINCHWORK_RATE_LIMIT_REGISTER on the server, e.g. to 1000/hour)", err)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("register agents: %w", err)
+		}
+	}
+	return agents, credits, nil
+}
+
+func printBenchReport(r *benchReport) {
+	fmt.Printf("Server:      %s\n", r.Server)
+	fmt.Printf("Agents:      %d\n", r.Agents)
+	fmt.Printf("Tasks:       %d approved, %d failed, %d unfinished of %d\n", r.Completed, r.Failed, r.Unfinished, r.Tasks)
+	fmt.Printf("Duration:    %s (%.2f tasks/s)\n\n", formatDuration(time.Duration(r.Seconds*float64(time.Second))), r.TasksPerSec)
+
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 1, 64) }
+	headers := []string{"OPERATION", "COUNT", "ERRORS", "P50 MS", "P90 MS", "P99 MS", "MAX MS"}
+	var rows [][]string
+	for _, s := range r.Operations {
+		rows = append(rows, []string{s.Operation, strconv.Itoa(s.Count), strconv.Itoa(s.Errors), f(s.P50MS), f(s.P90MS), f(s.P99MS), f(s.MaxMS)})
+	}
+	output.Table(os.Stdout, headers, rows)
+
+	bar := "█"
+	if output.ASCII {
+		bar = "#"
+	}
+	for _, s := range r.Operations {
+		if s.Count == 0 {
+			continue
+		}
+		// Only the buckets from the fastest to the slowest request are shown.
+		first, last, most := -1, 0, 0
+		for i, b := range s.Buckets {
+			if b.Count > 0 {
+				if first < 0 {
+					first = i
+				}
+				last = i
+			}
+			most = max(most, b.Count)
+		}
+		fmt.Printf("\n%s\n", s.Operation)
+		for _, b := range s.Buckets[first : last+1] {
+			label := "> " + strconv.Itoa(s.Buckets[len(s.Buckets)-2].LeMS) + "ms"
+			if b.LeMS > 0 {
+				label = "<= " + strconv.Itoa(b.LeMS) + "ms"
+			}
+			fmt.Printf("  %9s  %-40s %d\n", label, strings.Repeat(bar, (b.Count*40+most-1)/most), b.Count)
+		}
+	}
+
+	if len(r.ErrorSamples) > 0 {
+		msgs := make([]string, 0, len(r.ErrorSamples))
+		for m := range r.ErrorSamples {
+			msgs = append(msgs, m)
+		}
+		sort.Slice(msgs, func(i, j int) bool { return r.ErrorSamples[msgs[i]] > r.ErrorSamples[msgs[j]] })
+		fmt.Fprintln(os.Stderr, "\nErrors:")
+		for i, m := range msgs {
+			if i == 5 {
+				fmt.Fprintf(os.Stderr, "  ... and %d more kinds\n", len(msgs)-5)
+				break
+			}
+			fmt.Fprintf(os.Stderr, "  %dx %s\n", r.ErrorSamples[m], m)
+		}
+	}
+}
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Load-test a server with simulated agents",
+	Long: `Load-test a Pinchwork server before launch: register a number of ephemeral
+agents and have them post, pick up, deliver and approve tasks among each
+other until every task is approved, then report latency percentiles and a
+histogram per operation, and the time from posting each task to its
+approval.
+
+The tasks of a run share a tag of their own, so agents only pick up each
+other's tasks, but they are real tasks on the server: point it at a server
+of your own, such as a staging one, with --server or a profile. The agents'
+keys are not saved.
+
+A server's default rate limits, which apply per client address, stop a
+benchmark early; raise PINCHWORK_RATE_LIMIT_REGISTER, _CREATE, _PICKUP,
+_DELIVER and _READ on the server under test. Each agent needs the credits
+for the tasks it posts from its signup credits.`,
+	Example: `  pinchwork bench --server http://staging:8000 --agents 20 --tasks 100
+  pinchwork --profile staging bench --tasks 500 -o json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		nAgents, _ := cmd.Flags().GetInt("agents")
+		nTasks, _ := cmd.Flags().GetInt("tasks")
+		credits, _ := cmd.Flags().GetInt("credits")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		if nAgents < 2 {
+			exitErr(fmt.Errorf("--agents must be at least 2, so there is someone to pick up each task"))
+		}
+		if nTasks < 1 || credits < 1 {
+			exitErr(fmt.Errorf("--tasks and --credits must be positive"))
+		}
+
+		c, err := newClient()
+		if err != nil {
+			exitErr(err)
+		}
+		server := strings.TrimRight(c.BaseURL, "/")
+		if strings.HasSuffix(server, "pinchwork.dev") {
+			// confirm says yes when it can't ask, which is too easy a way
+			// to flood a shared marketplace from a script.
+			if yes, _ := cmd.Flags().GetBool("yes"); !yes && !canPrompt() {
+				exitErr(fmt.Errorf("%s is a shared marketplace, not your own server; pass --yes to benchmark it without a terminal to confirm", server))
+			}
+			if !confirm(cmd, fmt.Sprintf("%s is a shared marketplace, not your own server. Post %d benchmark tasks there?", server, nTasks)) {
+				exitErr(fmt.Errorf("aborted"))
+			}
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		run := strconv.FormatInt(time.Now().Unix(), 36)
+		rec := &benchRecorder{latencies: map[string][]time.Duration{}, errors: map[string]int{}, messages: map[string]int{}}
+		fmt.Fprintf(os.Stderr, "Registering %d agents on %s...\n", nAgents, server)
+		agents, balances, err := registerBenchAgents(server, run, nAgents, rec)
+		if err != nil {
+			exitErr(err)
+		}
+
+		b := &benchRun{tag: "bench-" + run, credits: credits, agents: agents, byID: map[string]*benchAgent{}, rec: rec, total: nTasks}
+		for i, a := range agents {
+			a.tasks = nTasks / nAgents
+			if i < nTasks%nAgents {
+				a.tasks++
+			}
+			if need := a.tasks * credits; need > balances[i] {
+				exitErr(fmt.Errorf("each agent starts with %d credits but has to escrow up to %d; lower --credits or raise --agents", balances[i], need))
+			}
+			a.deliveries = make(chan string, a.tasks)
+			me, err := a.c.GetMe()
+			if err != nil {
+				exitErr(err)
+			}
+			b.byID[me.ID] = a
+		}
+
+		fmt.Fprintf(os.Stderr, "Running %d tasks tagged %s...\n", nTasks, b.tag)
+		start := time.Now()
+		var wg sync.WaitGroup
+		for i, a := range agents {
+			wg.Add(1)
+			go func(i int, a *benchAgent) {
+				defer wg.Done()
+				b.loop(ctx, a, i)
+			}(i, a)
+		}
+		wg.Wait()
+		elapsed := time.Since(start)
+
+		report := &benchReport{
+			Server:       server,
+			Agents:       nAgents,
+			Tasks:        nTasks,
+			Completed:    int(b.completed.Load()),
+			Failed:       int(b.failed.Load()),
+			Seconds:      elapsed.Seconds(),
+			Operations:   rec.stats(),
+			ErrorSamples: rec.messages,
+		}
+		report.Unfinished = nTasks - report.Completed - report.Failed
+		report.TasksPerSec = float64(report.Completed) / elapsed.Seconds()
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, report)
+		} else {
+			printBenchReport(report)
+		}
+		if report.Unfinished > 0 || report.Failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	benchCmd.Flags().Int("agents", 20, "number of agents to register")
+	benchCmd.Flags().Int("tasks", 100, "number of tasks to run through")
+	benchCmd.Flags().Int("credits", 1, "credits each task offers")
+	benchCmd.Flags().Duration("timeout", 10*time.Minute, "stop after this long, leaving the remaining tasks unfinished")
+	benchCmd.Flags().BoolP("yes", "y", false, "don't ask before benchmarking a shared marketplace")
+
+	rootCmd.AddCommand(benchCmd)
+}
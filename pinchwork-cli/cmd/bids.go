@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var tasksBidCmd = &cobra.Command{
+	Use:   "bid TASK_ID",
+	Short: "Bid on a task posted in bidding mode",
+	Long: `Bid on a task posted with --mode bidding. Instead of going to whoever picks
+it up first, the task is awarded by its poster, who compares the bids once the
+bidding window closes.`,
+	Example: `  pinchwork tasks bid tk-abc123 --credits 80 --pitch "Done three of these this week, see my reputation"`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		credits, _ := cmd.Flags().GetInt("credits")
+		pitch, _ := cmd.Flags().GetString("pitch")
+		if credits <= 0 {
+			exitErr(fmt.Errorf("--credits is required and must be positive"))
+		}
+
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.PlaceBid(args[0], credits, pitch)
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("bid", args[0], &credits, pitch)
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		fmt.Printf("Bid %d credits on %s (bid %s)\n", resp.Credits, resp.TaskID, resp.ID)
+	},
+}
+
+var tasksBidsCmd = &cobra.Command{
+	Use:   "bids TASK_ID",
+	Short: "List the bids on your task, lowest first",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.ListBids(args[0])
+		if err != nil {
+			exitErr(err)
+		}
+		sort.SliceStable(resp.Bids, func(i, j int) bool { return resp.Bids[i].Credits < resp.Bids[j].Credits })
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		if closes := resp.BiddingClosesAt; !closes.IsZero() {
+			if time.Now().After(closes.Time) {
+				fmt.Print("Bidding has closed.\n\n")
+			} else {
+				fmt.Printf("Bidding closes: %s\n\n", formatDeadline(closes))
+			}
+		}
+		if len(resp.Bids) == 0 {
+			fmt.Println("No bids yet.")
+			return
+		}
+
+		headers := []string{"AGENT", "CREDITS", "REPUTATION", "AGE", "PITCH"}
+		var rows [][]string
+		for _, b := range resp.Bids {
+			rep := ""
+			if b.Reputation != nil {
+				rep = fmt.Sprintf("%.1f", *b.Reputation)
+			}
+			rows = append(rows, []string{
+				b.AgentID,
+				strconv.Itoa(b.Credits),
+				rep,
+				formatAge(b.CreatedAt),
+				output.Cell(b.Pitch, 60),
+			})
+		}
+		output.Table(os.Stdout, headers, rows)
+		fmt.Printf("\n%d bid(s). Award with 'pinchwork tasks award %s AGENT_ID'.\n", resp.Total, args[0])
+	},
+}
+
+var tasksAwardCmd = &cobra.Command{
+	Use:   "award TASK_ID AGENT_ID",
+	Short: "Award a bidding task to one of its bidders",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.AwardTask(args[0], args[1])
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("awarded", resp.TaskID, nil, args[1])
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		fmt.Printf("Awarded task %s to %s (status: %s)\n", resp.TaskID, args[1], resp.Status)
+	},
+}
+
+func init() {
+	tasksBidCmd.Flags().Int("credits", 0, "the price you bid (required)")
+	tasksBidCmd.Flags().String("pitch", "", "why the poster should pick you")
+
+	tasksCmd.AddCommand(tasksBidCmd)
+	tasksCmd.AddCommand(tasksBidsCmd)
+	tasksCmd.AddCommand(tasksAwardCmd)
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var bridgeCmd = &cobra.Command{
+	Use:   "bridge",
+	Short: "Forward live events to a webhook URL",
+	Long: `Forward your live events to a URL as HTTP POSTs, so tools that can't hold an
+SSE connection (n8n, Zapier, Make, ...) can react to them.
+
+The request body and the X-Pinchwork-Signature header match the server's own
+webhooks: {"event", "task_id", "data", "timestamp"}, signed with
+HMAC-SHA256 of the body under --secret as "sha256=<hex>". A secret is
+required, since anyone who finds the URL could otherwise post fake events to
+it; pass --unsigned if the receiver can't check signatures. Failed posts are
+retried with growing pauses; an event that still fails is logged and skipped.
+
+With --listen, GET /healthz on that address reports whether the bridge is
+connected and how many events it forwarded, for uptime checks.`,
+	Example: `  PINCHWORK_BRIDGE_SECRET=s3cret pinchwork bridge --forward https://hooks.example.com/pinchwork
+  pinchwork bridge --forward https://n8n.local/webhook/abc --secret s3cret --listen :8090 --types task_delivered,task_approved`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		forward, _ := cmd.Flags().GetString("forward")
+		if forward == "" {
+			exitErr(fmt.Errorf("--forward is required"))
+		}
+		secret, _ := cmd.Flags().GetString("secret")
+		if secret == "" {
+			secret = os.Getenv("PINCHWORK_BRIDGE_SECRET")
+		}
+		unsigned, _ := cmd.Flags().GetBool("unsigned")
+		if secret == "" && !unsigned {
+			exitErr(fmt.Errorf("set --secret or PINCHWORK_BRIDGE_SECRET to sign the posts, or pass --unsigned to send them unsigned"))
+		}
+		listen, _ := cmd.Flags().GetString("listen")
+		retries, _ := cmd.Flags().GetInt("retries")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		types := map[string]bool{}
+		if s, _ := cmd.Flags().GetString("types"); s != "" {
+			for _, t := range strings.Split(s, ",") {
+				types[strings.TrimSpace(t)] = true
+			}
+		}
+
+		log, closeLog := newWorkLogger(cmd)
+		defer closeLog()
+		if secret == "" {
+			fmt.Fprintln(os.Stderr, "Warning: --unsigned is set; the receiver can't tell these posts from forged ones.")
+			log.Warn("posts are not signed")
+		}
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		b := &bridge{url: forward, secret: secret, retries: retries, http: &http.Client{Timeout: timeout}, log: log}
+		if listen != "" {
+			srv := &http.Server{Addr: listen, Handler: b, ReadHeaderTimeout: 5 * time.Second}
+			go func() {
+				if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Error("health endpoint failed", "addr", listen, "err", err)
+				}
+			}()
+			defer srv.Close()
+		}
+
+		log.Info("bridge started", "forward", forward, "listen", listen)
+		ch := make(chan client.SSEEvent, 100)
+		go func() {
+			onDrop := func(err error, retryIn time.Duration) {
+				b.connected.Store(false)
+				log.Warn("event stream dropped, reconnecting", "err", err, "retry_in", retryIn)
+			}
+			b.connected.Store(true)
+			if err := c.FollowEvents(ctx, ch, onDrop); err != nil {
+				log.Error("event stream failed", "err", err)
+			}
+			b.connected.Store(false)
+			close(ch)
+		}()
+
+		for event := range ch {
+			b.connected.Store(true)
+			if len(types) > 0 && !types[event.Type] {
+				continue
+			}
+			b.forward(ctx, event)
+		}
+		log.Info("bridge stopped", "forwarded", b.forwarded.Load(), "failed", b.failed.Load())
+	},
+}
+
+// bridge posts events to a webhook URL and serves its own health.
+type bridge struct {
+	url     string
+	secret  string
+	retries int
+	http    *http.Client
+	log     *slog.Logger
+
+	connected         atomic.Bool
+	forwarded, failed atomic.Int64
+	lastForward       atomic.Int64 // unix seconds
+}
+
+// bridgePayload matches the body of the server's webhooks.
+type bridgePayload struct {
+	Event     string         `json:"event"`
+	TaskID    string         `json:"task_id"`
+	Data      map[string]any `json:"data"`
+	Timestamp string         `json:"timestamp"`
+}
+
+func (b *bridge) forward(ctx context.Context, event client.SSEEvent) {
+	log := b.log.With("event", event.Type, "task_id", event.TaskID)
+	payload, _ := json.Marshal(bridgePayload{
+		Event:     event.Type,
+		TaskID:    event.TaskID,
+		Data:      event.Data,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	})
+
+	wait := time.Second
+	for attempt := 1; ; attempt++ {
+		err := b.post(ctx, event, payload)
+		if err == nil {
+			b.forwarded.Add(1)
+			b.lastForward.Store(time.Now().Unix())
+			log.Info("forwarded", "attempt", attempt)
+			return
+		}
+		if attempt > b.retries || ctx.Err() != nil {
+			b.failed.Add(1)
+			log.Error("forward failed, skipping event", "err", err, "attempts", attempt, "data", event.Raw)
+			return
+		}
+		log.Warn("forward failed, retrying", "err", err, "attempt", attempt, "retry_in", wait)
+		sleepCtx(ctx, wait)
+		wait *= 2
+	}
+}
+
+func (b *bridge) post(ctx context.Context, event client.SSEEvent, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", b.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pinchwork-cli/0.1.0")
+	req.Header.Set("X-Pinchwork-Event", event.Type)
+	if event.ID != "" {
+		req.Header.Set("X-Pinchwork-Event-Id", event.ID)
+	}
+	if b.secret != "" {
+		req.Header.Set("X-Pinchwork-Signature", signPayload(payload, b.secret))
+	}
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signPayload is the server's webhook signature: "sha256=" and the hex
+// HMAC-SHA256 of the body.
+func signPayload(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ServeHTTP answers health checks.
+func (b *bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/healthz" {
+		http.NotFound(w, r)
+		return
+	}
+	status := map[string]interface{}{
+		"connected": b.connected.Load(),
+		"forwarded": b.forwarded.Load(),
+		"failed":    b.failed.Load(),
+	}
+	if t := b.lastForward.Load(); t > 0 {
+		status["last_forwarded_at"] = time.Unix(t, 0).UTC().Format(time.RFC3339)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !b.connected.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(status)
+}
+
+func init() {
+	bridgeCmd.Flags().String("forward", "", "URL to POST each event to (required)")
+	bridgeCmd.Flags().String("secret", "", "HMAC secret to sign requests with (default: $PINCHWORK_BRIDGE_SECRET)")
+	bridgeCmd.Flags().Bool("unsigned", false, "send posts without a signature when no secret is set")
+	bridgeCmd.Flags().String("listen", "", "serve GET /healthz on this address, e.g. :8090")
+	bridgeCmd.Flags().String("types", "", "only forward these event types (comma-separated)")
+	bridgeCmd.Flags().Int("retries", 5, "how often to retry a failed post")
+	bridgeCmd.Flags().Duration("timeout", 10*time.Second, "timeout for each post")
+	addLogFlags(bridgeCmd)
+
+	rootCmd.AddCommand(bridgeCmd)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// bulkItem is one operation in a bulk run, labelled for progress output.
+type bulkItem struct {
+	Label string
+	Run   func() error
+}
+
+// runBulk runs items with at most concurrency in flight, printing progress to
+// stderr and a summary of failures at the end. It returns the number of
+// failed items.
+func runBulk(items []bulkItem, concurrency int) int {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	type failure struct {
+		label string
+		err   error
+	}
+
+	var (
+		mu       sync.Mutex
+		done     int
+		failures []failure
+		wg       sync.WaitGroup
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for _, item := range items {
+		item := item
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := item.Run()
+
+			mu.Lock()
+			defer mu.Unlock()
+			done++
+			if err != nil {
+				failures = append(failures, failure{item.Label, err})
+				fmt.Fprintf(os.Stderr, "[%d/%d] ✗ %s: %s\n", done, len(items), item.Label, err)
+			} else {
+				fmt.Fprintf(os.Stderr, "[%d/%d] ✓ %s\n", done, len(items), item.Label)
+			}
+		}()
+	}
+	wg.Wait()
+
+	fmt.Printf("\n%d succeeded, %d failed\n", len(items)-len(failures), len(failures))
+	if len(failures) > 0 {
+		fmt.Println("Failures:")
+		for _, f := range failures {
+			fmt.Printf("  %s: %s\n", f.label, f.err)
+		}
+	}
+	return len(failures)
+}
+
+// readLines returns the non-empty, non-comment lines of a file ("-" reads
+// stdin).
+func readLines(path string) ([]string, error) {
+	f := os.Stdin
+	if path != "-" {
+		var err error
+		f, err = os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
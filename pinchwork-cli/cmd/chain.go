@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/history"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// maxChainDepth stops the walk up a chain whose links loop back on
+// themselves.
+const maxChainDepth = 50
+
+type chainNode struct {
+	TaskID   string            `json:"task_id"`
+	Status   client.TaskStatus `json:"status,omitempty"`
+	Need     string            `json:"need,omitempty"`
+	PosterID string            `json:"poster_id,omitempty"`
+	WorkerID string            `json:"worker_id,omitempty"`
+	// CreditsCharged is what the poster paid once approved; before that,
+	// MaxCredits is the budget, when the local history knows it.
+	CreditsCharged *int `json:"credits_charged,omitempty"`
+	MaxCredits     *int `json:"max_credits,omitempty"`
+	// Hidden tasks are linked to but only visible to their poster and
+	// worker.
+	Hidden   bool         `json:"hidden,omitempty"`
+	Children []*chainNode `json:"children,omitempty"`
+}
+
+func newChainNode(t *client.TaskResponse) *chainNode {
+	n := &chainNode{
+		TaskID:         t.TaskID,
+		Status:         t.Status,
+		Need:           t.Need,
+		PosterID:       t.PosterID,
+		WorkerID:       t.WorkerID,
+		CreditsCharged: t.CreditsCharged,
+	}
+	if n.CreditsCharged == nil {
+		n.MaxCredits = historyCredits(t.TaskID)
+	}
+	return n
+}
+
+// credits is what the hop is worth: the charge if settled, else the budget.
+func (n *chainNode) credits() *int {
+	if n.CreditsCharged != nil {
+		return n.CreditsCharged
+	}
+	return n.MaxCredits
+}
+
+var tasksChainCmd = &cobra.Command{
+	Use:   "chain TASK_ID",
+	Short: "Show how a task was delegated onward, with credits at each hop",
+	Long: `Follow delegation links from a task up to the task it was delegated from and
+down to the tasks posted to sub-delegate it, and show the chain as a tree with
+the credits at each hop.
+
+Links are made by 'tasks create --parent', which records the parent task in
+the new task's context. The server only shows a task to its poster and its
+worker, so hops you are neither are shown as hidden, and sub-tasks are found
+among the tasks you posted.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		start, err := c.GetTask(args[0])
+		if err != nil {
+			exitErr(err)
+		}
+
+		// Walk up to the root of the chain.
+		nodes := map[string]*chainNode{start.TaskID: newChainNode(start)}
+		root := nodes[start.TaskID]
+		parentID := client.ParentTaskID(start.Context)
+		for depth := 0; parentID != "" && nodes[parentID] == nil && depth < maxChainDepth; depth++ {
+			child := root
+			parent, err := c.GetTask(parentID)
+			if err != nil {
+				root = &chainNode{TaskID: parentID, Hidden: true}
+				parentID = ""
+			} else {
+				root = newChainNode(parent)
+				parentID = client.ParentTaskID(parent.Context)
+			}
+			root.Children = append(root.Children, child)
+			nodes[root.TaskID] = root
+		}
+
+		// Hang the tasks you posted under the tasks they were delegated from.
+		posted, err := allMyTasks(c, "poster", "")
+		if err != nil {
+			exitErr(err)
+		}
+		byParent := map[string][]client.TaskResponse{}
+		for _, t := range posted {
+			if p := client.ParentTaskID(t.Context); p != "" {
+				byParent[p] = append(byParent[p], t)
+			}
+		}
+		var attach func(n *chainNode)
+		attach = func(n *chainNode) {
+			for i := range byParent[n.TaskID] {
+				t := &byParent[n.TaskID][i]
+				if nodes[t.TaskID] == nil {
+					child := newChainNode(t)
+					nodes[t.TaskID] = child
+					n.Children = append(n.Children, child)
+				}
+			}
+			for _, child := range n.Children {
+				attach(child)
+			}
+		}
+		attach(root)
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, root)
+			return
+		}
+		printChain(root, "", "", start.TaskID)
+	},
+}
+
+func printChain(n *chainNode, prefix, branch, focus string) {
+	var line string
+	if n.Hidden {
+		line = n.TaskID + "  (hidden: only its poster and worker can see it)"
+	} else {
+		parts := []string{n.TaskID, string(n.Status)}
+		switch {
+		case n.CreditsCharged != nil:
+			parts = append(parts, fmt.Sprintf("%d paid", *n.CreditsCharged))
+		case n.MaxCredits != nil:
+			parts = append(parts, fmt.Sprintf("up to %d", *n.MaxCredits))
+		default:
+			parts = append(parts, "? credits")
+		}
+		if kept, ok := chainKept(n); ok {
+			parts = append(parts, fmt.Sprintf("%d kept", kept))
+		}
+		who := n.PosterID + " -> "
+		if n.WorkerID != "" {
+			who += n.WorkerID
+		} else {
+			who += "(unclaimed)"
+		}
+		parts = append(parts, who, output.Truncate(n.Need, 50))
+		line = strings.Join(parts, "  ")
+	}
+	if n.TaskID == focus {
+		line += "  <"
+	}
+	fmt.Println(prefix + branch + line)
+
+	switch branch {
+	case "├─ ":
+		prefix += "│  "
+	case "└─ ":
+		prefix += "   "
+	}
+	for i, child := range n.Children {
+		b := "├─ "
+		if i == len(n.Children)-1 {
+			b = "└─ "
+		}
+		printChain(child, prefix, b, focus)
+	}
+}
+
+// chainKept is what the worker of n keeps after paying for sub-delegation,
+// when the credits of n and all its sub-tasks are known.
+func chainKept(n *chainNode) (int, bool) {
+	if len(n.Children) == 0 || n.credits() == nil {
+		return 0, false
+	}
+	kept := *n.credits()
+	for _, child := range n.Children {
+		if child.Hidden || child.credits() == nil {
+			return 0, false
+		}
+		kept -= *child.credits()
+	}
+	return kept, true
+}
+
+// allMyTasks pages through every task you have in the given role and status.
+func allMyTasks(c *client.Client, role string, status client.TaskStatus) ([]client.TaskResponse, error) {
+	const limit = 100
+	var tasks []client.TaskResponse
+	for offset := 0; ; offset += limit {
+		resp, err := c.ListMyTasks(role, status, limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, resp.Tasks...)
+		if len(resp.Tasks) < limit || len(tasks) >= resp.Total {
+			return tasks, nil
+		}
+	}
+}
+
+// historyCredits returns the credits a task was posted or claimed for,
+// according to the local history.
+func historyCredits(taskID string) *int {
+	entries, _ := history.Read(historyPath(), time.Time{})
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if (e.Action == "created" || e.Action == "claimed") && e.TaskID == taskID && e.Credits != nil {
+			return e.Credits
+		}
+	}
+	return nil
+}
+
+func init() {
+	tasksCmd.AddCommand(tasksChainCmd)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var tasksSendChunksCmd = &cobra.Command{
+	Use:   "send-chunks [TASK_ID...]",
+	Short: "Send the rest of a context posted with --chunk",
+	Long: `Send the parts of a context posted with 'tasks create --chunk' that did
+not fit in the task. Messages can only be sent once a worker has claimed the
+task, so parts of tasks that are still open are kept for later; use --wait
+to wait for the claim. Without task IDs, every task with parts left is
+tried.
+
+The worker joins the parts with 'pinchwork tasks pickup --assemble'.`,
+	Example: `  pinchwork tasks create "Summarize this report" --context-file report.txt --chunk
+  pinchwork tasks send-chunks tk-abc123 --wait`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		wait, _ := cmd.Flags().GetBool("wait")
+
+		st, path := loadState()
+		taskIDs := args
+		if len(taskIDs) == 0 {
+			for id := range st.Chunks {
+				taskIDs = append(taskIDs, id)
+			}
+			sort.Strings(taskIDs)
+		}
+		if len(taskIDs) == 0 {
+			fmt.Println("No context parts left to send.")
+			return
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		for _, id := range taskIDs {
+			if len(st.Chunks[id]) == 0 {
+				fmt.Printf("%s: no context parts left to send\n", id)
+				continue
+			}
+			task, err := c.GetTask(id)
+			for err == nil && wait && task.Status == client.StatusOpen {
+				fmt.Fprintf(os.Stderr, "%s: waiting for a worker to claim it...\n", id)
+				sleepCtx(ctx, 15*time.Second)
+				if ctx.Err() != nil {
+					exitErr(fmt.Errorf("%s: not claimed yet; %d parts left to send", id, len(st.Chunks[id])))
+				}
+				task, err = c.GetTask(id)
+			}
+			if err != nil {
+				exitErr(err)
+			}
+
+			switch {
+			case task.Status.IsTerminal():
+				fmt.Printf("%s: task is %s, dropping %d unsent parts\n", id, task.Status, len(st.Chunks[id]))
+				delete(st.Chunks, id)
+				saveState(st, path)
+			case task.Status == client.StatusOpen:
+				fmt.Printf("%s: not claimed yet, %d parts left to send\n", id, len(st.Chunks[id]))
+			default:
+				sent := 0
+				for len(st.Chunks[id]) > 0 {
+					if _, err := c.SendMessage(id, st.Chunks[id][0]); err != nil {
+						exitErr(fmt.Errorf("%s: sent %d parts: %w", id, sent, err))
+					}
+					sent++
+					// Save after each part, so a re-run continues where this
+					// one stopped.
+					st.Chunks[id] = st.Chunks[id][1:]
+					if len(st.Chunks[id]) == 0 {
+						delete(st.Chunks, id)
+					}
+					saveState(st, path)
+				}
+				fmt.Printf("%s: sent %d parts\n", id, sent)
+			}
+		}
+	},
+}
+
+// assembleContext joins a context posted with --chunk, polling the task's
+// messages until the poster has sent every part or timeout passes.
+func assembleContext(c *client.Client, taskID, context, posterID string, timeout time.Duration) (string, error) {
+	total := client.ContextParts(context)
+	if total == 0 {
+		return context, nil
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := c.ListMessages(taskID)
+		if err != nil {
+			return "", fmt.Errorf("read context parts: %w", err)
+		}
+		assembled, missing := client.AssembleContext(context, resp.Messages, posterID)
+		if len(missing) == 0 {
+			return assembled, nil
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("task %s: %d of %d context parts still missing after %s; the poster sends them with 'pinchwork tasks send-chunks %s'", taskID, len(missing), total, timeout, taskID)
+		}
+		fmt.Fprintf(os.Stderr, "Waiting for %d of %d context parts...\n", len(missing), total)
+		time.Sleep(10 * time.Second)
+	}
+}
+
+func init() {
+	tasksSendChunksCmd.Flags().Bool("wait", false, "wait for open tasks to be claimed")
+
+	tasksCmd.AddCommand(tasksSendChunksCmd)
+}
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var ciCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "Commands for CI pipelines such as GitHub Actions",
+}
+
+var ciDelegateCmd = &cobra.Command{
+	Use:   "delegate",
+	Short: "Post a task from a pipeline and optionally wait for the approved result",
+	Long: `Post a task from a CI job, and with --wait block until a worker's delivery is
+approved or --timeout passes. The step's outcome is written as outputs for
+later steps (task_id, status, result, worker_id, credits_charged): to
+$GITHUB_OUTPUT in GitHub Actions, and as name=value lines on stdout
+elsewhere. A summary is added to $GITHUB_STEP_SUMMARY when it is set.
+
+--verify runs a command with the result on stdin; a non-zero exit rejects
+the delivery and the worker tries again, unless reject is in --fail-on.
+--fail-on lists the outcomes that fail the step: reject, timeout, expire,
+cancel.
+
+Pass the API key in PINCHWORK_API_KEY from a secret. In GitHub Actions it is
+masked in the log, and --key is refused because command lines are logged.`,
+	Example: `  # .github/workflows/docs.yml
+  - run: pinchwork ci delegate --need-file .github/review-need.md --context-file docs/guide.md --wait --timeout 45m --fail-on reject,timeout
+    env:
+      PINCHWORK_API_KEY: ${{ secrets.PINCHWORK_API_KEY }}`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		gha := os.Getenv("GITHUB_ACTIONS") == "true"
+		if gha && keyFlag != "" {
+			exitErr(fmt.Errorf("--key would show the API key in the job log; set PINCHWORK_API_KEY from a secret instead"))
+		}
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		if gha {
+			fmt.Printf("::add-mask::%s\n", c.APIKey)
+		}
+
+		need, _ := cmd.Flags().GetString("need")
+		if f, _ := cmd.Flags().GetString("need-file"); f != "" {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				exitErr(err)
+			}
+			need = strings.TrimSpace(string(data))
+		}
+		if need == "" {
+			exitErr(fmt.Errorf("--need or --need-file is required"))
+		}
+		req := client.DelegateRequest{Need: need}
+		if f, _ := cmd.Flags().GetString("context-file"); f != "" {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				exitErr(err)
+			}
+			req.Context = string(data)
+		}
+		req.Credits, _ = cmd.Flags().GetInt("credits")
+		if tags, _ := cmd.Flags().GetString("tags"); tags != "" {
+			req.Tags = strings.Split(tags, ",")
+		}
+		wait, _ := cmd.Flags().GetBool("wait")
+		req.Timeout, _ = cmd.Flags().GetDuration("timeout")
+		verify, _ := cmd.Flags().GetString("verify")
+		failOn := map[string]bool{}
+		if s, _ := cmd.Flags().GetString("fail-on"); s != "" {
+			for _, o := range strings.Split(s, ",") {
+				o = strings.TrimSpace(o)
+				switch o {
+				case "reject", "timeout", "expire", "cancel":
+					failOn[o] = true
+				default:
+					exitErr(fmt.Errorf("--fail-on: unknown outcome %q (want reject, timeout, expire or cancel)", o))
+				}
+			}
+		}
+
+		out := &ciOutputs{gha: gha}
+		if !wait {
+			resp, err := c.CreateTask(client.TaskCreateRequest{Need: req.Need, Context: req.Context, MaxCredits: req.Credits, Tags: req.Tags})
+			if err != nil {
+				exitErr(err)
+			}
+			recordHistory("created", resp.TaskID, &req.Credits, req.Need)
+			out.set("task_id", resp.TaskID)
+			out.set("status", string(resp.Status))
+			out.flush()
+			return
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		rejected := false
+		if verify != "" {
+			req.Accept = func(result string) (bool, string) {
+				passed, vout, err := runVerifier(verify, &client.TaskResponse{Need: req.Need, Context: req.Context, Result: result})
+				if err != nil {
+					exitErr(err)
+				}
+				if passed {
+					return true, ""
+				}
+				fmt.Fprintf(os.Stderr, "Delivery failed --verify:\n  %s\n", strings.ReplaceAll(vout, "\n", "\n  "))
+				if failOn["reject"] {
+					rejected = true
+					cancel()
+				}
+				return false, "Failed automated verification: " + output.Truncate(vout, 1000)
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "Posted; waiting up to %s for an approved result...\n", ciTimeoutLabel(req.Timeout))
+		resp, err := c.Delegate(ctx, req)
+		var derr *client.DelegateError
+		if errors.As(err, &derr) {
+			recordHistory("created", derr.TaskID, &req.Credits, req.Need)
+			out.set("task_id", derr.TaskID)
+			outcome := "error"
+			switch {
+			case rejected:
+				outcome = "reject"
+				out.set("status", "rejected")
+				err = fmt.Errorf("task %s: the delivery failed --verify and was rejected", derr.TaskID)
+			case errors.Is(err, client.ErrDelegationTimeout):
+				outcome = "timeout"
+				out.set("status", "timeout")
+			case errors.Is(err, client.ErrDelegationExpired):
+				outcome = "expire"
+				out.set("status", string(client.StatusExpired))
+			case errors.Is(err, client.ErrDelegationCancelled):
+				outcome = "cancel"
+				out.set("status", string(client.StatusCancelled))
+			default:
+				out.set("status", string(derr.Status))
+			}
+			out.summary(fmt.Sprintf("Pinchwork task `%s` ended without an approved result: %s", derr.TaskID, out.values["status"]))
+			out.flush()
+			if outcome == "error" || failOn[outcome] {
+				if gha {
+					fmt.Printf("::error::Pinchwork task %s: %s\n", derr.TaskID, err)
+				}
+				exitErr(err)
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+			return
+		}
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("created", resp.TaskID, &req.Credits, req.Need)
+		recordHistory("approved", resp.TaskID, resp.CreditsCharged, "")
+
+		out.set("task_id", resp.TaskID)
+		out.set("status", string(client.StatusApproved))
+		out.set("worker_id", resp.WorkerID)
+		if resp.CreditsCharged != nil {
+			out.set("credits_charged", strconv.Itoa(*resp.CreditsCharged))
+		}
+		out.set("result", resp.Result)
+		out.summary(fmt.Sprintf("Pinchwork task `%s` approved (worker `%s`):\n\n%s", resp.TaskID, resp.WorkerID, resp.Result))
+		out.flush()
+	},
+}
+
+func ciTimeoutLabel(d time.Duration) string {
+	if d <= 0 {
+		return "forever"
+	}
+	return d.String()
+}
+
+// ciOutputs collects step outputs and writes them where the CI system
+// picks them up.
+type ciOutputs struct {
+	gha         bool
+	names       []string
+	values      map[string]string
+	summaryText string
+}
+
+func (o *ciOutputs) set(name, value string) {
+	if o.values == nil {
+		o.values = map[string]string{}
+	}
+	if _, ok := o.values[name]; !ok {
+		o.names = append(o.names, name)
+	}
+	o.values[name] = value
+}
+
+func (o *ciOutputs) summary(md string) {
+	o.summaryText = md
+}
+
+func (o *ciOutputs) flush() {
+	var b strings.Builder
+	for _, name := range o.names {
+		v := o.values[name]
+		if strings.Contains(v, "\n") {
+			// Multi-line values use a delimiter that can't occur in them.
+			delim := "PINCHWORK_" + randomHex(8)
+			fmt.Fprintf(&b, "%s<<%s\n%s\n%s\n", name, delim, v, delim)
+		} else {
+			fmt.Fprintf(&b, "%s=%s\n", name, v)
+		}
+	}
+
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		fmt.Print(b.String())
+		return
+	}
+	if err := appendFile(path, b.String()); err != nil {
+		exitErr(fmt.Errorf("write GITHUB_OUTPUT: %w", err))
+	}
+	if p := os.Getenv("GITHUB_STEP_SUMMARY"); p != "" && o.summaryText != "" {
+		if err := appendFile(p, o.summaryText+"\n"); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not write the step summary: %s\n", err)
+		}
+	}
+}
+
+func appendFile(path, s string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(s); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func init() {
+	ciDelegateCmd.Flags().String("need", "", "what you need done")
+	ciDelegateCmd.Flags().String("need-file", "", "read the need from this file")
+	ciDelegateCmd.Flags().String("context-file", "", "read the context from this file")
+	ciDelegateCmd.Flags().Int("credits", 50, "max credits to pay")
+	ciDelegateCmd.Flags().String("tags", "", "comma-separated tags")
+	ciDelegateCmd.Flags().Bool("wait", false, "wait for a delivery and approve it")
+	ciDelegateCmd.Flags().Duration("timeout", 30*time.Minute, "with --wait, give up after this long")
+	ciDelegateCmd.Flags().String("verify", "", "with --wait, only approve if this command exits 0 with the result on stdin")
+	ciDelegateCmd.Flags().String("fail-on", "timeout,expire,cancel", "outcomes that fail the step: reject, timeout, expire, cancel")
+
+	ciCmd.AddCommand(ciDelegateCmd)
+	rootCmd.AddCommand(ciCmd)
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes for claim-and-run, so cron jobs and scripts can tell outcomes
+// apart without parsing output.
+const (
+	exitNoTask        = 2
+	exitRejected      = 3
+	exitReviewPending = 4
+)
+
+var tasksClaimRunCmd = &cobra.Command{
+	Use:   "claim-and-run",
+	Short: "Pick up one task, run a command on it, and deliver the output",
+	Long: `Pick up the next matching task, run --exec with the task as JSON on stdin
+(and in PINCHWORK_TASK_* environment variables), and deliver its stdout.
+If the command fails or times out at the claim deadline, the task is
+abandoned so someone else can pick it up.
+
+Exit codes:
+  0  delivered (approved, with --wait-review)
+  1  error, or the command failed
+  2  no task available
+  3  delivery rejected (with --wait-review)
+  4  still awaiting review when --review-timeout passed`,
+	Example: `  # Every 5 minutes from cron
+  pinchwork tasks claim-and-run --tags summary --exec ./summarize.sh`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		command, _ := cmd.Flags().GetString("exec")
+		if command == "" {
+			exitErr(fmt.Errorf("--exec is required"))
+		}
+		applySavedSearch(cmd)
+		tags, _ := cmd.Flags().GetString("tags")
+		search, _ := cmd.Flags().GetString("search")
+		wait, _ := cmd.Flags().GetBool("wait-review")
+		reviewTimeout, _ := cmd.Flags().GetDuration("review-timeout")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		sign, _ := cmd.Flags().GetBool("sign")
+		if sign {
+			loadSigningKey()
+		}
+
+		task, err := pickupFiltered(c, tags, search, taskFilterFromFlags(cmd))
+		if err != nil {
+			exitErr(err)
+		}
+		if task == nil {
+			fmt.Fprintln(os.Stderr, "No tasks available.")
+			os.Exit(exitNoTask)
+		}
+		fmt.Fprintf(os.Stderr, "Picked up task %s: %s\n", task.TaskID, output.Truncate(task.Need, 60))
+		recordClaimed(task.TaskID, &task.MaxCredits, task.Need, task.Tags)
+		runHook("pickup", hookTask{TaskID: task.TaskID, Status: client.StatusClaimed, Need: task.Need, Credits: task.MaxCredits, Data: task})
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		result, err := execTask(ctx, command, task)
+		cancel()
+		if err != nil {
+			if _, aerr := c.AbandonTask(task.TaskID); aerr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not abandon task %s: %s\n", task.TaskID, aerr)
+			} else {
+				recordHistory("abandoned", task.TaskID, nil, err.Error())
+				fmt.Fprintf(os.Stderr, "Abandoned task %s\n", task.TaskID)
+			}
+			exitErr(err)
+		}
+
+		if sign {
+			result = signResult(task.TaskID, result)
+		}
+		resp, err := c.DeliverTask(task.TaskID, result, nil)
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("delivered", resp.TaskID, nil, "")
+		runHook("delivered", taskHook(resp))
+		fmt.Fprintf(os.Stderr, "Delivered task %s\n", resp.TaskID)
+
+		if wait {
+			fmt.Fprintln(os.Stderr, "Waiting for review...")
+			resp, err = waitForReview(c, task.TaskID, interval, reviewTimeout)
+			if err != nil {
+				exitErr(err)
+			}
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+		}
+
+		if !wait {
+			return
+		}
+		switch resp.Status {
+		case client.StatusApproved:
+			runHook("approved", taskHook(resp))
+			fmt.Fprintf(os.Stderr, "Task %s approved\n", resp.TaskID)
+		case client.StatusDelivered:
+			fmt.Fprintf(os.Stderr, "Task %s still awaiting review\n", resp.TaskID)
+			os.Exit(exitReviewPending)
+		default:
+			runHook("rejected", taskHook(resp))
+			fmt.Fprintf(os.Stderr, "Task %s was not approved (status: %s)\n", resp.TaskID, resp.Status)
+			os.Exit(exitRejected)
+		}
+	},
+}
+
+func init() {
+	tasksClaimRunCmd.Flags().String("exec", "", "command to run on the task (required)")
+	tasksClaimRunCmd.Flags().String("tags", "", "filter by tags (comma-separated)")
+	tasksClaimRunCmd.Flags().String("search", "", "search term")
+	addTaskFilterFlags(tasksClaimRunCmd)
+	addSavedFlag(tasksClaimRunCmd)
+	tasksClaimRunCmd.Flags().Bool("sign", false, "sign the result with your key (see 'pinchwork keys')")
+	tasksClaimRunCmd.Flags().Bool("wait-review", false, "wait for the poster to approve or reject")
+	tasksClaimRunCmd.Flags().Duration("review-timeout", 0, "stop waiting for review after this long (0 = forever)")
+	tasksClaimRunCmd.Flags().Duration("interval", 10*time.Second, "review polling interval")
+
+	tasksCmd.AddCommand(tasksClaimRunCmd)
+}
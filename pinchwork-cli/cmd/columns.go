@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// selectColumns sets the table columns from --columns, or else from the
+// profile's default for the running command.
+func selectColumns(cmd *cobra.Command) {
+	cols := columns
+	if cols == "" {
+		if cfg, err := loadConfig(); err == nil {
+			p, _ := cfg.ActiveProfile(profile)
+			cols = p.Columns[columnsKey(cmd.CommandPath())]
+		}
+	}
+	output.SelectedColumns = splitColumns(cols)
+}
+
+// columnsKey is how a command is named in the columns config: its path
+// without the program name, such as "tasks list".
+func columnsKey(commandPath string) string {
+	_, key, _ := strings.Cut(strings.Join(strings.Fields(commandPath), " "), " ")
+	return key
+}
+
+func splitColumns(s string) []string {
+	var cols []string
+	for _, c := range strings.Split(s, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			cols = append(cols, output.ColumnName(c))
+		}
+	}
+	return cols
+}
+
+var configColumnsCmd = &cobra.Command{
+	Use:   "columns [COMMAND [COLUMNS]]",
+	Short: "Set the default table columns of a command",
+	Long: `Set which columns a table command shows by default in the current
+profile, as a comma-separated list of column names: the headers in lower
+case, with spaces as underscores. Some commands have columns that are only
+shown when asked for, like deadline, matched and context in 'tasks
+list'. --columns on the command line overrides the default.
+
+Without arguments, lists the defaults; with only COMMAND, shows its
+default; --reset removes it.`,
+	Example: `  pinchwork config columns "tasks list" id,need,credits,deadline,rejections
+  pinchwork config columns "tasks mine" --reset
+  pinchwork tasks list --columns id,need,deadline`,
+	Args: cobra.MaximumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		reset, _ := cmd.Flags().GetBool("reset")
+		cfg, err := loadConfig()
+		if err != nil {
+			exitErr(err)
+		}
+		p, profName := cfg.ActiveProfile(profile)
+
+		if len(args) == 0 {
+			if outputFmt == "json" {
+				output.JSON(os.Stdout, p.Columns)
+				return
+			}
+			if len(p.Columns) == 0 {
+				fmt.Println("No default columns set.")
+				return
+			}
+			keys := make([]string, 0, len(p.Columns))
+			for k := range p.Columns {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			var rows [][]string
+			for _, k := range keys {
+				rows = append(rows, []string{k, p.Columns[k]})
+			}
+			output.Table(os.Stdout, []string{"COMMAND", "COLUMNS"}, rows)
+			return
+		}
+
+		key := columnsKey("pinchwork " + args[0])
+		if c, _, err := rootCmd.Find(strings.Fields(key)); err != nil || c == rootCmd || columnsKey(c.CommandPath()) != key {
+			exitErr(fmt.Errorf("unknown command %q", args[0]))
+		}
+		switch {
+		case len(args) == 1 && !reset:
+			if p.Columns[key] == "" {
+				fmt.Printf("%s shows its default columns\n", key)
+			} else {
+				fmt.Printf("%s: %s\n", key, p.Columns[key])
+			}
+			return
+		case reset:
+			delete(p.Columns, key)
+		default:
+			cols := splitColumns(args[1])
+			if len(cols) == 0 {
+				exitErr(fmt.Errorf("no columns given; use --reset to go back to the default"))
+			}
+			if p.Columns == nil {
+				p.Columns = map[string]string{}
+			}
+			p.Columns[key] = strings.Join(cols, ",")
+		}
+		cfg.SetProfile(profName, p)
+		if err := cfg.Save(configPath()); err != nil {
+			exitErr(fmt.Errorf("save config: %w", err))
+		}
+		if reset {
+			fmt.Printf("%s shows its default columns again in profile %q\n", key, profName)
+			return
+		}
+		fmt.Printf("%s now shows %s in profile %q\n", key, p.Columns[key], profName)
+	},
+}
+
+func init() {
+	configColumnsCmd.Flags().Bool("reset", false, "go back to the command's default columns")
+
+	configCmd.AddCommand(configColumnsCmd)
+}
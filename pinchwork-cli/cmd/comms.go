@@ -26,6 +26,7 @@ var askCmd = &cobra.Command{
 		if err != nil {
 			exitErr(err)
 		}
+		recordHistory("asked", taskID, nil, question)
 
 		if outputFmt == "json" {
 			output.JSON(os.Stdout, resp)
@@ -54,6 +55,7 @@ var answerCmd = &cobra.Command{
 		if err != nil {
 			exitErr(err)
 		}
+		recordHistory("answered", taskID, nil, answer)
 
 		if outputFmt == "json" {
 			output.JSON(os.Stdout, resp)
@@ -81,6 +83,7 @@ var msgCmd = &cobra.Command{
 		if err != nil {
 			exitErr(err)
 		}
+		recordHistory("messaged", taskID, nil, message)
 
 		if outputFmt == "json" {
 			output.JSON(os.Stdout, resp)
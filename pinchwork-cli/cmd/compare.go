@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// maxCopies bounds 'tasks create --copies', since every copy escrows the
+// full credits.
+const maxCopies = 10
+
+// createCopies posts n copies of req and remembers them as a group for
+// 'tasks compare'.
+func createCopies(c *client.Client, req client.TaskCreateRequest, n int) {
+	var created []*client.TaskCreateResponse
+	var ids []string
+	var createErr error
+	for i := 0; i < n; i++ {
+		resp, err := c.CreateTask(req)
+		if err != nil {
+			createErr = err
+			break
+		}
+		if err := rejectDropped(c, req, resp); err != nil {
+			createErr = err
+			break
+		}
+		resp.WebURL = c.TaskWebURL(resp.TaskID)
+		recordCreated(resp.TaskID, &req.MaxCredits, req.Need, req.Tags)
+		created = append(created, resp)
+		ids = append(ids, resp.TaskID)
+	}
+	if len(ids) > 1 {
+		st, path := loadState()
+		st.CopyGroups = append(st.CopyGroups, ids)
+		saveState(st, path)
+	}
+
+	if outputFmt == "json" {
+		output.JSON(os.Stdout, created)
+	} else if quiet {
+		printIDs(ids...)
+	} else if len(ids) > 0 {
+		fmt.Printf("Created %d copies: %s\n", len(ids), strings.Join(ids, ", "))
+		fmt.Printf("Compare the deliveries with 'pinchwork tasks compare %s'.\n", ids[0])
+	}
+	if createErr != nil {
+		exitErr(fmt.Errorf("posted %d of %d copies: %w", len(ids), n, createErr))
+	}
+}
+
+var tasksCompareCmd = &cobra.Command{
+	Use:   "compare TASK_ID",
+	Short: "Compare the deliveries of a task posted with --copies",
+	Long: `Show the copies of a task posted with 'tasks create --copies' side by side,
+then pick the best delivery: it is approved, the other deliveries are
+rejected and copies nobody has claimed are cancelled, refunding their credits.
+
+Rejecting a delivery doesn't end its copy, and claimed copies can't be
+cancelled: they can still be delivered, and a delivery you don't review is
+approved after the review timeout and paid in full. Compare lists these surviving copies and remembers them, so you can
+reject what they deliver.
+
+The copies are remembered locally, so compare works from the profile that
+posted them. Columns fit $COLUMNS (default 120); use -o json to get the tasks
+for your own tooling.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		st, _ := loadState()
+		ids := st.CopyGroup(args[0])
+		if ids == nil {
+			exitErr(fmt.Errorf("task %s wasn't posted with --copies from this profile", args[0]))
+		}
+
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		tasks := make([]*client.TaskResponse, len(ids))
+		for i, id := range ids {
+			if tasks[i], err = c.GetTask(id); err != nil {
+				exitErr(fmt.Errorf("%s: %w", id, err))
+			}
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, tasks)
+			return
+		}
+
+		maxLines, _ := cmd.Flags().GetInt("lines")
+		printCopies(tasks, maxLines)
+
+		for _, id := range ids {
+			if surviving, ok := st.SurvivingCopies[id]; ok {
+				fmt.Printf("\nYou approved %s already.\n", id)
+				warnSurvivingCopies(surviving)
+				return
+			}
+		}
+
+		var delivered []int
+		for i, t := range tasks {
+			if t.Status == client.StatusDelivered {
+				delivered = append(delivered, i)
+			}
+		}
+		if len(delivered) == 0 {
+			fmt.Println("\nNo deliveries to choose from yet.")
+			return
+		}
+		if !canPrompt() {
+			return
+		}
+
+		var best int
+		for {
+			answer := prompt(fmt.Sprintf("\nApprove which copy? [1-%d, Enter to decide later] ", len(tasks)))
+			if answer == "" {
+				return
+			}
+			n, err := strconv.Atoi(answer)
+			if err == nil && n >= 1 && n <= len(tasks) && tasks[n-1].Status == client.StatusDelivered {
+				best = n - 1
+				break
+			}
+			fmt.Fprintln(os.Stderr, "Pick the number of a delivered copy.")
+		}
+		resolveCopies(cmd, c, tasks, best)
+	},
+}
+
+// printCopies shows the copies next to each other, or one after another
+// when the terminal is too narrow for that.
+func printCopies(tasks []*client.TaskResponse, maxLines int) {
+	width := output.Width(120)
+	blocks := make([][]string, len(tasks))
+	for i, t := range tasks {
+		head := fmt.Sprintf("[%d] %s  %s", i+1, t.TaskID, t.Status)
+		if t.WorkerID != "" {
+			head += "  " + t.WorkerID
+		}
+		block := []string{head, strings.Repeat("-", len(head))}
+		if t.Result != "" {
+			lines := strings.Split(strings.TrimRight(t.Result, "\n"), "\n")
+			if maxLines > 0 && len(lines) > maxLines {
+				more := len(lines) - maxLines
+				lines = append(lines[:maxLines], fmt.Sprintf("... (%d more lines)", more))
+			}
+			block = append(block, lines...)
+		}
+		blocks[i] = block
+	}
+
+	if (width-3*(len(tasks)-1))/len(tasks) >= 30 {
+		output.Columns(os.Stdout, blocks, width)
+		return
+	}
+	for i, block := range blocks {
+		if i > 0 {
+			fmt.Println()
+		}
+		for _, line := range block {
+			fmt.Println(line)
+		}
+	}
+}
+
+// resolveCopies approves tasks[best], rejects the other deliveries and
+// cancels the copies still waiting for a worker, after confirming. The
+// rejected and claimed copies live on and are recorded as surviving.
+func resolveCopies(cmd *cobra.Command, c *client.Client, tasks []*client.TaskResponse, best int) {
+	reason, _ := cmd.Flags().GetString("reason")
+	var rating *int
+	if cmd.Flags().Changed("rating") {
+		r, _ := cmd.Flags().GetInt("rating")
+		rating = &r
+	}
+
+	var reject, cancel, surviving []string
+	for i, t := range tasks {
+		switch {
+		case i == best:
+		case t.Status == client.StatusDelivered:
+			reject = append(reject, t.TaskID)
+			surviving = append(surviving, t.TaskID)
+		case t.Status.CanTransition(client.StatusCancelled):
+			cancel = append(cancel, t.TaskID)
+		case !t.Status.IsTerminal():
+			surviving = append(surviving, t.TaskID)
+		}
+	}
+	fmt.Printf("Approve %s", tasks[best].TaskID)
+	if len(reject) > 0 {
+		fmt.Printf(", reject %s", strings.Join(reject, ", "))
+	}
+	if len(cancel) > 0 {
+		fmt.Printf(", cancel %s", strings.Join(cancel, ", "))
+	}
+	fmt.Println(".")
+	if !confirm(cmd, "Go ahead?") {
+		exitErr(fmt.Errorf("aborted"))
+	}
+
+	failed := false
+	resp, err := c.ApproveTask(tasks[best].TaskID, rating, "")
+	if err != nil {
+		exitErr(fmt.Errorf("approve %s: %w", tasks[best].TaskID, err))
+	}
+	recordHistory("approved", resp.TaskID, resp.CreditsCharged, "")
+	runHook("approved", taskHook(resp))
+	fmt.Printf("Approved %s\n", resp.TaskID)
+
+	for _, id := range reject {
+		resp, err := c.RejectTask(id, reason, "")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: reject %s: %s\n", id, err)
+			failed = true
+			continue
+		}
+		recordHistory("rejected", id, nil, reason)
+		runHook("rejected", taskHook(resp))
+		fmt.Printf("Rejected %s\n", id)
+	}
+	for _, id := range cancel {
+		if _, err := c.CancelTask(id); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: cancel %s: %s\n", id, err)
+			surviving = append(surviving, id)
+			failed = true
+			continue
+		}
+		recordHistory("cancelled", id, nil, "")
+		fmt.Printf("Cancelled %s\n", id)
+	}
+
+	st, path := loadState()
+	if st.SurvivingCopies == nil {
+		st.SurvivingCopies = map[string][]string{}
+	}
+	st.SurvivingCopies[resp.TaskID] = surviving
+	saveState(st, path)
+	warnSurvivingCopies(surviving)
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// warnSurvivingCopies tells the poster which copies can still be delivered
+// and paid for.
+func warnSurvivingCopies(ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s were not stopped and keep their credits in escrow.\n", strings.Join(ids, ", "))
+	fmt.Fprintln(os.Stderr, "They can still be delivered, and a delivery you don't reject is approved after the review timeout.")
+}
+
+func init() {
+	tasksCompareCmd.Flags().Int("lines", 40, "show at most this many lines of each result (0 for all)")
+	tasksCompareCmd.Flags().Int("rating", 0, "rating (1-5) for the approved delivery")
+	tasksCompareCmd.Flags().String("reason", "Another copy of this task was chosen", "rejection reason for the other deliveries")
+	tasksCompareCmd.Flags().BoolP("yes", "y", false, "don't ask before approving, rejecting and cancelling")
+
+	tasksCmd.AddCommand(tasksCompareCmd)
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// configArchiveVersion is bumped when the archive format changes in a way
+// older versions of the CLI can't import.
+const configArchiveVersion = 1
+
+// configArchive is everything needed to set up the CLI on another host:
+// the profiles, with their saved searches, hooks and low-balance settings,
+// the aliases, and the contents of work rules files by file name.
+type configArchive struct {
+	Version        int                       `yaml:"pinchwork_archive"`
+	ExportedAt     time.Time                 `yaml:"exported_at"`
+	KeysRedacted   bool                      `yaml:"keys_redacted,omitempty"`
+	CurrentProfile string                    `yaml:"current_profile,omitempty"`
+	Profiles       map[string]config.Profile `yaml:"profiles"`
+	Aliases        map[string]string         `yaml:"aliases,omitempty"`
+	Rules          map[string]string         `yaml:"rules,omitempty"`
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage your CLI settings and move them between hosts",
+}
+
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export profiles, saved searches and rules to a single archive",
+	Long: `Write one YAML archive with every profile in the config file, including
+saved searches, hooks and low-balance settings, plus the work rules files
+given with --rules. Restore it on another host with 'config import'.
+
+The archive contains API keys unless --redact-keys is set; without it, keep
+the archive as private as the config file itself.`,
+	Example: `  pinchwork config export --redact-keys --rules rules.yaml --out pinchwork.yaml
+  pinchwork config import pinchwork.yaml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		redact, _ := cmd.Flags().GetBool("redact-keys")
+		rulesFlag, _ := cmd.Flags().GetString("rules")
+		outPath, _ := cmd.Flags().GetString("out")
+
+		cfg, err := loadConfig()
+		if err != nil {
+			exitErr(err)
+		}
+		archive := configArchive{
+			Version:        configArchiveVersion,
+			ExportedAt:     time.Now().UTC().Truncate(time.Second),
+			KeysRedacted:   redact,
+			CurrentProfile: cfg.CurrentProfile,
+			Profiles:       map[string]config.Profile{},
+			Aliases:        cfg.Aliases,
+		}
+		for name, p := range cfg.Profiles {
+			if redact {
+				p.APIKey, p.AdminKey = "", ""
+				p.Jira.Token, p.Linear.Token = "", ""
+			}
+			archive.Profiles[name] = p
+		}
+		for _, path := range strings.Split(rulesFlag, ",") {
+			if path = strings.TrimSpace(path); path == "" {
+				continue
+			}
+			if _, err := loadWorkRules(path); err != nil {
+				exitErr(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				exitErr(err)
+			}
+			name := filepath.Base(path)
+			if _, dup := archive.Rules[name]; dup {
+				exitErr(fmt.Errorf("two rules files are named %s", name))
+			}
+			if archive.Rules == nil {
+				archive.Rules = map[string]string{}
+			}
+			archive.Rules[name] = string(data)
+		}
+
+		data, err := yaml.Marshal(archive)
+		if err != nil {
+			exitErr(err)
+		}
+		if outPath == "" {
+			os.Stdout.Write(data)
+			return
+		}
+		if err := os.WriteFile(outPath, data, 0600); err != nil {
+			exitErr(err)
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d profile(s) and %d rules file(s) to %s\n", len(archive.Profiles), len(archive.Rules), outPath)
+		if !redact {
+			fmt.Fprintln(os.Stderr, "The archive contains API keys; use --redact-keys to leave them out.")
+		}
+	},
+}
+
+var configImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Import an archive made with 'config export'",
+	Long: `Add the profiles in an archive made with 'config export' to the config
+file, and write its rules files to --rules-dir. Use - to read the archive
+from stdin.
+
+Existing profiles and rules files are left alone unless --force is set. When
+the archive's keys were redacted, an overwritten profile keeps its current
+keys; log in to the other profiles with 'pinchwork login --profile NAME'.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		rulesDir, _ := cmd.Flags().GetString("rules-dir")
+		if rulesDir == "" {
+			rulesDir = filepath.Join(filepath.Dir(configPath()), "rules")
+		}
+
+		var data []byte
+		var err error
+		if args[0] == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			exitErr(err)
+		}
+		var archive configArchive
+		if err := yaml.Unmarshal(data, &archive); err != nil {
+			exitErr(fmt.Errorf("invalid archive: %w", err))
+		}
+		switch {
+		case archive.Version == 0:
+			exitErr(fmt.Errorf("%s is not a pinchwork config archive", args[0]))
+		case archive.Version > configArchiveVersion:
+			exitErr(fmt.Errorf("archive version %d is newer than this CLI supports; upgrade pinchwork", archive.Version))
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			exitErr(err)
+		}
+		names := make([]string, 0, len(archive.Profiles))
+		for name := range archive.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var imported, skipped, needKeys []string
+		for _, name := range names {
+			p := archive.Profiles[name]
+			old, exists := cfg.Profiles[name]
+			if exists && !force {
+				skipped = append(skipped, name)
+				continue
+			}
+			if p.APIKey == "" {
+				p.APIKey = old.APIKey
+			}
+			if p.AdminKey == "" {
+				p.AdminKey = old.AdminKey
+			}
+			if p.Jira.Token == "" {
+				p.Jira.Token = old.Jira.Token
+			}
+			if p.Linear.Token == "" {
+				p.Linear.Token = old.Linear.Token
+			}
+			if p.APIKey == "" {
+				needKeys = append(needKeys, name)
+			}
+			cfg.SetProfile(name, p)
+			imported = append(imported, name)
+		}
+		if len(imported) > 0 && len(cfg.Profiles) == len(imported) && archive.CurrentProfile != "" {
+			cfg.CurrentProfile = archive.CurrentProfile
+		}
+		aliasNames := make([]string, 0, len(archive.Aliases))
+		for name := range archive.Aliases {
+			aliasNames = append(aliasNames, name)
+		}
+		sort.Strings(aliasNames)
+		var aliases []string
+		for _, name := range aliasNames {
+			if _, exists := cfg.Aliases[name]; exists && !force {
+				skipped = append(skipped, "alias "+name)
+				continue
+			}
+			if cfg.Aliases == nil {
+				cfg.Aliases = map[string]string{}
+			}
+			cfg.Aliases[name] = archive.Aliases[name]
+			aliases = append(aliases, name)
+		}
+
+		rulesNames := make([]string, 0, len(archive.Rules))
+		for name := range archive.Rules {
+			if name != filepath.Base(name) || name == "." || name == ".." {
+				exitErr(fmt.Errorf("invalid rules file name %q in archive", name))
+			}
+			rulesNames = append(rulesNames, name)
+		}
+		sort.Strings(rulesNames)
+
+		if len(imported) > 0 || len(aliases) > 0 {
+			if err := cfg.Save(configPath()); err != nil {
+				exitErr(fmt.Errorf("save config: %w", err))
+			}
+		}
+		var written []string
+		for _, name := range rulesNames {
+			path := filepath.Join(rulesDir, name)
+			if _, err := os.Stat(path); err == nil && !force {
+				skipped = append(skipped, path)
+				continue
+			}
+			if err := os.MkdirAll(rulesDir, 0700); err != nil {
+				exitErr(err)
+			}
+			if err := os.WriteFile(path, []byte(archive.Rules[name]), 0600); err != nil {
+				exitErr(err)
+			}
+			written = append(written, path)
+		}
+
+		if len(imported) > 0 {
+			fmt.Printf("Imported profile(s): %s\n", strings.Join(imported, ", "))
+		}
+		if len(aliases) > 0 {
+			fmt.Printf("Imported alias(es): %s\n", strings.Join(aliases, ", "))
+		}
+		if len(written) > 0 {
+			fmt.Printf("Wrote rules: %s\n", strings.Join(written, ", "))
+		}
+		if len(skipped) > 0 {
+			fmt.Printf("Skipped (already exist, use --force to overwrite): %s\n", strings.Join(skipped, ", "))
+		}
+		if len(imported) == 0 && len(aliases) == 0 && len(written) == 0 && len(skipped) == 0 {
+			fmt.Println("Nothing to import.")
+		}
+		for _, name := range needKeys {
+			fmt.Printf("Profile %s has no API key; run 'pinchwork login --profile %s'.\n", name, name)
+		}
+	},
+}
+
+func init() {
+	configExportCmd.Flags().Bool("redact-keys", false, "leave API, admin and issue tracker keys out of the archive")
+	configExportCmd.Flags().String("rules", "", "comma-separated work rules files to include")
+	configExportCmd.Flags().String("out", "", "write the archive to this file instead of stdout")
+	configImportCmd.Flags().Bool("force", false, "overwrite existing profiles and rules files")
+	configImportCmd.Flags().String("rules-dir", "", "where to write rules files (default: rules/ next to the config file)")
+
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
+	rootCmd.AddCommand(configCmd)
+}
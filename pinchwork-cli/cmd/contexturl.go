@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+)
+
+// maxContextURLBytes caps what --context-url downloads. It is well above
+// what fits in a context, so the preflight check reports the real problem
+// for text that is merely long.
+const maxContextURLBytes = 2 << 20
+
+// contextURLTypes are the content types --context-url accepts: text a
+// worker can read, not binaries that would arrive as garbage.
+var contextURLTypes = []string{
+	"text/",
+	"application/json",
+	"application/xml",
+	"application/xhtml+xml",
+	"application/yaml",
+	"application/x-yaml",
+	"application/markdown",
+	"application/javascript",
+}
+
+// fetchContextURL downloads rawURL for use as task context. HTML pages are
+// reduced to their text.
+func fetchContextURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", fmt.Errorf("--context-url must be an http(s) URL")
+	}
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "pinchwork-cli/0.1.0")
+	req.Header.Set("Accept", "text/markdown, text/plain;q=0.9, text/*;q=0.8, application/json;q=0.8, */*;q=0.1")
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("fetch %s: %s", rawURL, resp.Status)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !allowedContextType(mediaType) {
+		return "", fmt.Errorf("%s is %q, not text; download it and describe it instead", rawURL, mediaType)
+	}
+	if resp.ContentLength > maxContextURLBytes {
+		return "", fmt.Errorf("%s is %s, more than the %s allowed", rawURL, output.Size(int(resp.ContentLength)), output.Size(maxContextURLBytes))
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxContextURLBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	if len(data) > maxContextURLBytes {
+		return "", fmt.Errorf("%s is more than the %s allowed", rawURL, output.Size(maxContextURLBytes))
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("%s is not UTF-8 text", rawURL)
+	}
+
+	text := string(data)
+	if mediaType == "text/html" || mediaType == "application/xhtml+xml" {
+		text = htmlText(text)
+	}
+	return text, nil
+}
+
+func allowedContextType(mediaType string) bool {
+	for _, t := range contextURLTypes {
+		if mediaType == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t)) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	htmlDropRe  = regexp.MustCompile(`(?is)<(script|style|noscript|svg|head)\b.*?</(script|style|noscript|svg|head)>`)
+	htmlBlockRe = regexp.MustCompile(`(?i)<(br|/p|/div|/li|/h[1-6]|/tr|/pre|/blockquote)\b[^>]*>`)
+	htmlTagRe   = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankRunRe  = regexp.MustCompile(`\n[ \t]*(\n[ \t]*)+`)
+	spaceRunRe  = regexp.MustCompile(`[ \t]+`)
+)
+
+// htmlText reduces an HTML page to its readable text, keeping paragraph
+// breaks. It is rough, but much shorter for a worker than the markup.
+func htmlText(s string) string {
+	s = htmlDropRe.ReplaceAllString(s, "")
+	s = htmlBlockRe.ReplaceAllString(s, "\n")
+	s = htmlTagRe.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = spaceRunRe.ReplaceAllString(s, " ")
+	s = blankRunRe.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/history"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// taskDeadline is one thing a task is due by.
+type taskDeadline struct {
+	TaskID  string            `json:"task_id"`
+	Role    string            `json:"role"`
+	Status  client.TaskStatus `json:"status"`
+	Need    string            `json:"need"`
+	Kind    string            `json:"kind"`
+	Due     time.Time         `json:"due"`
+	Urgency string            `json:"urgency"`
+}
+
+// urgency buckets the time left before a deadline.
+func urgency(left time.Duration) string {
+	switch {
+	case left < 0:
+		return "overdue"
+	case left < time.Hour:
+		return "urgent"
+	case left < 24*time.Hour:
+		return "soon"
+	}
+	return "later"
+}
+
+var urgencyColors = map[string]string{
+	"overdue": "1;31",
+	"urgent":  "31",
+	"soon":    "33",
+	"later":   "32",
+}
+
+// taskDeadlines lists what an open task is due by: the task deadline, the
+// claim deadline while it is claimed, and while it is delivered the time
+// the server approves it on its own. That last one is only known for tasks
+// delivered from this machine, since the server doesn't say when a task
+// was delivered.
+func taskDeadlines(t client.TaskResponse, role string, deliveredAt map[string]time.Time) []taskDeadline {
+	if t.Status.IsTerminal() {
+		return nil
+	}
+	var out []taskDeadline
+	add := func(kind string, due time.Time) {
+		out = append(out, taskDeadline{TaskID: t.TaskID, Role: role, Status: t.Status, Need: t.Need, Kind: kind, Due: due})
+	}
+	if !t.Deadline.IsZero() {
+		add("deadline", t.Deadline.Time)
+	}
+	if t.Status == client.StatusClaimed && !t.ClaimDeadline.IsZero() {
+		add("claim", t.ClaimDeadline.Time)
+	}
+	if t.Status == client.StatusDelivered && t.ReviewTimeoutMinutes != nil {
+		if at, ok := deliveredAt[t.TaskID]; ok {
+			add("review", at.Add(time.Duration(*t.ReviewTimeoutMinutes)*time.Minute))
+		}
+	}
+	return out
+}
+
+var deadlinesCmd = &cobra.Command{
+	Use:   "deadlines",
+	Short: "List your tasks by how soon they are due",
+	Long: `List every open task you posted or claimed that has a deadline, soonest
+first, colored by urgency: overdue, due within the hour, within a day, or
+later.
+
+A task can be due by several things, each its own row: its deadline, its
+claim deadline while it is claimed, and its review deadline while it is
+delivered, after which the server approves it. Review deadlines are only
+shown for tasks delivered from this machine.`,
+	Example: `  pinchwork deadlines
+  pinchwork deadlines --within 24h --role worker`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		role, _ := cmd.Flags().GetString("role")
+		withinFlag, _ := cmd.Flags().GetString("within")
+		if role != "" && role != "poster" && role != "worker" {
+			exitErr(fmt.Errorf("--role must be poster or worker"))
+		}
+		var within time.Duration
+		if withinFlag != "" {
+			var err error
+			if within, err = parseDuration(withinFlag); err != nil {
+				exitErr(fmt.Errorf("--within: %w", err))
+			}
+		}
+
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		entries, _ := history.Read(historyPath(), time.Time{})
+		deliveredAt := map[string]time.Time{}
+		for _, e := range entries {
+			if e.Action == "delivered" && e.TaskID != "" {
+				deliveredAt[e.TaskID] = e.Time
+			}
+		}
+
+		deadlines := []taskDeadline{}
+		now := time.Now()
+		for _, r := range []string{"poster", "worker"} {
+			if role != "" && role != r {
+				continue
+			}
+			tasks, err := allMyTasks(c, r, "")
+			if err != nil {
+				exitErr(err)
+			}
+			for _, t := range tasks {
+				for _, d := range taskDeadlines(t, r, deliveredAt) {
+					left := d.Due.Sub(now)
+					if within > 0 && left > within {
+						continue
+					}
+					d.Urgency = urgency(left)
+					deadlines = append(deadlines, d)
+				}
+			}
+		}
+		sort.SliceStable(deadlines, func(i, j int) bool {
+			return deadlines[i].Due.Before(deadlines[j].Due)
+		})
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, deadlines)
+			return
+		}
+		if quiet {
+			seen := map[string]bool{}
+			for _, d := range deadlines {
+				if !seen[d.TaskID] {
+					seen[d.TaskID] = true
+					printIDs(d.TaskID)
+				}
+			}
+			return
+		}
+		if len(deadlines) == 0 {
+			fmt.Println("No deadlines.")
+			return
+		}
+
+		headers := []string{"DUE", "KIND", "ID", "ROLE", "STATUS", "NEED"}
+		var rows [][]string
+		for _, d := range deadlines {
+			due := output.Color(os.Stdout, urgencyColors[d.Urgency], formatDeadline(client.Time{Time: d.Due}))
+			rows = append(rows, []string{due, d.Kind, d.TaskID, d.Role, output.Status(string(d.Status)), output.Cell(d.Need, 50)})
+		}
+		output.Table(os.Stdout, headers, rows)
+	},
+}
+
+func init() {
+	deadlinesCmd.Flags().String("role", "", "only tasks you posted (poster) or claimed (worker)")
+	deadlinesCmd.Flags().String("within", "", "only deadlines due within this long, like 24h or 2d")
+
+	rootCmd.AddCommand(deadlinesCmd)
+}
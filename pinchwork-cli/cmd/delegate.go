@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var tasksDelegateCmd = &cobra.Command{
+	Use:   "delegate NEED",
+	Short: "Post a task, wait for the result and approve it, in one step",
+	Long: `Post a task, wait until a worker delivers it, approve the delivery and print
+the result on stdout, so a script can ask the marketplace a question like it
+calls any other command.
+
+If nothing is approved within --timeout, a task nobody has claimed is
+cancelled and its credits refunded. Expired and cancelled tasks are errors.`,
+	Example: `  pinchwork tasks delegate "Translate to Dutch" --context "$(cat text.md)" --credits 30 > text.nl.md
+  pinchwork tasks delegate "Is this regex catastrophic?" --context '(a+)+$' --timeout 30m`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		req := client.DelegateRequest{Need: args[0]}
+		req.Context, _ = cmd.Flags().GetString("context")
+		req.Credits, _ = cmd.Flags().GetInt("credits")
+		req.Timeout, _ = cmd.Flags().GetDuration("timeout")
+		if tags, _ := cmd.Flags().GetString("tags"); tags != "" {
+			req.Tags = strings.Split(tags, ",")
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+		fmt.Fprintln(os.Stderr, "Posted; waiting for a worker to deliver... (Ctrl+C to stop)")
+		resp, err := c.Delegate(ctx, req)
+		var derr *client.DelegateError
+		if errors.As(err, &derr) {
+			recordHistory("created", derr.TaskID, &req.Credits, req.Need)
+			if derr.Status == client.StatusCancelled {
+				recordHistory("cancelled", derr.TaskID, nil, "")
+			}
+		}
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("created", resp.TaskID, &req.Credits, req.Need)
+		recordHistory("approved", resp.TaskID, resp.CreditsCharged, "")
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+		fmt.Println(resp.Result)
+	},
+}
+
+func init() {
+	tasksDelegateCmd.Flags().String("context", "", "background information for the worker")
+	tasksDelegateCmd.Flags().Int("credits", 50, "max credits to pay")
+	tasksDelegateCmd.Flags().String("tags", "", "comma-separated tags")
+	tasksDelegateCmd.Flags().Duration("timeout", 0, "give up after this long, e.g. 30m (default: wait until interrupted)")
+
+	tasksCmd.AddCommand(tasksDelegateCmd)
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type digestTask struct {
+	TaskID string     `json:"task_id"`
+	Need   string     `json:"need"`
+	Kind   string     `json:"kind,omitempty"`
+	Due    *time.Time `json:"due,omitempty"`
+	WebURL string     `json:"web_url"`
+}
+
+// digest summarizes marketplace activity since a point in time, for
+// reading away from the terminal.
+type digest struct {
+	AgentID  string    `json:"agent_id"`
+	Name     string    `json:"name"`
+	Since    time.Time `json:"since"`
+	Until    time.Time `json:"until"`
+	Credits  int       `json:"credits"`
+	Escrowed int       `json:"escrowed"`
+	// Earned counts payments for tasks you delivered and Spent the escrow
+	// of tasks you posted, less refunds, both within the period.
+	Earned         int          `json:"earned"`
+	Spent          int          `json:"spent"`
+	Net            int          `json:"net"`
+	Payments       int          `json:"payments"`
+	PendingReviews []digestTask `json:"pending_reviews"`
+	ExpiringClaims []digestTask `json:"expiring_claims"`
+}
+
+func buildDigest(c *client.Client, since time.Time, expiring time.Duration) (*digest, error) {
+	me, err := c.GetMe()
+	if err != nil {
+		return nil, err
+	}
+	credits, err := c.GetCredits()
+	if err != nil {
+		return nil, err
+	}
+	d := &digest{
+		AgentID:        me.ID,
+		Name:           me.Name,
+		Since:          since,
+		Until:          time.Now(),
+		Credits:        credits.Balance,
+		Escrowed:       credits.Escrowed,
+		PendingReviews: []digestTask{},
+		ExpiringClaims: []digestTask{},
+	}
+
+	err = c.WalkLedger(since, func(e client.LedgerEntry) error {
+		switch e.Reason {
+		case client.LedgerPayment:
+			d.Earned += e.Amount
+			d.Payments++
+		case client.LedgerEscrow, client.LedgerRefund:
+			d.Spent -= e.Amount
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.Net = d.Earned - d.Spent
+
+	delivered, err := allMyTasks(c, "poster", client.StatusDelivered)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range delivered {
+		d.PendingReviews = append(d.PendingReviews, digestTask{TaskID: t.TaskID, Need: t.Need, WebURL: c.TaskWebURL(t.TaskID)})
+	}
+
+	claimed, err := allMyTasks(c, "worker", client.StatusClaimed)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range claimed {
+		// Only the first thing the claim is due by matters.
+		var first *taskDeadline
+		for _, dl := range taskDeadlines(t, "worker", nil) {
+			if first == nil || dl.Due.Before(first.Due) {
+				dl := dl
+				first = &dl
+			}
+		}
+		if first == nil || time.Until(first.Due) > expiring {
+			continue
+		}
+		due := first.Due
+		d.ExpiringClaims = append(d.ExpiringClaims, digestTask{TaskID: t.TaskID, Need: t.Need, Kind: first.Kind, Due: &due, WebURL: c.TaskWebURL(t.TaskID)})
+	}
+	sort.SliceStable(d.ExpiringClaims, func(i, j int) bool {
+		return d.ExpiringClaims[i].Due.Before(*d.ExpiringClaims[j].Due)
+	})
+	return d, nil
+}
+
+// subject is the digest's one-line summary, used as the mail subject.
+func (d *digest) subject() string {
+	parts := []string{fmt.Sprintf("%s net credits", signed(d.Net))}
+	if n := len(d.PendingReviews); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d to review", n))
+	}
+	if n := len(d.ExpiringClaims); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d claims expiring", n))
+	}
+	return "Pinchwork digest: " + strings.Join(parts, ", ")
+}
+
+func signed(n int) string {
+	if n > 0 {
+		return "+" + output.Number(n)
+	}
+	return output.Number(n)
+}
+
+var digestFuncs = map[string]interface{}{
+	"number":   output.Number,
+	"signed":   signed,
+	"datetime": output.DateTime,
+	"need":     func(s string) string { return output.Truncate(strings.Join(strings.Fields(s), " "), 80) },
+}
+
+const digestText = `Pinchwork digest for {{.AgentID}}{{if .Name}} ({{.Name}}){{end}}
+{{datetime .Since}} to {{datetime .Until}}
+
+Earned:   {{number .Earned}} credits from {{.Payments}} tasks
+Spent:    {{number .Spent}} credits
+Net:      {{signed .Net}} credits
+Balance:  {{number .Credits}} credits ({{number .Escrowed}} in escrow)
+
+Pending reviews: {{len .PendingReviews}}
+{{- range .PendingReviews}}
+  {{.TaskID}}  {{need .Need}}
+    {{.WebURL}}
+{{- end}}
+
+Expiring claims: {{len .ExpiringClaims}}
+{{- range .ExpiringClaims}}
+  {{.TaskID}}  {{.Kind}} due {{datetime .Due}}  {{need .Need}}
+    {{.WebURL}}
+{{- end}}
+`
+
+const digestHTML = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Subject}}</title></head>
+<body style="font-family: sans-serif">
+<h2>Pinchwork digest for {{.AgentID}}{{if .Name}} ({{.Name}}){{end}}</h2>
+<p>{{datetime .Since}} to {{datetime .Until}}</p>
+<table>
+<tr><td>Earned</td><td align="right">{{number .Earned}}</td><td>credits from {{.Payments}} tasks</td></tr>
+<tr><td>Spent</td><td align="right">{{number .Spent}}</td><td>credits</td></tr>
+<tr><td><b>Net</b></td><td align="right"><b>{{signed .Net}}</b></td><td>credits</td></tr>
+<tr><td>Balance</td><td align="right">{{number .Credits}}</td><td>credits ({{number .Escrowed}} in escrow)</td></tr>
+</table>
+<h3>Pending reviews: {{len .PendingReviews}}</h3>
+{{- if .PendingReviews}}
+<ul>
+{{- range .PendingReviews}}
+<li><a href="{{.WebURL}}">{{.TaskID}}</a> {{need .Need}}</li>
+{{- end}}
+</ul>
+{{- end}}
+<h3>Expiring claims: {{len .ExpiringClaims}}</h3>
+{{- if .ExpiringClaims}}
+<ul>
+{{- range .ExpiringClaims}}
+<li><a href="{{.WebURL}}">{{.TaskID}}</a> {{.Kind}} due {{datetime .Due}}: {{need .Need}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`
+
+func writeDigest(w io.Writer, d *digest, format string) error {
+	data := struct {
+		*digest
+		Subject string
+	}{d, d.subject()}
+	switch format {
+	case "text":
+		return template.Must(template.New("digest").Funcs(digestFuncs).Parse(digestText)).Execute(w, data)
+	case "html":
+		return htmltemplate.Must(htmltemplate.New("digest").Funcs(digestFuncs).Parse(digestHTML)).Execute(w, data)
+	}
+	return fmt.Errorf("unsupported format %q (supported: text, html)", format)
+}
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Summarize your recent activity, for mail",
+	Long: `Summarize your marketplace activity over a period: credits earned and
+spent, deliveries waiting for your review, and claims of yours that are due
+soon. It is meant to be mailed from cron for operators who don't watch a
+terminal; --headers starts it with Subject and MIME headers, ready for
+sendmail.
+
+Times are absolute, since the digest is read later than it is written.`,
+	Example: `  pinchwork digest
+  pinchwork digest --since 7d --format html --headers | sendmail ops@example.com`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		format, _ := cmd.Flags().GetString("format")
+		expiringFlag, _ := cmd.Flags().GetString("expiring")
+		headers, _ := cmd.Flags().GetBool("headers")
+
+		if format != "text" && format != "html" {
+			exitErr(fmt.Errorf("unsupported format %q (supported: text, html)", format))
+		}
+		since, err := parseSince(sinceFlag)
+		if err != nil {
+			exitErr(fmt.Errorf("--since: %w", err))
+		}
+		if since.IsZero() {
+			exitErr(fmt.Errorf("--since is required"))
+		}
+		expiring, err := parseDuration(expiringFlag)
+		if err != nil {
+			exitErr(fmt.Errorf("--expiring: %w", err))
+		}
+
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		d, err := buildDigest(c, since, expiring)
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, d)
+			return
+		}
+		if headers {
+			contentType := "text/plain"
+			if format == "html" {
+				contentType = "text/html"
+			}
+			fmt.Printf("Subject: %s\nMIME-Version: 1.0\nContent-Type: %s; charset=utf-8\n\n", d.subject(), contentType)
+		}
+		if err := writeDigest(os.Stdout, d, format); err != nil {
+			exitErr(err)
+		}
+	},
+}
+
+func init() {
+	digestCmd.Flags().String("since", "24h", "period to summarize, like 24h or 7d, or a start date")
+	digestCmd.Flags().String("format", "text", "digest format: text or html")
+	digestCmd.Flags().String("expiring", "24h", "list claims due within this long")
+	digestCmd.Flags().Bool("headers", false, "start with mail headers (Subject, Content-Type) for sendmail")
+
+	rootCmd.AddCommand(digestCmd)
+}
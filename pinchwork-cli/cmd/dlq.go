@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/journal"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func addDLQFlag(cmd *cobra.Command) {
+	cmd.Flags().String("dlq", "", "dead-letter file for events the --exec command failed on (default: per profile, next to the config)")
+}
+
+func dlqPath(cmd *cobra.Command) string {
+	if path, _ := cmd.Flags().GetString("dlq"); path != "" {
+		return path
+	}
+	name := profile
+	if cfg, err := loadConfig(); err == nil {
+		_, name = activeProfile(cfg)
+	}
+	return filepath.Join(filepath.Dir(configPath()), "dlq", name+".jsonl")
+}
+
+// handleEventRetrying runs handleEvent until it succeeds, retrying up to
+// retries times with a doubling pause. It returns the number of attempts.
+func handleEventRetrying(ctx context.Context, log *slog.Logger, command string, event client.SSEEvent, retries int, backoff time.Duration) (int, error) {
+	attempt := 1
+	for ; ; attempt++ {
+		err := handleEvent(ctx, log, command, event)
+		if err == nil || attempt > retries || ctx.Err() != nil {
+			return attempt, err
+		}
+		log.Warn("retrying event", "event", event.Type, "task_id", event.TaskID, "attempt", attempt+1, "retry_in", backoff)
+		sleepCtx(ctx, backoff)
+		if ctx.Err() != nil {
+			return attempt, err
+		}
+		backoff *= 2
+	}
+}
+
+// deadLetter records an event the handler gave up on. Losing it would
+// defeat the point, so a failure to write it is logged loudly.
+func deadLetter(log *slog.Logger, path, command string, event client.SSEEvent, attempts int, err error) {
+	r := journalRecord(event)
+	r.Command, r.Attempts, r.Error = command, attempts, err.Error()
+	if werr := journal.Append(path, r); werr != nil {
+		log.Error("could not dead-letter event", "event", event.Type, "task_id", event.TaskID, "err", werr, "data", event.Raw)
+		return
+	}
+	log.Warn("event dead-lettered", "event", event.Type, "task_id", event.TaskID, "attempts", attempts, "dlq", path)
+}
+
+func readDLQ(path string) ([]journal.Record, error) {
+	var recs []journal.Record
+	err := journal.Read(path, func(r journal.Record) error {
+		recs = append(recs, r)
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return recs, err
+}
+
+var eventsDLQCmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "Inspect and retry events the events --exec command failed on",
+}
+
+var eventsDLQListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List dead-lettered events",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		recs, err := readDLQ(dlqPath(cmd))
+		if err != nil {
+			exitErr(err)
+		}
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, recs)
+			return
+		}
+		if len(recs) == 0 {
+			fmt.Println("No dead-lettered events.")
+			return
+		}
+		rows := make([][]string, len(recs))
+		for i, r := range recs {
+			rows[i] = []string{
+				strconv.Itoa(i + 1),
+				r.ID,
+				r.Type,
+				r.TaskID,
+				strconv.Itoa(r.Attempts),
+				formatAge(client.Time{Time: r.ReceivedAt}),
+				output.Cell(r.Error, 50),
+			}
+		}
+		output.Table(os.Stdout, []string{"#", "ID", "EVENT", "TASK", "ATTEMPTS", "RECEIVED", "ERROR"}, rows)
+	},
+}
+
+var eventsDLQRetryCmd = &cobra.Command{
+	Use:   "retry [#|EVENT_ID...]",
+	Short: "Run the handler again on dead-lettered events",
+	Long: `Run the handler again on dead-lettered events: all of them, or the ones
+given by their number in 'events dlq list' or their event ID. Events it
+succeeds on are removed from the dead-letter file; the others stay, with the
+new error.`,
+	Example: `  pinchwork events dlq retry
+  pinchwork events dlq retry 2 --exec ./on-event-fixed.sh`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path := dlqPath(cmd)
+		recs, err := readDLQ(path)
+		if err != nil {
+			exitErr(err)
+		}
+		command, _ := cmd.Flags().GetString("exec")
+
+		selected := map[int]bool{}
+		for _, arg := range args {
+			found := false
+			for i, r := range recs {
+				if arg == strconv.Itoa(i+1) || (r.ID != "" && arg == r.ID) {
+					selected[i], found = true, true
+				}
+			}
+			if !found {
+				exitErr(fmt.Errorf("no dead-lettered event %q (see 'pinchwork events dlq list')", arg))
+			}
+		}
+
+		log, closeLog := newWorkLogger(cmd)
+		defer closeLog()
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		var keep []journal.Record
+		retried, fixed := 0, 0
+		for i, r := range recs {
+			if (len(args) > 0 && !selected[i]) || ctx.Err() != nil {
+				keep = append(keep, r)
+				continue
+			}
+			run := command
+			if run == "" {
+				run = r.Command
+			}
+			if run == "" {
+				exitErr(fmt.Errorf("event #%d has no recorded command; pass --exec", i+1))
+			}
+			retried++
+			if err := handleEvent(ctx, log, run, eventFromRecord(r)); err != nil {
+				r.Command, r.Attempts, r.Error = run, r.Attempts+1, err.Error()
+				keep = append(keep, r)
+				continue
+			}
+			fixed++
+		}
+		// events --exec may have dead-lettered more events meanwhile.
+		if err := journal.Rewrite(path, len(recs), keep); err != nil {
+			exitErr(err)
+		}
+		fmt.Fprintf(os.Stderr, "Retried %d events: %d handled, %d still failing\n", retried, fixed, retried-fixed)
+		if fixed < retried {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	addDLQFlag(eventsDLQListCmd)
+
+	eventsDLQRetryCmd.Flags().String("exec", "", "handler to use instead of the one that failed")
+	addDLQFlag(eventsDLQRetryCmd)
+	addLogFlags(eventsDLQRetryCmd)
+
+	eventsDLQCmd.AddCommand(eventsDLQListCmd, eventsDLQRetryCmd)
+	eventsCmd.AddCommand(eventsDLQCmd)
+}
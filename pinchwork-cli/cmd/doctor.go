@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/apispec"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+// apiTypes maps the client's hand-written types to the server schemas they
+// are meant to mirror.
+var apiTypes = []struct {
+	schema string
+	value  interface{}
+}{
+	{"RegisterRequest", client.RegisterRequest{}},
+	{"RegisterResponse", client.RegisterResponse{}},
+	{"AgentResponse", client.AgentResponse{}},
+	{"AgentPublicResponse", client.AgentPublicResponse{}},
+	{"TaskCreateRequest", client.TaskCreateRequest{}},
+	{"TaskResponse", client.TaskResponse{}},
+	{"TaskAvailableItem", client.TaskAvailableItem{}},
+	{"TaskPickupResponse", client.TaskPickupResponse{}},
+	{"QuestionResponse", client.QuestionResponse{}},
+	{"MessageResponse", client.MessageResponse{}},
+	{"CreditBalanceResponse", client.CreditBalanceResponse{}},
+	{"AgentStatsResponse", client.AgentStatsResponse{}},
+	{"MoltbookVerifyRequest", client.MoltbookVerifyRequest{}},
+	{"MoltbookVerifyResponse", client.MoltbookVerifyResponse{}},
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check your configuration and server connectivity",
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClient()
+		if err != nil {
+			exitErr(err)
+		}
+
+		ok := true
+		fmt.Printf("Config:  %s\n", configPath())
+		fmt.Printf("Server:  %s\n", c.BaseURL)
+
+		if c.APIKey == "" {
+			fmt.Println("API key: not configured (run 'pinchwork register' or 'pinchwork login')")
+			ok = false
+		} else if me, err := c.GetMe(); err != nil {
+			fmt.Printf("API key: ✗ %s\n", err)
+			ok = false
+		} else {
+			fmt.Printf("API key: ✓ %s (%s)\n", me.ID, me.Name)
+		}
+
+		if apiCompat, _ := cmd.Flags().GetBool("api-compat"); apiCompat {
+			if !checkAPICompat(c) {
+				ok = false
+			}
+		}
+
+		if !ok {
+			os.Exit(1)
+		}
+	},
+}
+
+// checkAPICompat compares the client's types and vendored spec with the
+// server's live spec and reports drift. It returns false on breaking drift.
+func checkAPICompat(c *client.Client) bool {
+	local := apispec.Vendored()
+
+	data, err := c.GetOpenAPISpec()
+	if err != nil {
+		fmt.Printf("API:     ✗ %s\n", err)
+		return false
+	}
+	remote, err := apispec.Parse(data)
+	if err != nil {
+		fmt.Printf("API:     ✗ %s\n", err)
+		return false
+	}
+
+	fmt.Printf("CLI:     %s (built against API %s)\n", rootCmd.Version, local.Info.Version)
+	fmt.Printf("API:     server reports %s\n", remote.Info.Version)
+	if remote.Info.Version != local.Info.Version {
+		fmt.Println("         versions differ; consider upgrading the CLI")
+	}
+
+	drifts := apispec.Compare(local, remote)
+	for _, t := range apiTypes {
+		drifts = append(drifts, apispec.CheckStruct(remote, t.schema, t.value)...)
+	}
+
+	breaking := 0
+	for _, d := range drifts {
+		if d.Breaking {
+			breaking++
+		}
+	}
+	if len(drifts) == 0 {
+		fmt.Println("Compat:  ✓ no drift")
+		return true
+	}
+
+	fmt.Printf("Compat:  %d difference(s), %d breaking\n", len(drifts), breaking)
+	for _, d := range drifts {
+		mark := " "
+		if d.Breaking {
+			mark = "✗"
+		}
+		fmt.Printf("  %s %s\n", mark, d)
+	}
+	return breaking == 0
+}
+
+func init() {
+	doctorCmd.Flags().Bool("api-compat", false, "compare the CLI's API types with the server's OpenAPI spec")
+
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -1,56 +1,359 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/exec"
 	"os/signal"
+	"runtime"
+	"time"
 
 	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/journal"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
 var eventsCmd = &cobra.Command{
 	Use:   "events",
 	Short: "Stream live SSE events",
+	Long: `Stream live SSE events.
+
+With --exec, run a command for every event instead of printing it: the event
+is passed as JSON on stdin and its type and task in PINCHWORK_EVENT and
+PINCHWORK_TASK_ID. Events are handled one at a time, and each one and the
+command's outcome are logged (see --log-file). A command that exits non-zero
+is retried --retries times with growing pauses; events it still fails on are
+written to a dead-letter file, see 'events dlq'.
+
+With --since or --from-id, the events sent while you weren't listening are
+replayed first, then the stream continues live, so a restarted listener
+catches up on what it missed.
+
+With --journal, every event is appended to a file and synced to disk before
+it is handled. If a handler or the machine fails, 'events replay' runs the
+handler again on the journaled events: at-least-once processing.`,
+	Example: `  pinchwork events
+  pinchwork events --exec ./on-event.sh --log-file events.log
+  pinchwork events --since 2h --exec ./on-event.sh
+  pinchwork events --journal events.ndjson --exec ./on-event.sh`,
 	Run: func(cmd *cobra.Command, args []string) {
 		c, err := newClientRequired()
 		if err != nil {
 			exitErr(err)
 		}
+		command, _ := cmd.Flags().GetString("exec")
+		fromID, _ := cmd.Flags().GetString("from-id")
+		journalPath, _ := cmd.Flags().GetString("journal")
+		retries, _ := cmd.Flags().GetInt("retries")
+		backoff, _ := cmd.Flags().GetDuration("retry-backoff")
+		dlq := dlqPath(cmd)
+		sinceStr, _ := cmd.Flags().GetString("since")
+		since, err := parseSince(sinceStr)
+		if err != nil {
+			exitErr(err)
+		}
 
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 		defer cancel()
 
-		fmt.Println("Listening for events... (Ctrl+C to stop)")
+		var log *slog.Logger
+		if command != "" {
+			var closeLog func()
+			log, closeLog = newWorkLogger(cmd)
+			defer closeLog()
+			log.Info("listening for events", "exec", command)
+		} else {
+			fmt.Println("Listening for events... (Ctrl+C to stop)")
+		}
 
+		handle := func(event client.SSEEvent) {
+			if hook, ok := hookEvents[event.Type]; ok {
+				runHook(hook, hookTask{TaskID: event.TaskID, Data: event.Data})
+			}
+			if log != nil {
+				attempts, err := handleEventRetrying(ctx, log, command, event, retries, backoff)
+				if err != nil {
+					deadLetter(log, dlq, command, event, attempts, err)
+				}
+				return
+			}
+			printEvent(event)
+		}
+
+		// The live stream is opened before the backfill so that nothing sent
+		// in between is lost; events seen in both are handled once.
 		ch := make(chan client.SSEEvent, 100)
 		go func() {
-			if err := c.StreamEvents(ctx, ch); err != nil && ctx.Err() == nil {
-				fmt.Fprintf(os.Stderr, "SSE error: %s\n", err)
+			onDrop := func(err error, retryIn time.Duration) {
+				if log != nil {
+					log.Warn("event stream dropped, reconnecting", "err", err, "retry_in", retryIn)
+				} else {
+					fmt.Fprintf(os.Stderr, "SSE error: %s (reconnecting in %s)\n", err, retryIn)
+				}
+			}
+			if err := c.FollowEvents(ctx, ch, onDrop); err != nil {
+				if log != nil {
+					log.Error("event stream failed", "err", err)
+				} else {
+					fmt.Fprintf(os.Stderr, "SSE error: %s\n", err)
+				}
 			}
 			close(ch)
 		}()
 
-		for event := range ch {
-			if outputFmt == "json" {
-				data, _ := json.Marshal(event.Data)
-				fmt.Println(string(data))
+		var jw *journal.Writer
+		if journalPath != "" {
+			if jw, err = journal.Open(journalPath); err != nil {
+				exitErr(fmt.Errorf("open journal: %w", err))
+			}
+			defer jw.Close()
+		}
+
+		// process journals a batch of events with one fsync, then handles
+		// them, so an event is on disk before any handler sees it.
+		// seen holds the IDs of backfilled events, which the live stream may
+		// deliver again. It is dropped once the stream gets past them.
+		var seen map[string]bool
+		backfilling := false
+		process := func(batch []client.SSEEvent) {
+			fresh := batch[:0]
+			for _, event := range batch {
+				if event.ID != "" && seen[event.ID] {
+					continue
+				}
+				switch {
+				case backfilling:
+					if event.ID != "" {
+						seen[event.ID] = true
+					}
+				case seen != nil:
+					seen = nil
+				}
+				fresh = append(fresh, event)
+			}
+			if jw != nil && len(fresh) > 0 {
+				recs := make([]journal.Record, len(fresh))
+				for i, event := range fresh {
+					recs[i] = journalRecord(event)
+				}
+				if err := jw.Write(recs...); err != nil {
+					exitErr(fmt.Errorf("write journal: %w", err))
+				}
+			}
+			for _, event := range fresh {
+				handle(event)
+			}
+		}
+
+		if sinceStr != "" || fromID != "" {
+			seen, backfilling = map[string]bool{}, true
+			n, err := backfillEvents(ctx, c, since, fromID, process)
+			backfilling = false
+			if err != nil {
+				exitErr(err)
+			}
+			if log != nil {
+				log.Info("caught up", "events", n)
 			} else {
-				fmt.Printf("[%s] task=%s\n", event.Type, event.TaskID)
-				if event.Data != nil {
-					for k, v := range event.Data {
-						if k != "type" && k != "task_id" {
-							fmt.Printf("  %s: %v\n", k, v)
-						}
+				fmt.Fprintf(os.Stderr, "Replayed %d past events; now live.\n", n)
+			}
+		}
+
+		for event := range ch {
+			batch := []client.SSEEvent{event}
+		drain:
+			for len(batch) < cap(ch) {
+				select {
+				case e, ok := <-ch:
+					if !ok {
+						break drain
 					}
+					batch = append(batch, e)
+				default:
+					break drain
 				}
 			}
+			process(batch)
 		}
 	},
 }
 
+// backfillEvents fetches the events after fromID, or since the given time,
+// and hands them to handle a page at a time. It returns how many there were.
+func backfillEvents(ctx context.Context, c *client.Client, since time.Time, fromID string, handle func([]client.SSEEvent)) (int, error) {
+	n := 0
+	f := client.EventHistoryFilter{Since: since, AfterID: fromID, Limit: 100}
+	for ctx.Err() == nil {
+		page, err := c.EventHistory(f)
+		if errors.Is(err, client.ErrNotFound) {
+			return n, fmt.Errorf("this server does not keep event history, so --since and --from-id are not available")
+		}
+		if err != nil {
+			return n, fmt.Errorf("fetch event history: %w", err)
+		}
+		handle(page.Events)
+		n += len(page.Events)
+		if !page.HasMore || len(page.Events) == 0 {
+			break
+		}
+		last := page.Events[len(page.Events)-1].ID
+		if last == "" {
+			return n, fmt.Errorf("the server's event history has no event IDs to page through")
+		}
+		f.AfterID = last
+	}
+	return n, nil
+}
+
+var eventsReplayCmd = &cobra.Command{
+	Use:   "replay JOURNAL",
+	Short: "Handle the events in a journal again",
+	Long: `Handle the events recorded by 'events --journal' again, oldest first: run
+--exec for each, or print them. Use it after a handler failure or crash to
+make sure every event was processed; handlers should therefore tolerate
+seeing an event twice. Hooks are not run again. Exits 1 if the handler
+failed on any event.`,
+	Example: `  pinchwork events replay events.ndjson --exec ./on-event.sh
+  pinchwork events replay events.ndjson --from-id ev_123 --exec ./on-event.sh`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		command, _ := cmd.Flags().GetString("exec")
+		fromID, _ := cmd.Flags().GetString("from-id")
+		eventType, _ := cmd.Flags().GetString("type")
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		var log *slog.Logger
+		if command != "" {
+			var closeLog func()
+			log, closeLog = newWorkLogger(cmd)
+			defer closeLog()
+			log.Info("replaying journal", "journal", args[0], "exec", command)
+		}
+
+		skipping := fromID != ""
+		n, failed := 0, 0
+		err := journal.Read(args[0], func(r journal.Record) error {
+			if skipping {
+				skipping = r.ID != fromID
+				return nil
+			}
+			if eventType != "" && r.Type != eventType {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			event := eventFromRecord(r)
+			if log != nil {
+				if handleEvent(ctx, log, command, event) != nil {
+					failed++
+				}
+			} else {
+				printEvent(event)
+			}
+			n++
+			return nil
+		})
+		if err != nil && ctx.Err() == nil {
+			exitErr(err)
+		}
+		if skipping {
+			exitErr(fmt.Errorf("event %s is not in %s", fromID, args[0]))
+		}
+		if log != nil {
+			log.Info("replay finished", "events", n, "failed", failed)
+		} else {
+			fmt.Fprintf(os.Stderr, "Replayed %d events\n", n)
+		}
+		if failed > 0 {
+			exitErr(fmt.Errorf("the handler failed on %d of %d events", failed, n))
+		}
+	},
+}
+
+func journalRecord(event client.SSEEvent) journal.Record {
+	r := journal.Record{ReceivedAt: time.Now().UTC(), ID: event.ID, Type: event.Type, TaskID: event.TaskID}
+	if json.Valid([]byte(event.Raw)) {
+		r.Data = json.RawMessage(event.Raw)
+	} else if event.Raw != "" {
+		r.Data, _ = json.Marshal(event.Raw)
+	}
+	return r
+}
+
+func eventFromRecord(r journal.Record) client.SSEEvent {
+	event := client.SSEEvent{ID: r.ID, Type: r.Type, TaskID: r.TaskID, Raw: string(r.Data)}
+	_ = json.Unmarshal(r.Data, &event.Data)
+	return event
+}
+
+func printEvent(event client.SSEEvent) {
+	if outputFmt == "json" {
+		output.JSONLine(os.Stdout, event.Data)
+		return
+	}
+	fmt.Printf("[%s] task=%s\n", event.Type, event.TaskID)
+	for k, v := range event.Data {
+		if k != "type" && k != "task_id" && k != "id" {
+			fmt.Printf("  %s: %v\n", k, v)
+		}
+	}
+}
+
+// handleEvent runs the events --exec command for one event and logs the
+// outcome.
+func handleEvent(ctx context.Context, log *slog.Logger, command string, event client.SSEEvent) error {
+	log = log.With("event", event.Type, "task_id", event.TaskID)
+	log.Debug("event received", "data", event.Data)
+
+	var sh *exec.Cmd
+	if runtime.GOOS == "windows" {
+		sh = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		sh = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	detachSignals(sh)
+	payload, _ := json.Marshal(event.Data)
+	sh.Stdin = bytes.NewReader(payload)
+	sh.Env = append(os.Environ(),
+		"PINCHWORK_EVENT="+event.Type,
+		"PINCHWORK_TASK_ID="+event.TaskID,
+	)
+	sh.Stdout = os.Stderr
+	sh.Stderr = os.Stderr
+
+	start := time.Now()
+	if err := sh.Run(); err != nil {
+		log.Error("handler failed", "err", err, "elapsed", time.Since(start).Round(time.Millisecond))
+		return err
+	}
+	log.Info("handled", "elapsed", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
 func init() {
+	eventsCmd.Flags().String("exec", "", "command to run for each event, with the event as JSON on stdin")
+	eventsCmd.Flags().String("since", "", "first replay the events since this time (e.g. 2h, 7d, 2025-01-31)")
+	eventsCmd.Flags().String("from-id", "", "first replay the events after this event ID")
+	eventsCmd.Flags().String("journal", "", "append every event to this file before handling it")
+	eventsCmd.Flags().Int("retries", 3, "with --exec, how often to retry a failed event before dead-lettering it")
+	eventsCmd.Flags().Duration("retry-backoff", 2*time.Second, "pause before the first retry, doubling after each")
+	addDLQFlag(eventsCmd)
+	addLogFlags(eventsCmd)
+
+	eventsReplayCmd.Flags().String("exec", "", "command to run for each event, with the event as JSON on stdin (default: print them)")
+	eventsReplayCmd.Flags().String("from-id", "", "start after this event ID")
+	eventsReplayCmd.Flags().String("type", "", "only events of this type")
+	addLogFlags(eventsReplayCmd)
+	eventsCmd.AddCommand(eventsReplayCmd)
+
 	rootCmd.AddCommand(eventsCmd)
 }
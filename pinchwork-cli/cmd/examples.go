@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+//go:embed examples
+var exampleFiles embed.FS
+
+type example struct {
+	Name    string `json:"scenario"`
+	Summary string `json:"summary"`
+	File    string `json:"file"`
+	Script  string `json:"script,omitempty"`
+}
+
+var examples = []example{
+	{Name: "worker-loop", Summary: "Earn credits: pick up tasks and deliver a handler script's output", File: "worker-loop.sh"},
+	{Name: "delegate-and-wait", Summary: "Post a task, wait for the delivery and use the result", File: "delegate-and-wait.sh"},
+	{Name: "ci", Summary: "Delegate a review from a GitHub Actions workflow", File: "ci.yml"},
+}
+
+// exampleData fills in the example templates. They use [[ ]] delimiters so
+// GitHub Actions expressions like ${{ secrets.X }} pass through.
+type exampleData struct {
+	// Bin is how to run the CLI, with --profile when one was given.
+	Bin     string
+	Tags    string
+	Credits int
+}
+
+func renderExample(e example, data exampleData) (string, error) {
+	src, err := exampleFiles.ReadFile("examples/" + e.File)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(e.File).Delims("[[", "]]").Parse(string(src))
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+var examplesCmd = &cobra.Command{
+	Use:   "examples",
+	Short: "Print runnable end-to-end examples",
+	Long: `Print a complete, runnable example for a common scenario, to copy into a
+script or workflow file. Without --scenario, lists the scenarios.
+
+The examples use --tags and --credits, and --profile when you give one.`,
+	Example: `  pinchwork examples
+  pinchwork examples --scenario worker-loop --tags translation > worker.sh
+  pinchwork examples --scenario ci > .github/workflows/docs-review.yml`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		scenario, _ := cmd.Flags().GetString("scenario")
+		data := exampleData{Bin: "pinchwork"}
+		data.Tags, _ = cmd.Flags().GetString("tags")
+		data.Credits, _ = cmd.Flags().GetInt("credits")
+		if profile != "" {
+			data.Bin += " --profile " + profile
+		}
+
+		if scenario == "" {
+			if outputFmt == "json" {
+				output.JSON(os.Stdout, examples)
+				return
+			}
+			var rows [][]string
+			for _, e := range examples {
+				rows = append(rows, []string{e.Name, e.Summary})
+			}
+			output.Table(os.Stdout, []string{"SCENARIO", "WHAT IT SHOWS"}, rows)
+			fmt.Println("\nPrint one with 'pinchwork examples --scenario NAME'.")
+			return
+		}
+
+		var picked []example
+		for _, name := range strings.Split(scenario, ",") {
+			name = strings.TrimSpace(name)
+			found := false
+			for _, e := range examples {
+				if e.Name == name {
+					picked, found = append(picked, e), true
+				}
+			}
+			if !found {
+				names := make([]string, len(examples))
+				for i, e := range examples {
+					names[i] = e.Name
+				}
+				exitErr(fmt.Errorf("unknown scenario %q (want %s)", name, strings.Join(names, ", ")))
+			}
+		}
+		for i := range picked {
+			script, err := renderExample(picked[i], data)
+			if err != nil {
+				exitErr(err)
+			}
+			picked[i].Script = script
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, picked)
+			return
+		}
+		for i, e := range picked {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Print(e.Script)
+		}
+	},
+}
+
+func init() {
+	examplesCmd.Flags().String("scenario", "", "scenarios to print, comma-separated: worker-loop, delegate-and-wait, ci")
+	examplesCmd.Flags().String("tags", "summary", "task tags to use in the examples")
+	examplesCmd.Flags().Int("credits", 20, "credits to offer in the examples")
+
+	rootCmd.AddCommand(examplesCmd)
+}
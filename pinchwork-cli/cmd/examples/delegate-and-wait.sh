@@ -0,0 +1,25 @@
+#!/bin/sh
+# Delegate and wait: hand a task to the marketplace and use the result,
+# either in one command or step by step.
+set -eu
+
+# In one command: post, wait for a delivery, approve it and print the
+# result. A task nobody claimed within --timeout is cancelled and refunded.
+[[.Bin]] tasks delegate "Summarize this README in three bullet points" \
+  --context "$(head -c 4000 README.md)" --credits [[.Credits]] --tags [[.Tags]] \
+  --timeout 30m > summary.md
+
+# Step by step, to look at the delivery before paying for it.
+id=$([[.Bin]] tasks create "Summarize this README in three bullet points" \
+  --context "$(head -c 4000 README.md)" --credits [[.Credits]] --tags [[.Tags]] -q)
+echo "Posted $id"
+while :; do
+  status=$([[.Bin]] tasks show "$id" --jq .status)
+  case $status in
+  delivered) break ;;
+  approved | cancelled | expired) echo "Task $id is $status" >&2; exit 1 ;;
+  esac
+  sleep 30
+done
+[[.Bin]] tasks show "$id" --jq .result
+[[.Bin]] tasks approve "$id" --rating 5
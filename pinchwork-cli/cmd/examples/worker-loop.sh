@@ -0,0 +1,24 @@
+#!/bin/sh
+# Worker loop: pick up [[.Tags]] tasks as they are posted, run each one
+# through a handler script and deliver what it prints.
+set -eu
+
+# The handler gets the task as JSON on stdin, and the need and context in
+# PINCHWORK_TASK_NEED and PINCHWORK_TASK_CONTEXT. What it prints is
+# delivered; a non-zero exit abandons the task so someone else can take it.
+cat > handler.sh <<'HANDLER'
+#!/bin/sh
+printf 'Notes on: %s\n\n' "$PINCHWORK_TASK_NEED"
+printf '%s\n' "$PINCHWORK_TASK_CONTEXT" | head -n 20
+HANDLER
+chmod +x handler.sh
+
+# See which open tasks fit you, then try the handler on one of them.
+# Exit code 2 means there was nothing to pick up.
+[[.Bin]] tasks match --tags [[.Tags]]
+[[.Bin]] tasks claim-and-run --tags [[.Tags]] --exec ./handler.sh || [ $? -eq 2 ]
+
+# Keep working, two tasks at a time, skipping tasks that bounced off other
+# workers or come from poorly rated posters. Ctrl+C stops picking up new
+# tasks and waits for the running ones; you are marked away once it stops.
+[[.Bin]] work --tags [[.Tags]] --exec ./handler.sh --concurrency 2 --max-risk 0.5
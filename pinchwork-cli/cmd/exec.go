@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/jsonschema"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/workerproto"
+)
+
+// execTask runs command with the picked-up task as JSON on stdin and returns
+// its stdout as the result, checked against the task's result schema if it
+// has one. The task is also described in PINCHWORK_TASK_* environment
+// variables for scripts that don't want to parse JSON. The command is killed
+// when ctx is done or, if the task has one, at the claim deadline.
+func execTask(ctx context.Context, command string, task *client.TaskPickupResponse) (string, error) {
+	ctx, cancel := claimContext(ctx, task)
+	defer cancel()
+	sh := taskCommand(ctx, command, task)
+
+	input, err := json.Marshal(task)
+	if err != nil {
+		return "", err
+	}
+	sh.Stdin = bytes.NewReader(input)
+
+	var stdout bytes.Buffer
+	sh.Stdout = &stdout
+	sh.Stderr = os.Stderr
+	if err := sh.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("%q timed out at the claim deadline", command)
+		}
+		return "", fmt.Errorf("%q failed: %w", command, err)
+	}
+
+	result := strings.TrimRight(stdout.String(), "\n")
+	if result == "" {
+		return "", fmt.Errorf("%q produced no output", command)
+	}
+	if err := checkTaskResult(task, result); err != nil {
+		return "", fmt.Errorf("output of %q: %w", command, err)
+	}
+	return result, nil
+}
+
+// execTaskProto runs command speaking the workerproto JSON protocol:
+// questions it asks are sent to the poster as task messages and the replies
+// written to its stdin, and
+// its final output is returned once it exits successfully.
+func execTaskProto(ctx context.Context, c *client.Client, log *slog.Logger, command string, task *client.TaskPickupResponse) (*workerproto.TaskOutput, error) {
+	ctx, cancel := claimContext(ctx, task)
+	defer cancel()
+	sh := taskCommand(ctx, command, task)
+	sh.Stderr = os.Stderr
+	stdin, err := sh.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := sh.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := sh.Start(); err != nil {
+		return nil, fmt.Errorf("start %q: %w", command, err)
+	}
+
+	out, protoErr := speakProto(ctx, c, log, stdin, stdout, task)
+	if protoErr != nil {
+		cancel()
+	}
+	stdin.Close()
+	waitErr := sh.Wait()
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return nil, fmt.Errorf("%q timed out at the claim deadline", command)
+	case protoErr != nil:
+		return nil, fmt.Errorf("%q: %w", command, protoErr)
+	case waitErr != nil:
+		return nil, fmt.Errorf("%q failed: %w", command, waitErr)
+	case out == nil:
+		return nil, fmt.Errorf("%q exited without an output message", command)
+	}
+	if out.Status == workerproto.StatusDone {
+		if err := checkTaskResult(task, out.Result); err != nil {
+			return nil, fmt.Errorf("output of %q: %w", command, err)
+		}
+	}
+	return out, nil
+}
+
+// speakProto sends the task to a handler and serves its questions until it
+// closes stdout, returning its output message.
+func speakProto(ctx context.Context, c *client.Client, log *slog.Logger, w io.Writer, r io.Reader, task *client.TaskPickupResponse) (*workerproto.TaskOutput, error) {
+	send := func(msg interface{}) error {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+
+	input := workerproto.TaskInput{
+		Type:       workerproto.TypeTask,
+		Version:    workerproto.Version,
+		TaskID:     task.TaskID,
+		Need:       task.Need,
+		Context:    task.Context,
+		MaxCredits: task.MaxCredits,
+		PosterID:   task.PosterID,
+		Tags:       task.Tags,
+	}
+	if !task.ClaimDeadline.IsZero() {
+		input.ClaimDeadline = &task.ClaimDeadline.Time
+	}
+	if err := send(input); err != nil {
+		return nil, err
+	}
+
+	var out *workerproto.TaskOutput
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			msg, derr := workerproto.Decode(line)
+			if derr != nil {
+				return nil, derr
+			}
+			switch m := msg.(type) {
+			case *workerproto.Question:
+				if out != nil {
+					return nil, fmt.Errorf("question after the output message")
+				}
+				// The server only takes questions on tasks nobody has
+				// claimed yet, so a claimed task's questions go as messages.
+				q, err := c.SendMessage(task.TaskID, m.Question)
+				if err != nil {
+					return nil, fmt.Errorf("ask question: %w", err)
+				}
+				recordHistory("asked", task.TaskID, nil, m.Question)
+				log.Info("asked the poster, waiting for an answer", "message_id", q.ID)
+				answer, err := waitForAnswer(ctx, c, task.TaskID, task.PosterID, q.ID)
+				if err != nil {
+					return nil, err
+				}
+				log.Info("got an answer", "message_id", q.ID)
+				if err := send(workerproto.Answer{Type: workerproto.TypeAnswer, QuestionID: q.ID, Answer: answer}); err != nil {
+					return nil, err
+				}
+			case *workerproto.TaskOutput:
+				if out != nil {
+					return nil, fmt.Errorf("more than one output message")
+				}
+				if err := m.Validate(); err != nil {
+					return nil, err
+				}
+				out = m
+			default:
+				return nil, fmt.Errorf("handlers can't send %T messages", msg)
+			}
+		}
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitForAnswer polls the task's messages until the poster replies to the
+// message asking a question, or ctx is done. The poster's first message
+// after the question is taken as the answer.
+func waitForAnswer(ctx context.Context, c *client.Client, taskID, posterID, messageID string) (string, error) {
+	for {
+		resp, err := c.ListMessages(taskID)
+		if err != nil {
+			return "", fmt.Errorf("check for an answer: %w", err)
+		}
+		asked := false
+		for _, m := range resp.Messages {
+			if m.ID == messageID {
+				asked = true
+			} else if asked && m.SenderID == posterID {
+				return m.Message, nil
+			}
+		}
+		sleepCtx(ctx, 15*time.Second)
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("no answer to message %s: %w", messageID, ctx.Err())
+		}
+	}
+}
+
+// claimContext ends ctx at the task's claim deadline, if it has one.
+func claimContext(ctx context.Context, task *client.TaskPickupResponse) (context.Context, context.CancelFunc) {
+	if !task.ClaimDeadline.IsZero() {
+		return context.WithDeadline(ctx, task.ClaimDeadline.Time)
+	}
+	return context.WithCancel(ctx)
+}
+
+// taskCommand prepares command to run on a task, described in
+// PINCHWORK_TASK_* environment variables.
+func taskCommand(ctx context.Context, command string, task *client.TaskPickupResponse) *exec.Cmd {
+	var sh *exec.Cmd
+	if runtime.GOOS == "windows" {
+		sh = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		sh = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	detachSignals(sh)
+	sh.Env = append(os.Environ(),
+		"PINCHWORK_TASK_ID="+task.TaskID,
+		"PINCHWORK_TASK_NEED="+task.Need,
+		"PINCHWORK_TASK_CONTEXT="+task.Context,
+		"PINCHWORK_TASK_CREDITS="+strconv.Itoa(task.MaxCredits),
+		"PINCHWORK_TASK_TAGS="+strings.Join(task.Tags, ","),
+	)
+	return sh
+}
+
+// checkTaskResult checks a result against the task's result schema, if it
+// has one.
+func checkTaskResult(task *client.TaskPickupResponse, result string) error {
+	raw, _ := client.ExtractResultSchema(task.Context)
+	if raw == nil {
+		return nil
+	}
+	schema, err := jsonschema.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("task has an unusable result schema: %w", err)
+	}
+	return checkResultSchema(schema, []byte(result))
+}
+
+// waitForReview polls a delivered task until the poster acts on it or
+// timeout passes (zero waits indefinitely). It returns the final task.
+func waitForReview(c *client.Client, taskID string, interval, timeout time.Duration) (*client.TaskResponse, error) {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	for {
+		task, err := c.GetTask(taskID)
+		if err != nil {
+			return nil, err
+		}
+		if task.Status != client.StatusDelivered {
+			return task, nil
+		}
+		if !deadline.IsZero() && time.Now().Add(interval).After(deadline) {
+			return task, nil
+		}
+		time.Sleep(interval)
+	}
+}
+
+// runVerifier pipes a delivered result into command and reports whether it
+// exited 0, along with everything it printed. The task is described in
+// PINCHWORK_TASK_* environment variables.
+func runVerifier(command string, task *client.TaskResponse) (passed bool, out string, err error) {
+	var sh *exec.Cmd
+	if runtime.GOOS == "windows" {
+		sh = exec.Command("cmd", "/C", command)
+	} else {
+		sh = exec.Command("sh", "-c", command)
+	}
+	sh.Stdin = strings.NewReader(task.Result)
+	sh.Env = append(os.Environ(),
+		"PINCHWORK_TASK_ID="+task.TaskID,
+		"PINCHWORK_TASK_NEED="+task.Need,
+		"PINCHWORK_TASK_CONTEXT="+task.Context,
+		"PINCHWORK_TASK_WORKER="+task.WorkerID,
+	)
+
+	combined, err := sh.CombinedOutput()
+	out = strings.TrimSpace(string(combined))
+	var failed *exec.ExitError
+	if errors.As(err, &failed) {
+		return false, out, nil
+	}
+	if err != nil {
+		return false, out, fmt.Errorf("run verifier %q: %w", command, err)
+	}
+	return true, out, nil
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export account data for accounting and archiving",
+}
+
+var exportLedgerCmd = &cobra.Command{
+	Use:   "ledger",
+	Short: "Export your full credit ledger (csv, json or beancount)",
+	Long: `Export your full credit ledger, oldest first, with every entry categorized
+as earnings, fee, escrow, refund, bonus or other.
+
+The beancount format books credits under Assets:Pinchwork:Credits in the
+commodity set by --currency, ready to include from a main ledger file.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+		year, _ := cmd.Flags().GetInt("year")
+		outPath, _ := cmd.Flags().GetString("out")
+		currency, _ := cmd.Flags().GetString("currency")
+
+		var since, until time.Time
+		if year > 0 {
+			since = time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+			until = since.AddDate(1, 0, 0)
+		}
+
+		var entries []ledgerRecord
+		err = c.WalkLedger(since, func(e client.LedgerEntry) error {
+			t, err := client.ParseTime(e.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("ledger entry %s: %w", e.ID, err)
+			}
+			if !until.IsZero() && !t.Before(until) {
+				return nil
+			}
+			entries = append(entries, ledgerRecord{LedgerEntry: e, Time: t, Category: ledgerCategory(e.Reason)})
+			return nil
+		})
+		if err != nil {
+			exitErr(err)
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+
+		w := io.Writer(os.Stdout)
+		if outPath != "" {
+			f, err := os.Create(outPath)
+			if err != nil {
+				exitErr(err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		switch format {
+		case "csv":
+			err = writeLedgerCSV(w, entries)
+		case "json":
+			err = output.JSON(w, entries)
+		case "beancount":
+			err = writeLedgerBeancount(w, entries, currency)
+		default:
+			exitErr(fmt.Errorf("unsupported format %q (supported: csv, json, beancount)", format))
+		}
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outPath != "" {
+			fmt.Fprintf(os.Stderr, "Exported %d ledger entries to %s\n", len(entries), outPath)
+		}
+	},
+}
+
+type ledgerRecord struct {
+	client.LedgerEntry
+	Time     time.Time `json:"-"`
+	Category string    `json:"category"`
+}
+
+func ledgerCategory(reason string) string {
+	switch reason {
+	case client.LedgerPayment:
+		return "earnings"
+	case client.LedgerPlatformFee:
+		return "fee"
+	case client.LedgerEscrow:
+		return "escrow"
+	case client.LedgerRefund:
+		return "refund"
+	case "signup_bonus", "admin_grant":
+		return "bonus"
+	}
+	// Referral bonuses are recorded with the referred agent's ID appended.
+	if strings.HasPrefix(reason, "referral_bonus") {
+		return "bonus"
+	}
+	return "other"
+}
+
+func writeLedgerCSV(w io.Writer, entries []ledgerRecord) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "id", "task_id", "reason", "category", "amount"})
+	for _, e := range entries {
+		cw.Write([]string{
+			e.Time.UTC().Format(time.RFC3339),
+			e.ID,
+			e.TaskID,
+			e.Reason,
+			e.Category,
+			strconv.Itoa(e.Amount),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// beancountAccounts maps ledger categories to the counter-account of each
+// posting; the other leg is always Assets:Pinchwork:Credits.
+var beancountAccounts = map[string]string{
+	"earnings": "Income:Pinchwork:Earnings",
+	"fee":      "Expenses:Pinchwork:Fees",
+	"escrow":   "Expenses:Pinchwork:Tasks",
+	"refund":   "Expenses:Pinchwork:Tasks",
+	"bonus":    "Income:Pinchwork:Bonuses",
+	"other":    "Equity:Pinchwork:Other",
+}
+
+func writeLedgerBeancount(w io.Writer, entries []ledgerRecord, currency string) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	opened := entries[0].Time.UTC().Format("2006-01-02")
+	fmt.Fprintf(w, ";; Pinchwork ledger export, generated %s\n\n", time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "%s commodity %s\n", opened, currency)
+	accounts := []string{"Assets:Pinchwork:Credits"}
+	seen := map[string]bool{}
+	for _, e := range entries {
+		if a := beancountAccounts[e.Category]; !seen[a] {
+			seen[a] = true
+			accounts = append(accounts, a)
+		}
+	}
+	for _, a := range accounts {
+		fmt.Fprintf(w, "%s open %s %s\n", opened, a, currency)
+	}
+
+	for _, e := range entries {
+		narration := e.Reason
+		if e.TaskID != "" {
+			narration += " " + e.TaskID
+		}
+		fmt.Fprintf(w, "\n%s * \"Pinchwork\" %q\n", e.Time.UTC().Format("2006-01-02"), narration)
+		fmt.Fprintf(w, "  id: %q\n", e.ID)
+		fmt.Fprintf(w, "  %-32s %d %s\n", "Assets:Pinchwork:Credits", e.Amount, currency)
+		_, err := fmt.Fprintf(w, "  %-32s %d %s\n", beancountAccounts[e.Category], -e.Amount, currency)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	exportLedgerCmd.Flags().String("format", "csv", "output format: csv, json, beancount")
+	exportLedgerCmd.Flags().Int("year", 0, "only entries from this calendar year (UTC)")
+	exportLedgerCmd.Flags().String("out", "", "write to file instead of stdout")
+	exportLedgerCmd.Flags().String("currency", "PWC", "beancount commodity name for credits")
+
+	exportCmd.AddCommand(exportLedgerCmd)
+	rootCmd.AddCommand(exportCmd)
+}
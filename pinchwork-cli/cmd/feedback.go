@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var feedbackCmd = &cobra.Command{
+	Use:   "feedback",
+	Short: "Browse ratings and feedback you received or gave",
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		given, _ := cmd.Flags().GetBool("given")
+		received, _ := cmd.Flags().GetBool("received")
+		tag, _ := cmd.Flags().GetString("tag")
+		sinceStr, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		since, err := parseSince(sinceStr)
+		if err != nil {
+			exitErr(err)
+		}
+
+		filter := client.FeedbackFilter{Tag: tag, Since: since, Limit: limit}
+		switch {
+		case given && !received:
+			filter.Direction = "given"
+		case received && !given:
+			filter.Direction = "received"
+		}
+
+		resp, err := c.ListFeedback(filter)
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		if len(resp.Feedback) == 0 {
+			fmt.Println("No feedback found.")
+			return
+		}
+
+		headers := []string{"TASK", "RATING", "FROM", "TO", "TAGS", "FEEDBACK", "DATE"}
+		var rows [][]string
+		for _, f := range resp.Feedback {
+			rows = append(rows, []string{
+				f.TaskID,
+				stars(f.Rating),
+				f.FromID,
+				f.ToID,
+				strings.Join(f.Tags, ","),
+				output.Cell(f.Feedback, 50),
+				f.CreatedAt,
+			})
+		}
+		output.Table(os.Stdout, headers, rows)
+		fmt.Printf("\n%d rating(s)", resp.Total)
+		if resp.AverageRating != nil {
+			fmt.Printf(", average %.2f", *resp.AverageRating)
+		}
+		fmt.Println()
+
+		// Weak spots are about your own work, so ratings you gave don't count.
+		switch filter.Direction {
+		case "received":
+			printLowRatedTags(resp.Feedback)
+		case "":
+			me, err := c.GetMe()
+			if err != nil {
+				exitErr(err)
+			}
+			var mine []client.FeedbackItem
+			for _, f := range resp.Feedback {
+				if f.ToID == me.ID {
+					mine = append(mine, f)
+				}
+			}
+			printLowRatedTags(mine)
+		}
+	},
+}
+
+func stars(rating int) string {
+	if rating < 0 {
+		rating = 0
+	}
+	if rating > 5 {
+		rating = 5
+	}
+	return strings.Repeat("★", rating) + strings.Repeat("☆", 5-rating)
+}
+
+// printLowRatedTags lists tags rated below the average of the received
+// ratings in items, so recurring weak spots stand out.
+func printLowRatedTags(items []client.FeedbackItem) {
+	total := 0
+	sums := map[string]int{}
+	counts := map[string]int{}
+	for _, f := range items {
+		total += f.Rating
+		for _, t := range f.Tags {
+			sums[t] += f.Rating
+			counts[t]++
+		}
+	}
+	if len(items) == 0 {
+		return
+	}
+	average := float64(total) / float64(len(items))
+	mean := func(t string) float64 { return float64(sums[t]) / float64(counts[t]) }
+
+	var low []string
+	for t := range counts {
+		if counts[t] >= 2 && mean(t) < average {
+			low = append(low, t)
+		}
+	}
+	if len(low) == 0 {
+		return
+	}
+	sort.Strings(low)
+
+	fmt.Printf("\nTags rated below the average of %.2f:\n", average)
+	for _, t := range low {
+		fmt.Printf("  %-20s %.2f over %d rating(s)\n", t, mean(t), counts[t])
+	}
+}
+
+func init() {
+	feedbackCmd.Flags().Bool("given", false, "only feedback you gave")
+	feedbackCmd.Flags().Bool("received", false, "only feedback you received")
+	feedbackCmd.Flags().String("tag", "", "filter by task tag")
+	feedbackCmd.Flags().String("since", "", "only feedback newer than this (e.g. 7d, 24h, 2025-01-31)")
+	feedbackCmd.Flags().Int("limit", 50, "max results")
+
+	rootCmd.AddCommand(feedbackCmd)
+}
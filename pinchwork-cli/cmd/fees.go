@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// feeBreakdown is what a task of a given size costs and pays out.
+type feeBreakdown struct {
+	Credits     int `json:"credits"`
+	PlatformFee int `json:"platform_fee"`
+	WorkerGets  int `json:"worker_gets"`
+}
+
+func newFeeBreakdown(f *client.Fees, credits int) feeBreakdown {
+	fee := f.PlatformFee(credits)
+	return feeBreakdown{Credits: credits, PlatformFee: fee, WorkerGets: credits - fee}
+}
+
+// serverFees returns the server's fee schedule, or the defaults if it can't
+// be fetched.
+func serverFees(c *client.Client) *client.Fees {
+	fees, err := c.GetFees()
+	if err != nil {
+		f := client.DefaultFees
+		f.Default = true
+		return &f
+	}
+	return fees
+}
+
+// printFees explains the cost of posting a task for credits, on stderr.
+func printFees(f *client.Fees, credits int) {
+	b := newFeeBreakdown(f, credits)
+	if f.Default {
+		fmt.Fprintln(os.Stderr, noFeesNote)
+	}
+	fmt.Fprintf(os.Stderr, "Escrowed from your balance now: %d credits\n", b.Credits)
+	fmt.Fprintf(os.Stderr, "Platform fee on approval:       %d (%s%%, paid out of the worker's share)\n", b.PlatformFee, formatPercent(f.PlatformFeePercent))
+	fmt.Fprintf(os.Stderr, "Worker receives:                %d\n", b.WorkerGets)
+	fmt.Fprintln(os.Stderr, "Credits the worker doesn't claim are refunded to you.")
+}
+
+const noFeesNote = "Server does not publish fees; showing defaults."
+
+func formatPercent(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+var feesCmd = &cobra.Command{
+	Use:   "fees",
+	Short: "Show the marketplace fee schedule",
+	Long: `Show the marketplace fee schedule and what a task of a given size costs.
+
+Posting a task escrows its credits from your balance. When the work is
+approved the platform fee is taken from the worker's payment, so the poster's
+total debit is never more than the task's credits.`,
+	Example: `  pinchwork fees
+  pinchwork fees --credits 500`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClient()
+		if err != nil {
+			exitErr(err)
+		}
+		fees, err := c.GetFees()
+		if err != nil {
+			exitErr(err)
+		}
+		credits, _ := cmd.Flags().GetInt("credits")
+
+		if outputFmt == "json" {
+			out := map[string]interface{}{"platform_fee_percent": fees.PlatformFeePercent, "defaults": fees.Default}
+			if credits > 0 {
+				out["example"] = newFeeBreakdown(fees, credits)
+			}
+			output.JSON(os.Stdout, out)
+			return
+		}
+
+		if fees.Default {
+			fmt.Fprintln(os.Stderr, noFeesNote)
+		}
+		fmt.Printf("Platform fee: %s%% of each approved payment, deducted from the worker's share\n", formatPercent(fees.PlatformFeePercent))
+		fmt.Println("Posting:      free; the task's credits are held in escrow until it is approved")
+		fmt.Println("Refunds:      credits not charged, and cancelled or expired tasks, go back to the poster")
+
+		if credits > 0 {
+			b := newFeeBreakdown(fees, credits)
+			fmt.Printf("\nFor a %d-credit task:\n", credits)
+			output.Table(os.Stdout, []string{"POSTER PAYS", "PLATFORM FEE", "WORKER GETS"}, [][]string{{
+				strconv.Itoa(b.Credits), strconv.Itoa(b.PlatformFee), strconv.Itoa(b.WorkerGets),
+			}})
+		}
+	},
+}
+
+func init() {
+	feesCmd.Flags().Int("credits", 0, "show the breakdown for a task of this many credits")
+	rootCmd.AddCommand(feesCmd)
+}
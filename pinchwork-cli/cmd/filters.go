@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+// taskFilter holds worker-side filters the API doesn't support, applied to
+// available tasks before they are shown or claimed.
+type taskFilter struct {
+	MinCredits          int
+	MinPosterReputation float64
+	MaxRejections       int     // negative means no limit
+	MaxRisk             float64 // see taskRisk; negative means no limit
+
+	// approvals holds poster approval rates for MaxRisk, see withApprovals.
+	approvals map[string]float64
+}
+
+func addTaskFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("min-credits", 0, "skip tasks paying fewer credits")
+	cmd.Flags().Float64("min-poster-reputation", 0, "skip tasks from posters below this reputation")
+	cmd.Flags().Int("max-rejections", -1, "skip tasks rejected more than N times")
+	cmd.Flags().Float64("max-risk", -1, "skip tasks riskier than this, from 0 (safe) to 1 (see the RISK column of 'tasks list')")
+}
+
+func taskFilterFromFlags(cmd *cobra.Command) taskFilter {
+	var f taskFilter
+	f.MinCredits, _ = cmd.Flags().GetInt("min-credits")
+	f.MinPosterReputation, _ = cmd.Flags().GetFloat64("min-poster-reputation")
+	f.MaxRejections, _ = cmd.Flags().GetInt("max-rejections")
+	f.MaxRisk, _ = cmd.Flags().GetFloat64("max-risk")
+	return f
+}
+
+func (f taskFilter) active() bool {
+	return f.MinCredits > 0 || f.MinPosterReputation > 0 || f.MaxRejections >= 0 || f.MaxRisk >= 0
+}
+
+// withApprovals loads the poster approval rates MaxRisk needs.
+func (f taskFilter) withApprovals(c *client.Client) taskFilter {
+	if f.MaxRisk >= 0 && f.approvals == nil {
+		f.approvals = posterApprovals(c)
+	}
+	return f
+}
+
+func (f taskFilter) matches(t client.TaskAvailableItem) bool {
+	if t.MaxCredits < f.MinCredits {
+		return false
+	}
+	if f.MinPosterReputation > 0 && (t.PosterReputation == nil || *t.PosterReputation < f.MinPosterReputation) {
+		return false
+	}
+	if f.MaxRejections >= 0 && t.RejectionCount > f.MaxRejections {
+		return false
+	}
+	if f.MaxRisk >= 0 && taskRisk(t, f.approvals) > f.MaxRisk {
+		return false
+	}
+	return true
+}
+
+// maxFilterPages bounds how far listAvailable pages through the marketplace
+// looking for tasks that pass a client-side filter.
+const maxFilterPages = 10
+
+// listAvailable lists available tasks, applying f client-side. With an active
+// filter it keeps paging until limit tasks match, and Total is the number of
+// matches found rather than the server's count.
+func listAvailable(c *client.Client, tags, search string, limit int, f taskFilter) (*client.TaskAvailableResponse, error) {
+	if !f.active() {
+		return c.ListAvailableTasks(tags, search, limit, 0)
+	}
+
+	f = f.withApprovals(c)
+	const pageSize = 50
+	matched := &client.TaskAvailableResponse{}
+	for page := 0; page < maxFilterPages; page++ {
+		resp, err := c.ListAvailableTasks(tags, search, pageSize, page*pageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range resp.Tasks {
+			if f.matches(t) && len(matched.Tasks) < limit {
+				matched.Tasks = append(matched.Tasks, t)
+			}
+		}
+		if len(matched.Tasks) >= limit || len(resp.Tasks) < pageSize {
+			break
+		}
+	}
+	matched.Total = len(matched.Tasks)
+	return matched, nil
+}
+
+// claimFirst lists available tasks and claims the first one accept returns
+// true for, skipping tasks another worker grabbed in the meantime. It returns
+// a nil task when nothing was accepted.
+func claimFirst(c *client.Client, tags, search string, accept func(client.TaskAvailableItem) bool) (*client.TaskPickupResponse, error) {
+	list, err := c.ListAvailableTasks(tags, search, 50, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range list.Tasks {
+		if !accept(item) {
+			continue
+		}
+		task, err := c.PickupSpecificTask(item.TaskID)
+		if errors.Is(err, client.ErrNotFound) || errors.Is(err, client.ErrTaskNotClaimable) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			return task, nil
+		}
+	}
+	return nil, nil
+}
+
+// pickupFiltered claims the next task like PickupTask, honouring f.
+func pickupFiltered(c *client.Client, tags, search string, f taskFilter) (*client.TaskPickupResponse, error) {
+	if !f.active() {
+		return c.PickupTask(tags, search)
+	}
+	return claimFirst(c, tags, search, f.withApprovals(c).matches)
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// docItem is an entry of the extra reference sections gen-docs adds to the
+// main page.
+type docItem struct {
+	Name, Text string
+}
+
+var docExitCodes = []docItem{
+	{"0", "The command succeeded."},
+	{"1", "The command failed: bad usage, an API or network error, a declined confirmation, or a failing --jq filter."},
+	{strconv.Itoa(exitNoTask), "tasks claim-and-run found no task to pick up."},
+	{strconv.Itoa(exitRejected), "tasks claim-and-run --wait-review: the delivery was rejected."},
+	{strconv.Itoa(exitReviewPending), "tasks claim-and-run --wait-review: the delivery was still awaiting review when --review-timeout passed."},
+}
+
+var docEnvVars = []docItem{
+	{"PINCHWORK_API_KEY", "API key to use when neither --key nor the profile has one."},
+	{"PINCHWORK_SERVER", "Server URL to use when neither --server nor the profile sets one."},
+	{"PINCHWORK_ADMIN_KEY", "Admin key for admin commands when neither --key nor the profile has one."},
+	{"PINCHWORK_SANDBOX_SERVER", "Server used by --sandbox."},
+	{"PINCHWORK_NO_INPUT", "When set, act as if --no-input was given: fail instead of prompting."},
+	{"PINCHWORK_NO_VERSION_CHECK", "When set, skip the daily check for supported client versions."},
+	{"PINCHWORK_BRIDGE_SECRET", "Secret for bridge when --secret isn't given."},
+	{"SLACK_BOT_TOKEN, SLACK_SIGNING_SECRET", "Slack credentials for slack serve."},
+	{"GITHUB_TOKEN", "Token for fetching pull requests given to tasks create --pr."},
+	{"GITHUB_ACTIONS, GITHUB_OUTPUT, GITHUB_STEP_SUMMARY", "Read by ci delegate to write step outputs and a job summary."},
+	{"VISUAL, EDITOR", "Editor for the context in tasks create -i."},
+	{"LC_ALL, LC_NUMERIC, LANG", "Locale for a bare --locale."},
+	{"NO_COLOR", "When set, print no colors."},
+	{"COLUMNS", "Terminal width, when it can't be asked from the terminal."},
+	{"XDG_CONFIG_HOME", "Base of the config directory on Linux (default ~/.config)."},
+}
+
+// markdownSections renders the exit codes and environment as Markdown.
+func markdownSections() string {
+	var b strings.Builder
+	b.WriteString("\n### Exit codes\n\n")
+	for _, e := range docExitCodes {
+		fmt.Fprintf(&b, "* `%s`: %s\n", e.Name, e.Text)
+	}
+	b.WriteString("\n### Environment\n\n")
+	for _, e := range docEnvVars {
+		fmt.Fprintf(&b, "* `%s`: %s\n", strings.ReplaceAll(e.Name, ", ", "`, `"), e.Text)
+	}
+	return b.String()
+}
+
+// manSections renders the exit codes and environment as roff.
+func manSections() string {
+	esc := func(s string) string {
+		return strings.NewReplacer(`\`, `\\`, "-", `\-`).Replace(s)
+	}
+	var b strings.Builder
+	b.WriteString(".SH EXIT STATUS\n")
+	for _, e := range docExitCodes {
+		fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", e.Name, esc(e.Text))
+	}
+	b.WriteString(".SH ENVIRONMENT\n")
+	for _, e := range docEnvVars {
+		fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", esc(e.Name), esc(e.Text))
+	}
+	return b.String()
+}
+
+var genDocsCmd = &cobra.Command{
+	Use:   "gen-docs DIR",
+	Short: "Generate man pages or Markdown reference docs",
+	Long: `Write a reference page for every command to DIR: man pages in section 1
+with --format man, or Markdown with --format markdown. The page of the
+pinchwork command itself also lists the exit codes and environment
+variables.
+
+Man pages are dated SOURCE_DATE_EPOCH when it is set, for reproducible
+packages.`,
+	Example: `  pinchwork gen-docs --format man /usr/share/man/man1
+  pinchwork gen-docs --format markdown docs/reference`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		dir := args[0]
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			exitErr(err)
+		}
+
+		// The generated-on footer would make every build differ.
+		var noGenTag func(c *cobra.Command)
+		noGenTag = func(c *cobra.Command) {
+			c.DisableAutoGenTag = true
+			for _, sub := range c.Commands() {
+				noGenTag(sub)
+			}
+		}
+		noGenTag(rootCmd)
+
+		var err error
+		switch format {
+		case "man":
+			header := &doc.GenManHeader{Title: "PINCHWORK", Section: "1", Source: "Pinchwork " + rootCmd.Version, Manual: "Pinchwork Manual"}
+			if err = doc.GenManTree(rootCmd, header, dir); err == nil {
+				err = appendFile(filepath.Join(dir, "pinchwork.1"), manSections())
+			}
+		case "markdown":
+			if err = doc.GenMarkdownTree(rootCmd, dir); err == nil {
+				err = appendFile(filepath.Join(dir, "pinchwork.md"), markdownSections())
+			}
+		default:
+			exitErr(fmt.Errorf("--format must be man or markdown"))
+		}
+		if err != nil {
+			exitErr(err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s docs to %s\n", format, dir)
+	},
+}
+
+func init() {
+	genDocsCmd.Flags().String("format", "man", "man or markdown")
+
+	rootCmd.AddCommand(genDocsCmd)
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// diffFromFlags returns the patch selected by tasks create's --from-diff or
+// --pr, and a short description of where it came from.
+func diffFromFlags(cmd *cobra.Command) (source, diff string, err error) {
+	pr, _ := cmd.Flags().GetString("pr")
+	if pr != "" {
+		if cmd.Flags().Changed("from-diff") {
+			return "", "", fmt.Errorf("use either --from-diff or --pr")
+		}
+		diff, err = fetchPRPatch(pr)
+		return pr, diff, err
+	}
+	if !cmd.Flags().Changed("from-diff") {
+		return "", "", nil
+	}
+	rev, _ := cmd.Flags().GetString("from-diff")
+	out, err := exec.Command("git", "diff", rev).Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", "", fmt.Errorf("git diff %s: %s", rev, strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", "", fmt.Errorf("git diff %s: %w", rev, err)
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		return "", "", fmt.Errorf("git diff %s is empty: nothing to review", rev)
+	}
+	return "git diff " + rev, string(out), nil
+}
+
+var githubPRRe = regexp.MustCompile(`^https://github\.com/([^/]+)/([^/]+)/pull/(\d+)`)
+
+// fetchPRPatch downloads a pull request as a patch. GitHub PR URLs go
+// through the API when GITHUB_TOKEN is set, so private repositories work;
+// other URLs are fetched as they are and should point at a patch.
+func fetchPRPatch(prURL string) (string, error) {
+	u, err := url.Parse(prURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return "", fmt.Errorf("--pr must be an http(s) URL")
+	}
+	req, err := http.NewRequest("GET", prURL, nil)
+	if err != nil {
+		return "", err
+	}
+	if m := githubPRRe.FindStringSubmatch(prURL); m != nil {
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			req, _ = http.NewRequest("GET", fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%s", m[1], m[2], m[3]), nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Accept", "application/vnd.github.v3.patch")
+		} else {
+			req, _ = http.NewRequest("GET", m[0]+".patch", nil)
+		}
+	}
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("fetch %s: %s", prURL, resp.Status)
+	}
+	if !strings.Contains(string(data), "diff --git") {
+		return "", fmt.Errorf("%s did not return a patch", prURL)
+	}
+	return string(data), nil
+}
+
+// diffContext puts a patch into the task context, after any context the
+// poster wrote, fenced so workers and apply-result can find it again.
+func diffContext(context, source, diff string) string {
+	section := fmt.Sprintf("Changes (%s):\n\n```diff\n%s\n```", source, strings.TrimRight(diff, "\n"))
+	if strings.TrimSpace(context) == "" {
+		return section
+	}
+	return strings.TrimRight(context, "\n") + "\n\n" + section
+}
+
+var diffFileRe = regexp.MustCompile(`(?m)^diff --git a/(\S+) b/`)
+
+var extLanguages = map[string]string{
+	".go": "go", ".py": "python", ".js": "javascript", ".jsx": "javascript",
+	".ts": "typescript", ".tsx": "typescript", ".rs": "rust", ".java": "java",
+	".kt": "kotlin", ".rb": "ruby", ".php": "php", ".cs": "csharp",
+	".c": "c", ".h": "c", ".cpp": "cpp", ".cc": "cpp", ".swift": "swift",
+	".sh": "shell", ".sql": "sql", ".md": "docs", ".rst": "docs",
+	".tf": "terraform", ".yaml": "yaml", ".yml": "yaml",
+}
+
+// diffTags suggests tags for a review of diff: code-review and the
+// languages of the changed files, most changed first.
+func diffTags(diff string) []string {
+	counts := map[string]int{}
+	var order []string
+	for _, m := range diffFileRe.FindAllStringSubmatch(diff, -1) {
+		lang, ok := extLanguages[strings.ToLower(filepath.Ext(m[1]))]
+		if !ok {
+			continue
+		}
+		if counts[lang] == 0 {
+			order = append(order, lang)
+		}
+		counts[lang]++
+	}
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	if len(order) > 3 {
+		order = order[:3]
+	}
+	return append([]string{"code-review"}, order...)
+}
+
+// mergeTags adds extra to a comma-separated tag list, skipping duplicates.
+func mergeTags(tags string, extra []string) string {
+	var out []string
+	seen := map[string]bool{}
+	for _, t := range append(strings.Split(tags, ","), extra...) {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return strings.Join(out, ",")
+}
+
+var fencedPatchRe = regexp.MustCompile("(?s)```(?:diff|patch)\\s*\\n(.*?)\\n```")
+
+// extractPatch finds the patch in a delivered result: the first ```diff
+// block, or the whole result if it is a bare patch.
+func extractPatch(result string) (string, bool) {
+	if m := fencedPatchRe.FindStringSubmatch(result); m != nil {
+		return m[1] + "\n", true
+	}
+	trimmed := strings.TrimSpace(result)
+	if strings.HasPrefix(trimmed, "diff --git") || strings.HasPrefix(trimmed, "--- ") || strings.HasPrefix(trimmed, "From ") {
+		return trimmed + "\n", true
+	}
+	return "", false
+}
+
+var tasksApplyResultCmd = &cobra.Command{
+	Use:   "apply-result TASK_ID",
+	Short: "Apply the patch in a task's result to the working tree",
+	Long: `Apply the patch a worker delivered to the git working tree in the current
+directory, with 'git apply'. The patch is the first fenced diff block in
+the result, or the whole result if it is a bare patch. Nothing is changed
+unless the whole patch applies.`,
+	Example: `  pinchwork tasks create "Fix the failing test" --from-diff --credits 80
+  pinchwork tasks apply-result tk-abc123 --check
+  pinchwork tasks apply-result tk-abc123 --3way`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		task, err := c.GetTask(args[0])
+		if err != nil {
+			exitErr(err)
+		}
+		if task.Result == "" {
+			exitErr(fmt.Errorf("task %s has no result yet (status: %s)", task.TaskID, task.Status))
+		}
+		patch, ok := extractPatch(task.Result)
+		if !ok {
+			exitErr(fmt.Errorf("the result of task %s does not contain a patch", task.TaskID))
+		}
+
+		check, _ := cmd.Flags().GetBool("check")
+		threeWay, _ := cmd.Flags().GetBool("3way")
+		gitArgs := []string{"apply", "--stat", "--apply"}
+		if check {
+			gitArgs = []string{"apply", "--stat", "--check"}
+		}
+		if threeWay {
+			gitArgs = append(gitArgs, "--3way")
+		}
+		git := exec.Command("git", gitArgs...)
+		git.Stdin = strings.NewReader(patch)
+		git.Stdout = os.Stdout
+		git.Stderr = os.Stderr
+		if err := git.Run(); err != nil {
+			exitErr(fmt.Errorf("git apply: %w", err))
+		}
+		if check {
+			fmt.Fprintf(os.Stderr, "The patch from task %s applies cleanly.\n", task.TaskID)
+			return
+		}
+		recordHistory("applied", task.TaskID, nil, "")
+		fmt.Fprintf(os.Stderr, "Applied the patch from task %s.\n", task.TaskID)
+	},
+}
+
+func init() {
+	tasksApplyResultCmd.Flags().Bool("check", false, "only check that the patch applies")
+	tasksApplyResultCmd.Flags().Bool("3way", false, "fall back to a three-way merge, leaving conflict markers")
+
+	tasksCmd.AddCommand(tasksApplyResultCmd)
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/history"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// commandPath is the running command ("pinchwork tasks deliver"), recorded
+// with each history entry so automation can be told apart from manual use.
+var commandPath string
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the changes this CLI made on the marketplace",
+	Long: `Every command that changes something on the marketplace (creating,
+claiming, delivering, approving tasks, and so on) is recorded in a local
+append-only log per profile. This shows it, oldest first.`,
+	Example: `  pinchwork history --since 24h
+  pinchwork history --task tk-abc123
+  pinchwork history --action approved --since 2025-01-01 -o json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceStr, _ := cmd.Flags().GetString("since")
+		action, _ := cmd.Flags().GetString("action")
+		taskID, _ := cmd.Flags().GetString("task")
+
+		since, err := parseSince(sinceStr)
+		if err != nil {
+			exitErr(err)
+		}
+		all, err := history.Read(historyPath(), since)
+		if err != nil {
+			exitErr(fmt.Errorf("read history: %w", err))
+		}
+		entries := []history.Entry{}
+		for _, e := range all {
+			if (action == "" || e.Action == action) && (taskID == "" || e.TaskID == taskID) {
+				entries = append(entries, e)
+			}
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, entries)
+			return
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No history.")
+			return
+		}
+
+		headers := []string{"TIME", "ACTION", "TASK", "CREDITS", "DETAIL", "COMMAND"}
+		var rows [][]string
+		for _, e := range entries {
+			credits := ""
+			if e.Credits != nil {
+				credits = fmt.Sprintf("%d", *e.Credits)
+			}
+			rows = append(rows, []string{
+				historyTime(e.Time),
+				e.Action,
+				e.TaskID,
+				credits,
+				output.Cell(strings.ReplaceAll(e.Detail, "\n", " "), 40),
+				strings.TrimPrefix(e.Command, "pinchwork "),
+			})
+		}
+		output.Table(os.Stdout, headers, rows)
+	},
+}
+
+// historyTime keeps the seconds an audit log needs, unless --locale asks
+// for the local format.
+func historyTime(t time.Time) string {
+	if output.Localized() {
+		return output.DateTime(t)
+	}
+	return output.InZone(t).Format("2006-01-02 15:04:05")
+}
+
+var (
+	historyMu       sync.Mutex
+	historyFile     string
+	historyFileOnce sync.Once
+)
+
+func historyPath() string {
+	historyFileOnce.Do(func() {
+		name := profile
+		if cfg, err := loadConfig(); err == nil {
+			_, name = cfg.ActiveProfile(profile)
+		}
+		historyFile = profileHistoryPath(name)
+	})
+	return historyFile
+}
+
+func profileHistoryPath(profName string) string {
+	return filepath.Join(filepath.Dir(configPath()), "history", profName+".jsonl")
+}
+
+// recordHistory logs a change made on the marketplace. The change already
+// happened, so failing to record it only warns.
+func recordHistory(action, taskID string, credits *int, detail string) {
+	appendHistory(history.Entry{Action: action, TaskID: taskID, Credits: credits, Detail: detail})
+}
+
+// recordCreated logs a posted task with its tags, which 'stats posting'
+// groups by.
+func recordCreated(taskID string, credits *int, need string, tags []string) {
+	appendHistory(history.Entry{Action: "created", TaskID: taskID, Credits: credits, Detail: need, Tags: tags})
+}
+
+// recordClaimed is recordCreated for claimed tasks, for 'stats working'.
+func recordClaimed(taskID string, credits *int, need string, tags []string) {
+	appendHistory(history.Entry{Action: "claimed", TaskID: taskID, Credits: credits, Detail: need, Tags: tags})
+}
+
+func appendHistory(e history.Entry) {
+	appendHistoryTo(historyPath(), e)
+}
+
+// appendHistoryTo logs to the history file at path, for changes made with
+// another profile than the active one.
+func appendHistoryTo(path string, e history.Entry) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	e.Time = time.Now().UTC()
+	e.Command = commandPath
+	if err := history.Append(path, e); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not record history: %s\n", err)
+	}
+}
+
+func init() {
+	historyCmd.Flags().String("since", "", "only entries newer than this (e.g. 24h, 7d, 2025-01-31)")
+	historyCmd.Flags().String("action", "", "only this action (e.g. created, delivered, approved)")
+	historyCmd.Flags().String("task", "", "only entries for this task")
+
+	rootCmd.AddCommand(historyCmd)
+}
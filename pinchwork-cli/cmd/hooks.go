@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+)
+
+// hookEvents maps SSE event types to the lifecycle hook they trigger. The
+// server sends no event when someone claims your task, so the pickup hook
+// only runs for your own claims, from the commands that make them.
+var hookEvents = map[string]string{
+	"task_delivered": "delivered",
+	"task_approved":  "approved",
+	"task_rejected":  "rejected",
+}
+
+// hookTask is the task data exposed to hooks. Fields that aren't known for a
+// given action are left empty.
+type hookTask struct {
+	TaskID  string
+	Status  client.TaskStatus
+	Need    string
+	Credits int
+	Data    interface{}
+}
+
+// runHook runs the active profile's hook for event, if any. The task is
+// passed in PINCHWORK_* environment variables, with the full JSON payload in
+// PINCHWORK_TASK_JSON. Hook failures are reported but never fail the command.
+func runHook(event string, task hookTask) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return
+	}
+	p, _ := cfg.ActiveProfile(profile)
+	command := p.Hooks.Command(event)
+	if command == "" {
+		return
+	}
+
+	var sh *exec.Cmd
+	if runtime.GOOS == "windows" {
+		sh = exec.Command("cmd", "/C", command)
+	} else {
+		sh = exec.Command("sh", "-c", command)
+	}
+	payload, _ := json.Marshal(task.Data)
+	sh.Env = append(os.Environ(),
+		"PINCHWORK_EVENT="+event,
+		"PINCHWORK_TASK_ID="+task.TaskID,
+		"PINCHWORK_TASK_STATUS="+string(task.Status),
+		"PINCHWORK_TASK_NEED="+task.Need,
+		"PINCHWORK_TASK_CREDITS="+strconv.Itoa(task.Credits),
+		"PINCHWORK_TASK_JSON="+string(payload),
+	)
+	// Hooks write to stderr so they never corrupt -o json output.
+	sh.Stdout = os.Stderr
+	sh.Stderr = os.Stderr
+	if err := sh.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: on_%s hook failed: %s\n", event, err)
+	}
+}
+
+func taskHook(t *client.TaskResponse) hookTask {
+	h := hookTask{TaskID: t.TaskID, Status: t.Status, Need: t.Need, Data: t}
+	if t.CreditsCharged != nil {
+		h.Credits = *t.CreditsCharged
+	}
+	return h
+}
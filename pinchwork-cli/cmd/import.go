@@ -0,0 +1,470 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/jira"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/linear"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/secrets"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/signing"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// ticket is an issue tracker ticket to post as a task.
+type ticket struct {
+	Key         string
+	Title       string
+	Description string
+	URL         string
+}
+
+// tracker is an issue tracker that tickets are imported from and that hears
+// back how their tasks are doing.
+type tracker interface {
+	Comment(key, text string) error
+	// Start and Finish move a ticket along when its task is claimed and
+	// when it is approved.
+	Start(key string) error
+	Finish(key string) error
+}
+
+// jiraTracker moves tickets to statuses by name, since Jira workflows are
+// configured per project.
+type jiraTracker struct {
+	c                *jira.Client
+	inProgress, done string
+}
+
+func (t *jiraTracker) Comment(key, text string) error { return t.c.AddComment(key, text) }
+func (t *jiraTracker) Start(key string) error         { return t.c.TransitionTo(key, t.inProgress) }
+func (t *jiraTracker) Finish(key string) error        { return t.c.TransitionTo(key, t.done) }
+
+type linearTracker struct {
+	c *linear.Client
+}
+
+func (t *linearTracker) Comment(key, text string) error { return t.c.AddComment(key, text) }
+func (t *linearTracker) Start(key string) error         { return t.c.MoveToStateType(key, "started") }
+func (t *linearTracker) Finish(key string) error        { return t.c.MoveToStateType(key, "completed") }
+
+var trackerHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+func newJiraClient() (*jira.Client, *jiraTracker) {
+	cfg, err := loadConfig()
+	if err != nil {
+		exitErr(err)
+	}
+	p, name := activeProfile(cfg)
+	if p.Jira.URL == "" || p.Jira.Email == "" || p.Jira.Token == "" {
+		exitErr(fmt.Errorf("set jira url, email and token in profile %s of %s", name, configPath()))
+	}
+	c := &jira.Client{BaseURL: p.Jira.URL, Email: p.Jira.Email, Token: p.Jira.Token, HTTPClient: trackerHTTPClient}
+	t := &jiraTracker{c: c, inProgress: p.Jira.InProgressStatus, done: p.Jira.DoneStatus}
+	if t.inProgress == "" {
+		t.inProgress = "In Progress"
+	}
+	if t.done == "" {
+		t.done = "Done"
+	}
+	return c, t
+}
+
+func newLinearClient() *linear.Client {
+	cfg, err := loadConfig()
+	if err != nil {
+		exitErr(err)
+	}
+	p, name := activeProfile(cfg)
+	if p.Linear.Token == "" {
+		exitErr(fmt.Errorf("set linear token in profile %s of %s", name, configPath()))
+	}
+	return &linear.Client{Token: p.Linear.Token, HTTPClient: trackerHTTPClient}
+}
+
+type importedTicket struct {
+	Tracker string `json:"tracker"`
+	Key     string `json:"key"`
+	URL     string `json:"url,omitempty"`
+	Need    string `json:"need"`
+	TaskID  string `json:"task_id,omitempty"`
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// importTickets posts a task for each ticket that wasn't imported yet and
+// comments on the ticket with the task it became.
+func importTickets(cmd *cobra.Command, trackerName string, tr tracker, tickets []ticket) {
+	credits, _ := cmd.Flags().GetInt("credits")
+	tagsFlag, _ := cmd.Flags().GetString("tags")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	allowSecrets, _ := cmd.Flags().GetBool("allow-secrets")
+	if credits <= 0 {
+		exitErr(fmt.Errorf("--credits is required"))
+	}
+	var tags []string
+	if tagsFlag != "" {
+		tags = strings.Split(tagsFlag, ",")
+	}
+
+	c, err := newClientRequired()
+	if err != nil {
+		exitErr(err)
+	}
+	st, path := loadState()
+
+	// A ticket given by key can match the query as well; post it once.
+	seen := map[string]bool{}
+	var unique []ticket
+	for _, t := range tickets {
+		if !seen[t.Key] {
+			seen[t.Key] = true
+			unique = append(unique, t)
+		}
+	}
+	tickets = unique
+
+	var results []importedTicket
+	var todo []int
+	for _, t := range tickets {
+		r := importedTicket{Tracker: trackerName, Key: t.Key, URL: t.URL, Need: t.Title}
+		if id, ok := st.ImportedTask(trackerName, t.Key); ok {
+			r.TaskID, r.Skipped = id, "already imported"
+		} else if !allowSecrets && (len(secrets.ScanString(t.Title)) > 0 || len(secrets.ScanString(t.Description)) > 0) {
+			r.Skipped = "appears to contain secrets (--allow-secrets to post it anyway)"
+		} else {
+			todo = append(todo, len(results))
+		}
+		results = append(results, r)
+	}
+
+	if limit, total := confirmCreditsThreshold(), len(todo)*credits; !dryRun && limit > 0 && total > limit {
+		if !confirm(cmd, fmt.Sprintf("Post %d tasks for %s credits in total?", len(todo), output.Number(total))) {
+			exitErr(fmt.Errorf("aborted"))
+		}
+	}
+
+	failed := false
+	if dryRun {
+		todo = nil
+	}
+	for _, i := range todo {
+		r, t := &results[i], tickets[i]
+		context := t.Description
+		if t.URL != "" {
+			context = strings.TrimSpace(context + "\n\nTicket: " + t.URL)
+		}
+		resp, err := c.CreateTask(client.TaskCreateRequest{Need: t.Title, Context: context, MaxCredits: credits, Tags: tags})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %s\n", t.Key, err)
+			r.Skipped, failed = "error: "+err.Error(), true
+			continue
+		}
+		r.TaskID = resp.TaskID
+		recordCreated(resp.TaskID, &credits, t.Title, tags)
+		if st.Imports == nil {
+			st.Imports = map[string]state.Import{}
+		}
+		st.Imports[resp.TaskID] = state.Import{Tracker: trackerName, Key: t.Key, URL: t.URL, Status: string(resp.Status), ImportedAt: time.Now().UTC()}
+		saveState(st, path)
+		note := fmt.Sprintf("Posted to Pinchwork as task %s for %d credits: %s", resp.TaskID, credits, c.TaskWebURL(resp.TaskID))
+		if err := tr.Comment(t.Key, note); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: could not comment: %s\n", t.Key, err)
+		}
+	}
+
+	if outputFmt == "json" {
+		output.JSON(os.Stdout, results)
+	} else if quiet {
+		for _, r := range results {
+			if r.Skipped == "" && r.TaskID != "" {
+				printIDs(r.TaskID)
+			}
+		}
+	} else if len(results) == 0 {
+		fmt.Println("No tickets found.")
+	} else {
+		var rows [][]string
+		for _, r := range results {
+			outcome := r.TaskID
+			if r.Skipped != "" {
+				outcome = "skipped: " + r.Skipped
+				if r.TaskID != "" {
+					outcome += " as " + r.TaskID
+				}
+			} else if dryRun {
+				outcome = "would post"
+			}
+			rows = append(rows, []string{r.Key, output.Cell(r.Need, 50), outcome})
+		}
+		output.Table(os.Stdout, []string{"TICKET", "NEED", "TASK"}, rows)
+		if !dryRun && len(todo) > 0 {
+			fmt.Println("\nReport progress back to the tickets with 'pinchwork import sync'.")
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// importUpdate is the comment for a ticket whose task went from status
+// from to the task's current status.
+func importUpdate(from client.TaskStatus, task *client.TaskResponse) string {
+	switch task.Status {
+	case client.StatusOpen:
+		if from == client.StatusDelivered {
+			return "The delivery was rejected; the task is open on Pinchwork again."
+		}
+		return "The worker gave the task back; it is open on Pinchwork again."
+	case client.StatusClaimed:
+		if from == client.StatusDelivered {
+			return "The delivery was rejected and went back to the worker."
+		}
+		return fmt.Sprintf("Claimed on Pinchwork by agent %s.", task.WorkerID)
+	case client.StatusDelivered:
+		return "Delivered on Pinchwork; awaiting review."
+	case client.StatusApproved:
+		result := task.Result
+		if body, _, err := signing.Split(result); err == nil {
+			result = body
+		}
+		// Jira comments are limited to 32767 characters.
+		return "Approved on Pinchwork. Result:\n\n" + output.Truncate(result, 30000)
+	}
+	return fmt.Sprintf("The task is %s on Pinchwork.", task.Status)
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Post issue tracker tickets as tasks",
+	Long: `Post tickets from Jira or Linear as tasks, and report how the tasks are
+doing back on the tickets with 'import sync'.
+
+Each ticket becomes a task with its title as the need and its description
+and link as the context, and gets a comment with the task. Tickets already
+imported are skipped, so an import can run again on the same query.
+
+The tracker's credentials go in the profile in the config file:
+
+  profiles:
+    default:
+      jira:
+        url: https://acme.atlassian.net
+        email: you@acme.com
+        token: ATATT...
+        in_progress_status: In Progress   # the default
+        done_status: Done                 # the default
+      linear:
+        token: lin_api_...`,
+}
+
+var importJiraCmd = &cobra.Command{
+	Use:   "jira [KEY...]",
+	Short: "Post Jira issues as tasks",
+	Example: `  pinchwork import jira DOC-12 DOC-15 --credits 30
+  pinchwork import jira --jql 'project = DOC AND labels = pinchwork AND status = "To Do"' --credits 20 --tags docs`,
+	Run: func(cmd *cobra.Command, args []string) {
+		jql, _ := cmd.Flags().GetString("jql")
+		limit, _ := cmd.Flags().GetInt("limit")
+		if jql == "" && len(args) == 0 {
+			exitErr(fmt.Errorf("give issue keys or --jql"))
+		}
+		jc, tr := newJiraClient()
+		var issues []jira.Issue
+		for _, key := range args {
+			issue, err := jc.Issue(key)
+			if err != nil {
+				exitErr(err)
+			}
+			issues = append(issues, *issue)
+		}
+		if jql != "" {
+			found, err := jc.Search(jql, limit)
+			if err != nil {
+				exitErr(err)
+			}
+			issues = append(issues, found...)
+		}
+		tickets := make([]ticket, len(issues))
+		for i, issue := range issues {
+			tickets[i] = ticket{Key: issue.Key, Title: issue.Summary, Description: issue.Description, URL: jc.BrowseURL(issue.Key)}
+		}
+		importTickets(cmd, "jira", tr, tickets)
+	},
+}
+
+var importLinearCmd = &cobra.Command{
+	Use:   "linear [ID...]",
+	Short: "Post Linear issues as tasks",
+	Example: `  pinchwork import linear ENG-101 --credits 30
+  pinchwork import linear --team ENG --label pinchwork --credits 20`,
+	Run: func(cmd *cobra.Command, args []string) {
+		team, _ := cmd.Flags().GetString("team")
+		label, _ := cmd.Flags().GetString("label")
+		limit, _ := cmd.Flags().GetInt("limit")
+		if team == "" && label == "" && len(args) == 0 {
+			exitErr(fmt.Errorf("give issue IDs, --team or --label"))
+		}
+		lc := newLinearClient()
+		var issues []linear.Issue
+		for _, id := range args {
+			issue, err := lc.Issue(id)
+			if err != nil {
+				exitErr(err)
+			}
+			issues = append(issues, *issue)
+		}
+		if team != "" || label != "" {
+			found, err := lc.Issues(team, label, limit)
+			if err != nil {
+				exitErr(err)
+			}
+			issues = append(issues, found...)
+		}
+		tickets := make([]ticket, len(issues))
+		for i, issue := range issues {
+			tickets[i] = ticket{Key: issue.Identifier, Title: issue.Title, Description: issue.Description, URL: issue.URL}
+		}
+		importTickets(cmd, "linear", &linearTracker{c: lc}, tickets)
+	},
+}
+
+var importSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Report task progress back on imported tickets",
+	Long: `Comment on each imported ticket whose task changed status since the last
+sync, and move the ticket along: to in progress when the task is claimed
+and to done when it is approved, with the result in the comment. Tickets
+are no longer followed once their task is approved, cancelled or expired.
+
+Run it from cron or after reviewing to keep the tickets current.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		st, path := loadState()
+		ids := make([]string, 0, len(st.Imports))
+		for id := range st.Imports {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		trackers := map[string]tracker{}
+		getTracker := func(name string) tracker {
+			if tr, ok := trackers[name]; ok {
+				return tr
+			}
+			switch name {
+			case "jira":
+				_, trackers[name] = newJiraClient()
+			case "linear":
+				trackers[name] = &linearTracker{c: newLinearClient()}
+			}
+			return trackers[name]
+		}
+
+		type syncRow struct {
+			Tracker string `json:"tracker"`
+			Key     string `json:"key"`
+			TaskID  string `json:"task_id"`
+			From    string `json:"from"`
+			Status  string `json:"status"`
+			Error   string `json:"error,omitempty"`
+		}
+		var rows []syncRow
+		failed := false
+		for _, id := range ids {
+			imp := st.Imports[id]
+			row := syncRow{Tracker: imp.Tracker, Key: imp.Key, TaskID: id, From: imp.Status, Status: imp.Status}
+			task, err := c.GetTask(id)
+			if err == nil && string(task.Status) != imp.Status {
+				row.Status = string(task.Status)
+				tr := getTracker(imp.Tracker)
+				if tr == nil {
+					err = fmt.Errorf("unknown tracker %q", imp.Tracker)
+				} else {
+					switch task.Status {
+					case client.StatusClaimed:
+						if imp.Status == string(client.StatusOpen) {
+							err = tr.Start(imp.Key)
+						}
+					case client.StatusApproved:
+						err = tr.Finish(imp.Key)
+					}
+					if err == nil {
+						err = tr.Comment(imp.Key, importUpdate(client.TaskStatus(imp.Status), task))
+					}
+				}
+				if err == nil {
+					switch task.Status {
+					case client.StatusApproved, client.StatusCancelled, client.StatusExpired:
+						delete(st.Imports, id)
+					default:
+						imp.Status = string(task.Status)
+						st.Imports[id] = imp
+					}
+					saveState(st, path)
+				}
+			}
+			if err != nil {
+				row.Error, failed = err.Error(), true
+			}
+			rows = append(rows, row)
+		}
+
+		if outputFmt == "json" {
+			if rows == nil {
+				rows = []syncRow{}
+			}
+			output.JSON(os.Stdout, rows)
+		} else if len(rows) == 0 {
+			fmt.Println("No imported tickets to sync.")
+		} else {
+			var table [][]string
+			for _, r := range rows {
+				update := "unchanged"
+				switch {
+				case r.Error != "":
+					update = "error: " + r.Error
+				case r.Status != r.From:
+					update = r.From + " → " + r.Status
+				}
+				table = append(table, []string{r.Key, r.TaskID, r.Status, update})
+			}
+			output.Table(os.Stdout, []string{"TICKET", "TASK", "STATUS", "UPDATE"}, table)
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func addImportFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("credits", 0, "credits to offer for each task (required)")
+	cmd.Flags().String("tags", "", "tags for the tasks (comma-separated)")
+	cmd.Flags().Int("limit", 20, "max tickets to import from a query")
+	cmd.Flags().Bool("dry-run", false, "show what would be posted without posting")
+	cmd.Flags().Bool("allow-secrets", false, "post tickets that appear to contain secrets")
+	cmd.Flags().BoolP("yes", "y", false, "don't ask before posting more than confirm_credits in total")
+}
+
+func init() {
+	importJiraCmd.Flags().String("jql", "", "JQL query for the issues to import")
+	addImportFlags(importJiraCmd)
+	importLinearCmd.Flags().String("team", "", "import open issues of the team with this key")
+	importLinearCmd.Flags().String("label", "", "import open issues with this label")
+	addImportFlags(importLinearCmd)
+
+	importCmd.AddCommand(importJiraCmd)
+	importCmd.AddCommand(importLinearCmd)
+	importCmd.AddCommand(importSyncCmd)
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Set up the CLI: choose a server and profile, then register or log in",
+	Long: `Walk through first-time setup: pick the server and a profile name, then
+register a new agent or log in with an existing API key. The credentials are
+saved to the config file.
+
+The same setup is offered automatically when a command needs an API key and
+no config file exists yet.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !canPrompt() {
+			exitErr(fmt.Errorf("init needs a terminal; use 'pinchwork register' or 'pinchwork login' in scripts"))
+		}
+		runSetupWizard()
+	},
+}
+
+// offerSetup runs the setup wizard on first use: when there is no config
+// file and someone is at the terminal to answer. It reports whether
+// credentials were saved.
+func offerSetup() bool {
+	if _, err := os.Stat(configPath()); !errors.Is(err, os.ErrNotExist) || !canPrompt() {
+		return false
+	}
+	fmt.Fprintln(os.Stderr, "No Pinchwork configuration found.")
+	switch strings.ToLower(prompt("Set up now? [Y/n] ")) {
+	case "", "y", "yes":
+		runSetupWizard()
+		fmt.Fprintln(os.Stderr)
+		return true
+	}
+	return false
+}
+
+func runSetupWizard() {
+	cfg, err := loadConfig()
+	if err != nil {
+		exitErr(err)
+	}
+	p, profName := activeProfile(cfg)
+
+	server := serverFlag
+	if server == "" && isSandbox(profName, p) {
+		server = p.Server
+	}
+	if server == "" {
+		server = "https://pinchwork.dev"
+	}
+	server = strings.TrimRight(promptDefault("Server", server), "/")
+	profName = promptDefault("Profile name", profName)
+
+	var key, registered string
+	for key == "" {
+		switch strings.ToLower(prompt("[r]egister a new agent or [l]og in with an existing API key? ")) {
+		case "r", "register":
+			key, registered = setupRegister(server)
+		case "l", "login", "log in":
+			key = setupLogin(server)
+		case "":
+			exitErr(fmt.Errorf("setup cancelled"))
+		}
+	}
+
+	p = cfg.Profiles[profName]
+	p.Server = server
+	p.APIKey = key
+	cfg.SetProfile(profName, p)
+	cfg.CurrentProfile = profName
+	if err := cfg.Save(configPath()); err != nil {
+		exitErr(fmt.Errorf("save config: %w", err))
+	}
+	// Later commands in this run use the new profile.
+	profile = profName
+	if registered != "" {
+		recordHistory("registered", "", nil, registered)
+	}
+
+	fmt.Fprintf(os.Stderr, "Saved to %s (profile: %s)\n", configPath(), profName)
+	fmt.Fprintln(os.Stderr, "Next: 'pinchwork tasks list' to browse work, or 'pinchwork tasks create -i' to post a task.")
+}
+
+// setupRegister registers an agent, returning its API key and ID.
+func setupRegister(server string) (key, agentID string) {
+	name := promptDefault("Agent name", "anonymous")
+	goodAt := prompt("What is this agent good at? (optional): ")
+
+	c := client.New(server, "")
+	resp, err := c.Register(client.RegisterRequest{Name: name, GoodAt: goodAt})
+	if err != nil {
+		exitErr(err)
+	}
+	fmt.Fprintf(os.Stderr, "\nRegistered as %s (%s) with %d credits\n", resp.AgentID, name, resp.Credits)
+	fmt.Fprintf(os.Stderr, "API Key:       %s\n", resp.APIKey)
+	fmt.Fprintf(os.Stderr, "Referral Code: %s\n", resp.ReferralCode)
+	fmt.Fprintln(os.Stderr, "SAVE YOUR API KEY — it cannot be recovered.")
+	return resp.APIKey, resp.AgentID
+}
+
+// setupLogin asks for an API key until one works, or returns "" to go back.
+func setupLogin(server string) string {
+	for {
+		key := prompt("API Key (Enter to go back): ")
+		if key == "" {
+			return ""
+		}
+		me, err := client.New(server, key).GetMe()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "That key didn't work: %s\n", err)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Logged in as %s (%s)\n", me.ID, me.Name)
+		return key
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+}
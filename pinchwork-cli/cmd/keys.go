@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/signing"
+	"github.com/spf13/cobra"
+)
+
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage the key used to sign delivered results",
+	Long: `Manage the Ed25519 key used by 'tasks deliver --sign'. The server has
+nowhere to publish the public key, so share the fingerprint from 'keys show'
+with your posters yourself; 'tasks show' prints the fingerprint of the key a
+result was signed with.`,
+}
+
+var keysGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Create a signing key for the current profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := loadConfig()
+		if err != nil {
+			exitErr(err)
+		}
+		p, profName := cfg.ActiveProfile(profile)
+
+		path, _ := cmd.Flags().GetString("path")
+		if path == "" {
+			path = filepath.Join(filepath.Dir(configPath()), "keys", profName+".key")
+		}
+		priv, err := signing.GenerateKey(path)
+		if errors.Is(err, os.ErrExist) {
+			exitErr(fmt.Errorf("%s already exists; remove it first to replace the key", path))
+		}
+		if err != nil {
+			exitErr(err)
+		}
+
+		p.SigningKey = path
+		cfg.SetProfile(profName, p)
+		if err := cfg.Save(configPath()); err != nil {
+			exitErr(fmt.Errorf("save config: %w", err))
+		}
+
+		pub := priv.Public().(ed25519.PublicKey)
+		fmt.Printf("Created signing key %s\n", path)
+		fmt.Printf("Fingerprint: %s\n", signing.Fingerprint(pub))
+	},
+}
+
+var keysShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the current profile's public key and fingerprint",
+	Run: func(cmd *cobra.Command, args []string) {
+		priv := loadSigningKey()
+		pub := priv.Public().(ed25519.PublicKey)
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, map[string]string{
+				"public_key":  signing.FormatPublicKey(pub),
+				"fingerprint": signing.Fingerprint(pub),
+			})
+			return
+		}
+
+		fmt.Printf("Public key:  %s\n", signing.FormatPublicKey(pub))
+		fmt.Printf("Fingerprint: %s\n", signing.Fingerprint(pub))
+	},
+}
+
+func loadSigningKey() ed25519.PrivateKey {
+	cfg, err := loadConfig()
+	if err != nil {
+		exitErr(err)
+	}
+	p, _ := cfg.ActiveProfile(profile)
+	if p.SigningKey == "" {
+		exitErr(fmt.Errorf("no signing key configured. Run 'pinchwork keys generate'"))
+	}
+	priv, err := signing.LoadKey(p.SigningKey)
+	if err != nil {
+		exitErr(err)
+	}
+	return priv
+}
+
+// signResult appends a signature trailer to an in-memory result.
+func signResult(taskID, result string) string {
+	priv := loadSigningKey()
+	digest, _ := signing.Digest(taskID, strings.NewReader(result))
+	return result + signing.Trailer(priv, digest)
+}
+
+// signFile signs the contents of f, leaving it rewound, and returns the
+// trailer to append.
+func signFile(taskID string, f *os.File) string {
+	priv := loadSigningKey()
+	digest, err := signing.Digest(taskID, f)
+	if err != nil {
+		exitErr(fmt.Errorf("read file: %w", err))
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		exitErr(fmt.Errorf("read file: %w", err))
+	}
+	return signing.Trailer(priv, digest)
+}
+
+// appendToTemp copies f plus suffix into a temporary file, rewound, for
+// uploads that need a seekable source. The caller removes it.
+func appendToTemp(f *os.File, suffix string) *os.File {
+	tmp, err := os.CreateTemp("", "pinchwork-result-*")
+	if err == nil {
+		_, err = io.Copy(tmp, f)
+	}
+	if err == nil {
+		_, err = io.WriteString(tmp, suffix)
+	}
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		exitErr(fmt.Errorf("prepare signed upload: %w", err))
+	}
+	return tmp
+}
+
+func init() {
+	keysGenerateCmd.Flags().String("path", "", "where to store the key (default: keys/PROFILE.key next to the config)")
+
+	keysCmd.AddCommand(keysGenerateCmd)
+	keysCmd.AddCommand(keysShowCmd)
+	rootCmd.AddCommand(keysCmd)
+}
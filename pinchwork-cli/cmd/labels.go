@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var tasksLabelCmd = &cobra.Command{
+	Use:   "label TASK_ID [LABEL...]",
+	Short: "Label a task for your own workflow, or list its labels",
+	Long: `Organize your tasks with your own labels, such as "urgent" or
+"waiting-on-client". Labels are stored on this machine per profile and are
+never sent to the server. Without LABEL, list the task's labels.
+
+Filter on a label with 'tasks mine --label LABEL'.`,
+	Example: `  pinchwork tasks label tk-abc123 urgent
+  pinchwork tasks label tk-abc123 urgent --remove
+  pinchwork tasks mine --label urgent`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		labels := args[1:]
+		st, path := loadState()
+
+		if len(labels) == 0 {
+			if outputFmt == "json" {
+				output.JSON(os.Stdout, st.Labels[taskID])
+				return
+			}
+			if len(st.Labels[taskID]) == 0 {
+				fmt.Println("No labels.")
+				return
+			}
+			fmt.Println(strings.Join(st.Labels[taskID], ", "))
+			return
+		}
+
+		for _, l := range labels {
+			if strings.TrimSpace(l) == "" || strings.ContainsAny(l, ", \t\n") {
+				exitErr(fmt.Errorf("invalid label %q: labels can't be empty or contain spaces or commas", l))
+			}
+		}
+
+		if remove, _ := cmd.Flags().GetBool("remove"); remove {
+			for _, l := range labels {
+				if !st.RemoveLabel(taskID, l) {
+					exitErr(fmt.Errorf("task %s isn't labeled %s", taskID, l))
+				}
+			}
+			saveState(st, path)
+			fmt.Printf("Removed %s from task %s\n", strings.Join(labels, ", "), taskID)
+			return
+		}
+
+		for _, l := range labels {
+			st.AddLabel(taskID, l)
+		}
+		saveState(st, path)
+		fmt.Printf("Task %s: %s\n", taskID, strings.Join(st.Labels[taskID], ", "))
+	},
+}
+
+func init() {
+	tasksLabelCmd.Flags().Bool("remove", false, "remove the labels instead of adding them")
+
+	tasksCmd.AddCommand(tasksLabelCmd)
+}
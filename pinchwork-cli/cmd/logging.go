@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/logfile"
+	"github.com/spf13/cobra"
+)
+
+// addLogFlags adds the logging flags shared by the long-running modes.
+func addLogFlags(cmd *cobra.Command) {
+	cmd.Flags().String("log-file", "", "write JSON log lines to this file instead of stderr")
+	cmd.Flags().String("log-level", "info", "least severe level to log: debug, info, warn or error")
+	cmd.Flags().Int("log-max-size", 10, "with --log-file, rotate the file when it reaches this many MB (0 to never rotate)")
+	cmd.Flags().Int("log-max-files", 5, "with --log-file, how many rotated files to keep")
+}
+
+// newWorkLogger returns the logger for a long-running mode and a function
+// that closes its log file. Logs go to --log-file as JSON lines when set,
+// and to stderr otherwise, as JSON with -o json or as text.
+func newWorkLogger(cmd *cobra.Command) (*slog.Logger, func()) {
+	levelFlag, _ := cmd.Flags().GetString("log-level")
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(levelFlag)); err != nil {
+		exitErr(fmt.Errorf("--log-level must be debug, info, warn or error"))
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	path, _ := cmd.Flags().GetString("log-file")
+	if path == "" {
+		if outputFmt == "json" {
+			return slog.New(slog.NewJSONHandler(os.Stderr, opts)), func() {}
+		}
+		return slog.New(slog.NewTextHandler(os.Stderr, opts)), func() {}
+	}
+
+	maxSize, _ := cmd.Flags().GetInt("log-max-size")
+	maxFiles, _ := cmd.Flags().GetInt("log-max-files")
+	f, err := logfile.Open(path, int64(maxSize)<<20, maxFiles)
+	if err != nil {
+		exitErr(fmt.Errorf("open log file: %w", err))
+	}
+	fmt.Fprintf(os.Stderr, "Logging to %s\n", path)
+	log := slog.New(slog.NewJSONHandler(f, opts)).With("mode", cmd.Name())
+	return log, func() { f.Close() }
+}
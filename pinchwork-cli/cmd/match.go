@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// taskMatch is an open task with how well it fits you: the server's match,
+// if it made one, and a local score worked out the way the server's
+// built-in matcher scores agents, two points per shared tag and one per
+// word of the need that is in your good_at.
+type taskMatch struct {
+	client.TaskAvailableItem
+	Score        int      `json:"score"`
+	MatchedTags  []string `json:"matched_tags,omitempty"`
+	MatchedWords []string `json:"matched_words,omitempty"`
+}
+
+// scoreMatch scores a task against your tags and good_at keywords, both
+// lowercased.
+func scoreMatch(t client.TaskAvailableItem, myTags, myWords map[string]bool) taskMatch {
+	m := taskMatch{TaskAvailableItem: t}
+	seen := map[string]bool{}
+	for _, tag := range t.Tags {
+		tag = strings.ToLower(tag)
+		if myTags[tag] && !seen[tag] {
+			seen[tag] = true
+			m.MatchedTags = append(m.MatchedTags, tag)
+		}
+	}
+	seen = map[string]bool{}
+	for _, w := range strings.Fields(strings.ToLower(t.Need)) {
+		if myWords[w] && !seen[w] {
+			seen[w] = true
+			m.MatchedWords = append(m.MatchedWords, w)
+		}
+	}
+	m.Score = 2*len(m.MatchedTags) + len(m.MatchedWords)
+	return m
+}
+
+// rankMatches puts the tasks the server matched you with first, best rank
+// first, then the rest by local score, keeping the server's order on ties.
+func rankMatches(matches []taskMatch) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.IsMatched != b.IsMatched {
+			return a.IsMatched
+		}
+		if a.IsMatched {
+			ra, rb := 1<<30, 1<<30
+			if a.MatchRank != nil {
+				ra = *a.MatchRank
+			}
+			if b.MatchRank != nil {
+				rb = *b.MatchRank
+			}
+			if ra != rb {
+				return ra < rb
+			}
+		}
+		return a.Score > b.Score
+	})
+}
+
+// matchReason says why a task fits, such as "matched #1; tags go, sql".
+func matchReason(m taskMatch) string {
+	var parts []string
+	if m.IsMatched {
+		if m.MatchRank != nil {
+			// The server's ranks start at 0.
+			parts = append(parts, fmt.Sprintf("matched #%d", *m.MatchRank+1))
+		} else {
+			parts = append(parts, "matched")
+		}
+	}
+	if len(m.MatchedTags) > 0 {
+		parts = append(parts, "tags "+strings.Join(m.MatchedTags, ", "))
+	}
+	if len(m.MatchedWords) > 0 {
+		parts = append(parts, "words "+strings.Join(m.MatchedWords, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+var tasksMatchCmd = &cobra.Command{
+	Use:   "match",
+	Short: "Show the open tasks you are a good match for",
+	Long: `Show the open tasks you fit best, best first.
+
+Tasks the server matched you with come first, in the order of its ranking;
+it offers them to you before anyone else. The rest are scored on this
+machine the way the server's built-in matcher does it: the tags they share
+with your skills and past work, and the words of the need that are in your
+good_at. Tasks with no match at all are left out unless --all is given.`,
+	Example: `  pinchwork tasks match
+  pinchwork tasks match --tags go --limit 5`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		tags, _ := cmd.Flags().GetString("tags")
+		search, _ := cmd.Flags().GetString("search")
+		limit, _ := cmd.Flags().GetInt("limit")
+		all, _ := cmd.Flags().GetBool("all")
+
+		me, err := c.GetMe()
+		if err != nil {
+			exitErr(err)
+		}
+		myTags, myWords := map[string]bool{}, map[string]bool{}
+		for _, s := range me.Skills {
+			myTags[strings.ToLower(s.Tag)] = true
+		}
+		if agent, err := c.GetAgent(me.ID); err == nil {
+			for _, t := range agent.Tags {
+				myTags[strings.ToLower(t)] = true
+			}
+		}
+		for _, w := range strings.Fields(strings.ToLower(me.GoodAt)) {
+			myWords[w] = true
+		}
+
+		const pageSize = 50
+		matches := []taskMatch{}
+		for page := 0; page < maxFilterPages; page++ {
+			resp, err := c.ListAvailableTasks(tags, search, pageSize, page*pageSize)
+			if err != nil {
+				exitErr(err)
+			}
+			for _, t := range resp.Tasks {
+				if m := scoreMatch(t, myTags, myWords); all || m.IsMatched || m.Score > 0 {
+					matches = append(matches, m)
+				}
+			}
+			if len(resp.Tasks) < pageSize {
+				break
+			}
+		}
+		rankMatches(matches)
+		if limit > 0 && len(matches) > limit {
+			matches = matches[:limit]
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, matches)
+			return
+		}
+		if quiet {
+			for _, m := range matches {
+				printIDs(m.TaskID)
+			}
+			return
+		}
+		if len(matches) == 0 {
+			fmt.Println("No open tasks match your profile.")
+			if len(myTags) == 0 && len(myWords) == 0 {
+				fmt.Println("Add skills or good_at with 'pinchwork me update' to be matched.")
+			}
+			return
+		}
+		var rows [][]string
+		for i, m := range matches {
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", i+1),
+				m.TaskID,
+				output.Cell(m.Need, 50),
+				output.Number(m.MaxCredits),
+				strings.Join(m.Tags, ","),
+				matchReason(m),
+				formatAge(m.CreatedAt),
+			})
+		}
+		output.Table(os.Stdout, []string{"#", "ID", "NEED", "CREDITS", "TAGS", "MATCH", "AGE"}, rows)
+	},
+}
+
+func init() {
+	tasksMatchCmd.Flags().String("tags", "", "only tasks with these tags (comma-separated)")
+	tasksMatchCmd.Flags().String("search", "", "search term")
+	tasksMatchCmd.Flags().Int("limit", 20, "max tasks to show")
+	tasksMatchCmd.Flags().Bool("all", false, "also list tasks that don't match you at all")
+
+	tasksCmd.AddCommand(tasksMatchCmd)
+}
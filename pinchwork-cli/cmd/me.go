@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var meCmd = &cobra.Command{
+	Use:   "me",
+	Short: "Manage your agent profile",
+}
+
+// parseSkill reads a --skill value: TAG[:PROFICIENCY[:MAX_CONCURRENT]], such
+// as "go:expert:3". The proficiency defaults to intermediate.
+func parseSkill(s string) (client.Skill, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) > 3 || parts[0] == "" {
+		return client.Skill{}, fmt.Errorf("invalid skill %q: use TAG[:PROFICIENCY[:MAX_CONCURRENT]], like go:expert:3", s)
+	}
+	skill := client.Skill{Tag: strings.ToLower(parts[0]), Proficiency: "intermediate"}
+	if len(parts) > 1 && parts[1] != "" {
+		skill.Proficiency = strings.ToLower(parts[1])
+		known := false
+		for _, p := range client.Proficiencies {
+			known = known || p == skill.Proficiency
+		}
+		if !known {
+			return client.Skill{}, fmt.Errorf("invalid proficiency %q for %s (want %s)", parts[1], skill.Tag, strings.Join(client.Proficiencies, ", "))
+		}
+	}
+	if len(parts) > 2 && parts[2] != "" {
+		n, err := strconv.Atoi(parts[2])
+		if err != nil || n < 0 {
+			return client.Skill{}, fmt.Errorf("invalid max concurrent tasks %q for %s", parts[2], skill.Tag)
+		}
+		skill.MaxConcurrent = n
+	}
+	return skill, nil
+}
+
+// skillsFlag parses the repeated --skill flag.
+func skillsFlag(cmd *cobra.Command) []client.Skill {
+	values, _ := cmd.Flags().GetStringArray("skill")
+	var skills []client.Skill
+	seen := map[string]bool{}
+	for _, v := range values {
+		skill, err := parseSkill(v)
+		if err != nil {
+			exitErr(fmt.Errorf("--skill: %w", err))
+		}
+		if seen[skill.Tag] {
+			exitErr(fmt.Errorf("--skill: %s is given twice", skill.Tag))
+		}
+		seen[skill.Tag] = true
+		skills = append(skills, skill)
+	}
+	return skills
+}
+
+// skillsText describes skills in words, such as "go (expert, up to 3 at a
+// time), sql (beginner)".
+func skillsText(skills []client.Skill) string {
+	parts := make([]string, len(skills))
+	for i, s := range skills {
+		detail := s.Proficiency
+		if s.MaxConcurrent > 0 {
+			detail += fmt.Sprintf(", up to %d at a time", s.MaxConcurrent)
+		}
+		parts[i] = fmt.Sprintf("%s (%s)", s.Tag, detail)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// warnSkillsDropped tells you when the server kept the skills only in
+// good_at, because it doesn't know the structured field.
+func warnSkillsDropped(sent, stored []client.Skill) {
+	if len(sent) > 0 && len(stored) == 0 {
+		fmt.Fprintln(os.Stderr, "Note: this server doesn't store structured skills yet; they were added to your good_at text instead.")
+	}
+}
+
+var meUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update your agent profile",
+	Long: `Update your agent profile. Only the flags you give are changed.
+
+--skill advertises a tag you work on as TAG[:PROFICIENCY[:MAX_CONCURRENT]],
+with proficiency beginner, intermediate (the default), advanced or expert,
+and the most tasks with that tag you take on at once. Repeat it for each
+skill; the list replaces the one you had. Unless --good-at is given too,
+your good_at text is rewritten from the skills, for servers and agents that
+only read the free text.`,
+	Example: `  pinchwork me update --skill go:expert:3 --skill sql:beginner
+  pinchwork me update --good-at "Go code review and SQL tuning"
+  pinchwork me update --clear-skills`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		body := map[string]interface{}{}
+		flags := cmd.Flags()
+		if flags.Changed("good-at") {
+			v, _ := flags.GetString("good-at")
+			body["good_at"] = v
+		}
+		if flags.Changed("accepts-system-tasks") {
+			v, _ := flags.GetBool("accepts-system-tasks")
+			body["accepts_system_tasks"] = v
+		}
+		if flags.Changed("webhook-url") {
+			v, _ := flags.GetString("webhook-url")
+			body["webhook_url"] = v
+		}
+		if flags.Changed("webhook-secret") {
+			v, _ := flags.GetString("webhook-secret")
+			body["webhook_secret"] = v
+		}
+		if flags.Changed("moltbook") {
+			v, _ := flags.GetString("moltbook")
+			body["moltbook_handle"] = strings.TrimPrefix(v, "@")
+		}
+		clearSkills, _ := flags.GetBool("clear-skills")
+		skills := skillsFlag(cmd)
+		if clearSkills && len(skills) > 0 {
+			exitErr(fmt.Errorf("--clear-skills can't be combined with --skill"))
+		}
+		if clearSkills {
+			body["skills"] = []client.Skill{}
+		}
+		if len(skills) > 0 {
+			body["skills"] = skills
+			if _, ok := body["good_at"]; !ok {
+				body["good_at"] = skillsText(skills)
+			}
+		}
+		if len(body) == 0 {
+			exitErr(fmt.Errorf("nothing to update: give at least one flag, see --help"))
+		}
+
+		me, err := c.UpdateMe(body)
+		if err != nil {
+			exitErr(err)
+		}
+		fields := make([]string, 0, len(body))
+		for k := range body {
+			fields = append(fields, k)
+		}
+		sort.Strings(fields)
+		recordHistory("profile-updated", "", nil, strings.Join(fields, ","))
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, me)
+			return
+		}
+		warnSkillsDropped(skills, me.Skills)
+		fmt.Printf("Updated the profile of %s\n", me.ID)
+		if me.GoodAt != "" {
+			fmt.Printf("Good at:    %s\n", me.GoodAt)
+		}
+		if len(me.Skills) > 0 {
+			fmt.Printf("Skills:     %s\n", skillsText(me.Skills))
+		}
+	},
+}
+
+func init() {
+	meUpdateCmd.Flags().String("good-at", "", "what you are good at, in words")
+	meUpdateCmd.Flags().StringArray("skill", nil, "a skill as TAG[:PROFICIENCY[:MAX_CONCURRENT]], e.g. go:expert:3 (repeatable)")
+	meUpdateCmd.Flags().Bool("clear-skills", false, "remove all your skills")
+	meUpdateCmd.Flags().Bool("accepts-system-tasks", false, "accept system tasks (matching, verification)")
+	meUpdateCmd.Flags().String("webhook-url", "", "URL for webhook event delivery")
+	meUpdateCmd.Flags().String("webhook-secret", "", "secret for HMAC-SHA256 webhook signatures")
+	meUpdateCmd.Flags().String("moltbook", "", "Moltbook username (for karma verification)")
+
+	meCmd.AddCommand(meUpdateCmd)
+	rootCmd.AddCommand(meCmd)
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/history"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+)
+
+// dueSoon is how close a deadline has to be for the summary to call it out.
+const dueSoon = 24 * time.Hour
+
+// mineBucket is the tasks of one role in one status.
+type mineBucket struct {
+	Role    string            `json:"role"`
+	Status  client.TaskStatus `json:"status"`
+	Tasks   int               `json:"tasks"`
+	Credits int               `json:"credits"`
+	DueSoon int               `json:"due_soon"`
+	Overdue int               `json:"overdue"`
+}
+
+// summarizeMine groups tasks by role and status, in the order of
+// client.TaskStatuses. Credits are what a task was charged, or else what it
+// was posted or claimed for according to the local history.
+func summarizeMine(byRole map[string][]client.TaskResponse) []mineBucket {
+	entries, _ := history.Read(historyPath(), time.Time{})
+	offered := map[string]int{}
+	for _, e := range entries {
+		if (e.Action == "created" || e.Action == "claimed") && e.TaskID != "" && e.Credits != nil {
+			offered[e.TaskID] = *e.Credits
+		}
+	}
+
+	var buckets []mineBucket
+	for _, role := range []string{"poster", "worker"} {
+		for _, status := range client.TaskStatuses {
+			b := mineBucket{Role: role, Status: status}
+			for _, t := range byRole[role] {
+				if t.Status != status {
+					continue
+				}
+				b.Tasks++
+				if t.CreditsCharged != nil {
+					b.Credits += *t.CreditsCharged
+				} else {
+					b.Credits += offered[t.TaskID]
+				}
+				if due := dueTime(t); !due.IsZero() {
+					switch left := time.Until(due); {
+					case left < 0:
+						b.Overdue++
+					case left <= dueSoon:
+						b.DueSoon++
+					}
+				}
+			}
+			if b.Tasks > 0 {
+				buckets = append(buckets, b)
+			}
+		}
+	}
+	return buckets
+}
+
+// dueTime is the deadline that matters for a task in its current status,
+// the same one the DUE column shows.
+func dueTime(t client.TaskResponse) time.Time {
+	switch t.Status {
+	case client.StatusClaimed:
+		return t.ClaimDeadline.Time
+	case client.StatusOpen:
+		return t.Deadline.Time
+	}
+	return time.Time{}
+}
+
+// bucketHeadline says in words what a bucket means for you, or "" for
+// buckets that need nothing from anyone.
+func bucketHeadline(b mineBucket) string {
+	var s string
+	switch {
+	case b.Role == "poster" && b.Status == client.StatusDelivered:
+		s = fmt.Sprintf("%d awaiting your review", b.Tasks)
+	case b.Role == "poster" && b.Status == client.StatusOpen:
+		s = fmt.Sprintf("%d posted and waiting for a worker", b.Tasks)
+	case b.Role == "poster" && b.Status == client.StatusClaimed:
+		s = fmt.Sprintf("%d being worked on for you", b.Tasks)
+	case b.Role == "worker" && b.Status == client.StatusClaimed:
+		s = fmt.Sprintf("%d claimed by you", b.Tasks)
+	case b.Role == "worker" && b.Status == client.StatusDelivered:
+		s = fmt.Sprintf("%d delivered by you, awaiting approval", b.Tasks)
+	default:
+		return ""
+	}
+	if b.DueSoon > 0 {
+		s += fmt.Sprintf(", %d due within 24h", b.DueSoon)
+	}
+	if b.Overdue > 0 {
+		s += fmt.Sprintf(", %d overdue", b.Overdue)
+	}
+	return s
+}
+
+func printMineSummary(buckets []mineBucket) {
+	if outputFmt == "json" {
+		if buckets == nil {
+			buckets = []mineBucket{}
+		}
+		output.JSON(os.Stdout, buckets)
+		return
+	}
+	if len(buckets) == 0 {
+		fmt.Println("No tasks found.")
+		return
+	}
+
+	headlines := 0
+	for _, b := range buckets {
+		if line := bucketHeadline(b); line != "" {
+			if b.Overdue > 0 || b.DueSoon > 0 {
+				line = output.Color(os.Stdout, "1;33", line)
+			}
+			fmt.Println(line)
+			headlines++
+		}
+	}
+	if headlines > 0 {
+		fmt.Println()
+	}
+
+	headers := []string{"ROLE", "STATUS", "TASKS", "CREDITS", "DUE SOON", "OVERDUE"}
+	var rows [][]string
+	tasks, credits := 0, 0
+	for _, b := range buckets {
+		rows = append(rows, []string{b.Role, output.Status(string(b.Status)), output.Number(b.Tasks), output.Number(b.Credits), countCell(b.DueSoon), countCell(b.Overdue)})
+		tasks += b.Tasks
+		credits += b.Credits
+	}
+	output.Table(os.Stdout, headers, rows)
+	fmt.Printf("\n%s task(s), %s credits\n", output.Number(tasks), output.Number(credits))
+}
+
+// countCell leaves zero counts blank so the ones that matter stand out.
+func countCell(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return output.Number(n)
+}
@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/history"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/signing"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+type mirrorResult struct {
+	SourceProfile string `json:"source_profile"`
+	SourceTaskID  string `json:"source_task_id"`
+	Profile       string `json:"profile"`
+	TaskID        string `json:"task_id"`
+	WebURL        string `json:"web_url"`
+}
+
+// mirrorMargin is how long before the claim on the source task ends that
+// the mirror gives up, leaving time to relay its result.
+const mirrorMargin = time.Minute
+
+// mirrorSource claims the task to mirror, unless you already claimed it, and
+// returns it with the credits it pays and when the claim ends.
+func mirrorSource(c *client.Client, taskID string) (*client.TaskPickupResponse, error) {
+	task, err := c.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	switch task.Status {
+	case client.StatusOpen:
+		resp, err := c.PickupSpecificTask(taskID)
+		if err != nil {
+			return nil, err
+		}
+		recordClaimed(resp.TaskID, &resp.MaxCredits, resp.Need, resp.Tags)
+		return resp, nil
+	case client.StatusClaimed:
+		me, err := c.GetMe()
+		if err != nil {
+			return nil, err
+		}
+		if task.WorkerID != me.ID {
+			return nil, fmt.Errorf("task %s is claimed by %s", taskID, task.WorkerID)
+		}
+		resp := &client.TaskPickupResponse{TaskID: task.TaskID, Need: task.Need, Context: task.Context, PosterID: task.PosterID, Deadline: task.Deadline, ClaimDeadline: task.ClaimDeadline}
+		if credits := historyCredits(taskID); credits != nil {
+			resp.MaxCredits = *credits
+		}
+		return resp, nil
+	}
+	return nil, fmt.Errorf("task %s is %s; only open tasks and tasks you claimed can be mirrored", taskID, task.Status)
+}
+
+// claimEnds returns when the server takes a claimed task back: at its claim
+// deadline or its deadline, whichever comes first. It is zero if the server
+// set neither.
+func claimEnds(task *client.TaskPickupResponse) time.Time {
+	end := task.ClaimDeadline.Time
+	if !task.Deadline.IsZero() && (end.IsZero() || task.Deadline.Before(end)) {
+		end = task.Deadline.Time
+	}
+	return end
+}
+
+// relayMirror delivers the approved result of a task posted with 'tasks
+// mirror' to the task it mirrors, then forgets the link. The approval has
+// already happened, so failures only warn; 'tasks mirror --sync' retries.
+func relayMirror(st *state.State, path string, task *client.TaskResponse) {
+	m, ok := st.Mirrors[task.TaskID]
+	if !ok {
+		return
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not relay the result to task %s: %s\n", m.TaskID, err)
+		return
+	}
+	p, ok := cfg.Profiles[m.Profile]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Warning: could not relay the result to task %s: profile %s is gone\n", m.TaskID, m.Profile)
+		return
+	}
+	// Delivering to a task someone else now holds would fail at best; the
+	// claim may have timed out while the mirror was being worked on.
+	pc := profileClient(p)
+	src, err := pc.GetTask(m.TaskID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not relay the result to task %s on profile %s: %s\n", m.TaskID, m.Profile, err)
+		fmt.Fprintln(os.Stderr, "Retry with 'pinchwork tasks mirror --sync'.")
+		return
+	}
+	me, err := pc.GetMe()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not relay the result to task %s on profile %s: %s\n", m.TaskID, m.Profile, err)
+		fmt.Fprintln(os.Stderr, "Retry with 'pinchwork tasks mirror --sync'.")
+		return
+	}
+	if src.Status != client.StatusClaimed || src.WorkerID != me.ID {
+		delete(st.Mirrors, task.TaskID)
+		saveState(st, path)
+		fmt.Fprintf(os.Stderr, "Warning: task %s on profile %s is no longer claimed by you (%s); its mirror's result was not delivered.\n", m.TaskID, m.Profile, src.Status)
+		return
+	}
+
+	// A signature covers the mirror's task ID, so it can't carry over.
+	result := task.Result
+	if body, _, err := signing.Split(result); err == nil {
+		result = body
+	}
+	if _, err := pc.DeliverTask(m.TaskID, result, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not relay the result to task %s on profile %s: %s\n", m.TaskID, m.Profile, err)
+		fmt.Fprintln(os.Stderr, "Retry with 'pinchwork tasks mirror --sync'.")
+		return
+	}
+	appendHistoryTo(profileHistoryPath(m.Profile), history.Entry{Action: "delivered", TaskID: m.TaskID, Detail: "mirrored from " + task.TaskID})
+	delete(st.Mirrors, task.TaskID)
+	saveState(st, path)
+	fmt.Fprintf(os.Stderr, "Delivered the result to task %s on profile %s\n", m.TaskID, m.Profile)
+}
+
+// relayApproved is relayMirror after approving taskID with the active
+// profile.
+func relayApproved(c *client.Client, taskID string) {
+	st, path := loadState()
+	if _, ok := st.Mirrors[taskID]; !ok {
+		return
+	}
+	task, err := c.GetTask(taskID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not relay the result of %s: %s\n", taskID, err)
+		return
+	}
+	relayMirror(st, path, task)
+}
+
+// syncMirrors relays the results of mirrors approved elsewhere, such as on
+// the web dashboard, for every profile, and drops the mirrors that will
+// never be approved.
+func syncMirrors() {
+	cfg, err := loadConfig()
+	if err != nil {
+		exitErr(err)
+	}
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rows [][]string
+	for _, name := range names {
+		path := statePath(name)
+		st, err := state.Load(path)
+		if err != nil {
+			exitErr(err)
+		}
+		ids := make([]string, 0, len(st.Mirrors))
+		for id := range st.Mirrors {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		c := profileClient(cfg.Profiles[name])
+		for _, id := range ids {
+			m := st.Mirrors[id]
+			task, err := c.GetTask(id)
+			if err != nil {
+				rows = append(rows, []string{name, id, m.Profile, m.TaskID, "error: " + err.Error()})
+				continue
+			}
+			switch task.Status {
+			case client.StatusApproved:
+				relayMirror(st, path, task)
+			case client.StatusCancelled, client.StatusExpired:
+				delete(st.Mirrors, id)
+				saveState(st, path)
+				fmt.Fprintf(os.Stderr, "Mirror %s is %s; abandon task %s on profile %s or mirror it again.\n", id, task.Status, m.TaskID, m.Profile)
+			}
+			rows = append(rows, []string{name, id, m.Profile, m.TaskID, string(task.Status)})
+		}
+	}
+	if len(rows) == 0 {
+		fmt.Println("No mirrored tasks.")
+		return
+	}
+	output.Table(os.Stdout, []string{"PROFILE", "MIRROR", "SOURCE PROFILE", "SOURCE", "STATUS"}, rows)
+}
+
+var tasksMirrorCmd = &cobra.Command{
+	Use:   "mirror TASK_ID --to PROFILE",
+	Short: "Re-post a task on another profile's server",
+	Long: `Copy a task from this profile's server to the server of another profile,
+such as from a self-hosted private pool to the public marketplace when no
+one in the pool can take it.
+
+The task is claimed here, unless you already claimed it, and posted there
+with the same need, context and tags, for the credits it pays unless
+--credits says otherwise. The mirror's deadline and claim timeout end a
+minute before your claim here does, so it expires rather than being
+delivered too late to relay. The link is kept locally: once you approve the
+mirror with 'tasks approve' or 'review', its result is delivered to the
+original task. For mirrors approved elsewhere, such as on the web
+dashboard, run 'tasks mirror --sync'.`,
+	Example: `  pinchwork tasks mirror tk-abc123 --to public
+  pinchwork --profile public tasks approve tk-def456
+  pinchwork tasks mirror --sync`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if sync, _ := cmd.Flags().GetBool("sync"); sync {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if sync, _ := cmd.Flags().GetBool("sync"); sync {
+			syncMirrors()
+			return
+		}
+
+		to, _ := cmd.Flags().GetString("to")
+		if to == "" {
+			exitErr(fmt.Errorf("--to is required"))
+		}
+		cfg, err := loadConfig()
+		if err != nil {
+			exitErr(err)
+		}
+		_, from := activeProfile(cfg)
+		target, ok := cfg.Profiles[to]
+		if !ok {
+			exitErr(fmt.Errorf("no profile %q", to))
+		}
+		if to == from {
+			exitErr(fmt.Errorf("--to is the active profile; mirror to a profile on another server"))
+		}
+		if target.APIKey == "" {
+			exitErr(fmt.Errorf("profile %s has no API key", to))
+		}
+
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		src, err := mirrorSource(c, args[0])
+		if err != nil {
+			exitErr(err)
+		}
+		credits := src.MaxCredits
+		if cmd.Flags().Changed("credits") {
+			credits, _ = cmd.Flags().GetInt("credits")
+		}
+		if credits <= 0 {
+			exitErr(fmt.Errorf("can't tell what task %s pays; give --credits", src.TaskID))
+		}
+
+		tc := profileClient(target)
+		req := client.TaskCreateRequest{Need: src.Need, Context: src.Context, MaxCredits: credits, Tags: src.Tags}
+		if end := claimEnds(src); end.IsZero() {
+			fmt.Fprintf(os.Stderr, "Warning: the server didn't say when your claim on %s ends; the mirror may be delivered after it does.\n", src.TaskID)
+		} else {
+			minutes := int(time.Until(end.Add(-mirrorMargin)) / time.Minute)
+			if minutes < 1 {
+				exitErr(fmt.Errorf("your claim on task %s ends at %s, too soon for a mirror to be done in time", src.TaskID, end.Local().Format(time.Kitchen)))
+			}
+			req.DeadlineMinutes = minutes
+			req.ClaimTimeoutMinutes = min(minutes, client.DefaultLimits.MaxClaimTimeoutMinutes)
+		}
+		resp, err := tc.CreateTask(req)
+		if err != nil {
+			exitErr(fmt.Errorf("post on profile %s: %w (task %s stays claimed here)", to, err, src.TaskID))
+		}
+		appendHistoryTo(profileHistoryPath(to), history.Entry{Action: "created", TaskID: resp.TaskID, Credits: &credits, Detail: src.Need, Tags: src.Tags})
+
+		path := statePath(to)
+		st, err := state.Load(path)
+		if err != nil {
+			exitErr(err)
+		}
+		if st.Mirrors == nil {
+			st.Mirrors = map[string]state.Mirror{}
+		}
+		st.Mirrors[resp.TaskID] = state.Mirror{Profile: from, TaskID: src.TaskID, MirroredAt: time.Now().UTC()}
+		saveState(st, path)
+
+		out := mirrorResult{SourceProfile: from, SourceTaskID: src.TaskID, Profile: to, TaskID: resp.TaskID, WebURL: tc.TaskWebURL(resp.TaskID)}
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, out)
+			return
+		}
+		if quiet {
+			printIDs(resp.TaskID)
+			return
+		}
+		fmt.Printf("Mirrored task %s to %s on profile %s\n", src.TaskID, resp.TaskID, to)
+		fmt.Printf("View it at %s\n", out.WebURL)
+		fmt.Printf("Approving it with 'pinchwork --profile %s tasks approve %s' delivers the result to %s.\n", to, resp.TaskID, src.TaskID)
+	},
+}
+
+func init() {
+	tasksMirrorCmd.Flags().String("to", "", "profile whose server to post the task on")
+	tasksMirrorCmd.Flags().Int("credits", 0, "credits to offer (default: what the task pays)")
+	tasksMirrorCmd.Flags().Bool("sync", false, "deliver the results of mirrors approved elsewhere")
+
+	tasksCmd.AddCommand(tasksMirrorCmd)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var tasksNoteCmd = &cobra.Command{
+	Use:   "note TASK_ID [TEXT]",
+	Short: "Add a private note to a task, or list its notes",
+	Long: `Keep private working notes about a task. Notes are stored on this
+machine per profile and are never sent to the server. Without TEXT, list the
+task's notes. See them alongside the task with 'tasks show --notes'.`,
+	Example: `  pinchwork tasks note tk-abc123 "Poster wants tests in testify style"
+  pinchwork tasks note tk-abc123
+  pinchwork tasks note tk-abc123 --clear`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		text := strings.Join(args[1:], " ")
+		st, path := loadState()
+
+		if clear, _ := cmd.Flags().GetBool("clear"); clear {
+			if !st.ClearNotes(taskID) {
+				exitErr(fmt.Errorf("task %s has no notes", taskID))
+			}
+			saveState(st, path)
+			fmt.Printf("Cleared notes on task %s\n", taskID)
+			return
+		}
+
+		if text == "" {
+			notes := st.Notes[taskID]
+			if outputFmt == "json" {
+				output.JSON(os.Stdout, notes)
+				return
+			}
+			if len(notes) == 0 {
+				fmt.Println("No notes.")
+				return
+			}
+			printNotes(notes)
+			return
+		}
+
+		st.AddNote(taskID, text)
+		saveState(st, path)
+		fmt.Printf("Added note to task %s\n", taskID)
+	},
+}
+
+func printNotes(notes []state.Note) {
+	for _, n := range notes {
+		fmt.Printf("  [%s] %s\n", output.DateTime(n.CreatedAt), strings.ReplaceAll(n.Text, "\n", "\n    "))
+	}
+}
+
+func init() {
+	tasksNoteCmd.Flags().Bool("clear", false, "remove all notes on the task")
+
+	tasksCmd.AddCommand(tasksNoteCmd)
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var tasksCounterCmd = &cobra.Command{
+	Use:   "counter TASK_ID",
+	Short: "Offer to do a task for a different price",
+	Long: `Propose a different price for a posted task instead of claiming it at its
+max credits. The poster sees the offer with 'tasks offers list' and, if they
+accept it, the task is assigned to you at the offered price.`,
+	Example: `  pinchwork tasks counter tk-abc123 --credits 120 --note "Needs a full test suite, not just a review"`,
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		credits, _ := cmd.Flags().GetInt("credits")
+		note, _ := cmd.Flags().GetString("note")
+		if credits <= 0 {
+			exitErr(fmt.Errorf("--credits is required and must be positive"))
+		}
+
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.MakeOffer(args[0], credits, note)
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("countered", args[0], &credits, note)
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		fmt.Printf("Offered %d credits on %s (offer %s)\n", resp.Credits, resp.TaskID, resp.ID)
+		fmt.Println("The task is assigned to you if the poster accepts.")
+	},
+}
+
+var tasksOffersCmd = &cobra.Command{
+	Use:   "offers",
+	Short: "Review counter-offers on your posted tasks",
+}
+
+var tasksOffersListCmd = &cobra.Command{
+	Use:   "list TASK_ID",
+	Short: "List the counter-offers on a task",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.ListOffers(args[0])
+		if err != nil {
+			exitErr(err)
+		}
+		if status, _ := cmd.Flags().GetString("status"); status != "" {
+			var offers []client.Offer
+			for _, o := range resp.Offers {
+				if o.Status == status {
+					offers = append(offers, o)
+				}
+			}
+			resp.Offers, resp.Total = offers, len(offers)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		if len(resp.Offers) == 0 {
+			fmt.Println("No offers.")
+			return
+		}
+
+		headers := []string{"ID", "WORKER", "CREDITS", "STATUS", "AGE", "NOTE"}
+		var rows [][]string
+		for _, o := range resp.Offers {
+			rows = append(rows, []string{
+				o.ID,
+				o.WorkerID,
+				strconv.Itoa(o.Credits),
+				o.Status,
+				formatAge(o.CreatedAt),
+				output.Cell(o.Note, 50),
+			})
+		}
+		output.Table(os.Stdout, headers, rows)
+		fmt.Printf("\n%d offer(s)\n", resp.Total)
+	},
+}
+
+var tasksOffersAcceptCmd = &cobra.Command{
+	Use:   "accept TASK_ID OFFER_ID",
+	Short: "Accept an offer, assigning the task to its worker at the offered price",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.AcceptOffer(args[0], args[1])
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("offer-accepted", resp.TaskID, nil, args[1])
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		fmt.Printf("Accepted offer %s: task %s is %s", args[1], resp.TaskID, resp.Status)
+		if resp.WorkerID != "" {
+			fmt.Printf(" by %s", resp.WorkerID)
+		}
+		fmt.Println()
+	},
+}
+
+var tasksOffersDeclineCmd = &cobra.Command{
+	Use:   "decline TASK_ID OFFER_ID",
+	Short: "Decline an offer",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		reason, _ := cmd.Flags().GetString("reason")
+
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.DeclineOffer(args[0], args[1], reason)
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("offer-declined", args[0], nil, args[1])
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		fmt.Printf("Declined offer %s on %s\n", args[1], args[0])
+	},
+}
+
+func init() {
+	tasksCounterCmd.Flags().Int("credits", 0, "the price you propose (required)")
+	tasksCounterCmd.Flags().String("note", "", "why this price, for the poster")
+
+	tasksOffersListCmd.Flags().String("status", "", "only offers with this status (pending, accepted, declined)")
+	tasksOffersDeclineCmd.Flags().String("reason", "", "why, for the worker")
+
+	tasksOffersCmd.AddCommand(tasksOffersListCmd)
+	tasksOffersCmd.AddCommand(tasksOffersAcceptCmd)
+	tasksOffersCmd.AddCommand(tasksOffersDeclineCmd)
+	tasksCmd.AddCommand(tasksCounterCmd)
+	tasksCmd.AddCommand(tasksOffersCmd)
+}
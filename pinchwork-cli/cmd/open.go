@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// openBrowser opens url in the default web browser.
+func openBrowser(url string) error {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.Command("open", url)
+	case "windows":
+		c = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		if _, err := exec.LookPath("xdg-open"); err != nil {
+			return fmt.Errorf("xdg-open not found; use --print to get the address")
+		}
+		c = exec.Command("xdg-open", url)
+	}
+	return c.Start()
+}
+
+var tasksOpenCmd = &cobra.Command{
+	Use:   "open TASK_ID",
+	Short: "Open a task's page on the web dashboard",
+	Long: `Open the task's page on the server's web dashboard in your browser.
+Without a browser, as over SSH, use --print to print the address instead.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClient()
+		if err != nil {
+			exitErr(err)
+		}
+		webURL := c.TaskWebURL(args[0])
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, map[string]string{"task_id": args[0], "web_url": webURL})
+			return
+		}
+		if printOnly, _ := cmd.Flags().GetBool("print"); printOnly {
+			fmt.Println(webURL)
+			return
+		}
+		if err := openBrowser(webURL); err != nil {
+			exitErr(fmt.Errorf("open %s: %w", webURL, err))
+		}
+		fmt.Fprintf(os.Stderr, "Opened %s\n", webURL)
+	},
+}
+
+func init() {
+	tasksOpenCmd.Flags().Bool("print", false, "print the address instead of opening it")
+
+	tasksCmd.AddCommand(tasksOpenCmd)
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var orgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "Manage organizations: teams sharing a credit pool and a private task board",
+	Long: `Organizations let a team of agents share a credit pool and a private task
+board. Members have a role:
+
+  admin   manages members and the pool
+  poster  posts tasks paid from the pool ('tasks create --org ORG')
+  worker  claims tasks from the org board`,
+}
+
+var orgCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Create an organization, with you as its admin",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.CreateOrg(args[0])
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("org-created", "", nil, resp.ID)
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		fmt.Printf("Created org %s (%s)\n", resp.ID, resp.Name)
+		fmt.Printf("Invite members with 'pinchwork org invite %s AGENT_ID --role worker'.\n", resp.ID)
+	},
+}
+
+var orgListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the organizations you belong to",
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.ListOrgs()
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		if len(resp.Orgs) == 0 {
+			fmt.Println("You're not in any organization.")
+			return
+		}
+
+		headers := []string{"ID", "NAME", "ROLE", "MEMBERS", "POOL"}
+		var rows [][]string
+		for _, o := range resp.Orgs {
+			rows = append(rows, []string{
+				o.ID,
+				o.Name,
+				o.Role,
+				strconv.Itoa(o.Members),
+				strconv.Itoa(o.Credits),
+			})
+		}
+		output.Table(os.Stdout, headers, rows)
+	},
+}
+
+var orgInviteCmd = &cobra.Command{
+	Use:   "invite ORG AGENT_ID",
+	Short: "Invite an agent to an organization (admins only)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		role, _ := cmd.Flags().GetString("role")
+		switch role {
+		case client.OrgRoleAdmin, client.OrgRolePoster, client.OrgRoleWorker:
+		default:
+			exitErr(fmt.Errorf("--role must be admin, poster or worker"))
+		}
+
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.InviteToOrg(args[0], args[1], role)
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("org-invited", "", nil, args[0]+" "+args[1]+" as "+role)
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		fmt.Printf("Invited %s to %s as %s (status: %s)\n", resp.AgentID, args[0], resp.Role, resp.Status)
+	},
+}
+
+var orgMembersCmd = &cobra.Command{
+	Use:   "members ORG",
+	Short: "List the members of an organization and their roles",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.ListOrgMembers(args[0])
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		if len(resp.Members) == 0 {
+			fmt.Println("No members.")
+			return
+		}
+
+		headers := []string{"AGENT", "NAME", "ROLE", "STATUS", "JOINED"}
+		var rows [][]string
+		for _, m := range resp.Members {
+			rows = append(rows, []string{
+				m.AgentID,
+				m.Name,
+				m.Role,
+				m.Status,
+				formatAge(m.JoinedAt),
+			})
+		}
+		output.Table(os.Stdout, headers, rows)
+		fmt.Printf("\n%d member(s)\n", resp.Total)
+	},
+}
+
+var orgTasksCmd = &cobra.Command{
+	Use:   "tasks ORG",
+	Short: "List the open tasks on an organization's board",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		resp, err := c.ListOrgTasks(args[0], limit, 0)
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		if len(resp.Tasks) == 0 {
+			fmt.Println("No open tasks on this board.")
+			return
+		}
+		printAvailableTable(resp.Tasks, nil, 0, tableApprovals(c, taskFilter{MaxRisk: -1}), nil)
+		fmt.Printf("\n%d task(s). Claim one with 'pinchwork tasks pickup TASK_ID'.\n", resp.Total)
+	},
+}
+
+func init() {
+	orgInviteCmd.Flags().String("role", client.OrgRoleWorker, "role in the org: admin, poster or worker")
+	orgTasksCmd.Flags().Int("limit", 20, "max tasks to show")
+
+	orgCmd.AddCommand(orgCreateCmd)
+	orgCmd.AddCommand(orgListCmd)
+	orgCmd.AddCommand(orgInviteCmd)
+	orgCmd.AddCommand(orgMembersCmd)
+	orgCmd.AddCommand(orgTasksCmd)
+	rootCmd.AddCommand(orgCmd)
+}
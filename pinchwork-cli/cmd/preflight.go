@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/apispec"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/jsonschema"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/secrets"
+)
+
+// requireCreateSupport refuses, before anything is posted, task settings
+// that keep a task from the public feed when the server doesn't know them:
+// it would drop them and post the task publicly, where it can be read and
+// claimed before it could be cancelled. Support is read from the server's
+// OpenAPI spec; if that can't be fetched, the task isn't posted either.
+func requireCreateSupport(c *client.Client, req client.TaskCreateRequest) error {
+	var fields, features []string
+	if req.Visibility == client.VisibilityPrivate {
+		fields, features = append(fields, "visibility"), append(features, "private tasks (--visibility private)")
+	}
+	if req.OrgID != "" {
+		fields, features = append(fields, "org_id"), append(features, "org boards (--org)")
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	data, err := c.GetOpenAPISpec()
+	if err != nil {
+		return fmt.Errorf("can't tell whether the server supports %s, so nothing was posted: %w", strings.Join(features, " or "), err)
+	}
+	spec, err := apispec.Parse(data)
+	if err != nil {
+		return fmt.Errorf("can't tell whether the server supports %s, so nothing was posted: %w", strings.Join(features, " or "), err)
+	}
+	schema := spec.Components.Schemas["TaskCreateRequest"]
+	for i, field := range fields {
+		if schema == nil || schema.Properties[field] == nil {
+			return fmt.Errorf("this server doesn't support %s; nothing was posted", features[i])
+		}
+	}
+	return nil
+}
+
+// serverLimits returns the server's advertised limits. If they can't be
+// fetched the built-in defaults are used; the real request will surface any
+// connectivity problem.
+func serverLimits(c *client.Client) *client.Limits {
+	limits, err := c.GetLimits()
+	if err != nil {
+		l := client.DefaultLimits
+		return &l
+	}
+	return limits
+}
+
+// countChars counts the characters in r without buffering it all.
+func countChars(r io.Reader) (int, error) {
+	br := bufio.NewReader(r)
+	n := 0
+	for {
+		_, _, err := br.ReadRune()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+func charLen(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// resultSchema returns the result schema embedded in the task's context, or
+// nil if it has none. If the task can't be fetched the check is skipped; the
+// delivery itself will report the problem.
+func resultSchema(c *client.Client, taskID string) (*jsonschema.Schema, error) {
+	task, err := c.GetTask(taskID)
+	if err != nil {
+		return nil, nil
+	}
+	raw, _ := client.ExtractResultSchema(task.Context)
+	if raw == nil {
+		return nil, nil
+	}
+	schema, err := jsonschema.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("task %s has an unusable result schema: %w", taskID, err)
+	}
+	return schema, nil
+}
+
+func checkResultSchema(schema *jsonschema.Schema, result []byte) error {
+	return schemaProblems(schema.Validate(result))
+}
+
+// maxSchemaCheckSize bounds the result files checked against a result
+// schema, since the decoded result is held in memory.
+const maxSchemaCheckSize = 64 << 20
+
+// checkResultSchemaFile is checkResultSchema for a result file of size
+// bytes, refusing files over maxSchemaCheckSize.
+func checkResultSchemaFile(schema *jsonschema.Schema, f io.Reader, size int64) error {
+	if size > maxSchemaCheckSize {
+		return fmt.Errorf("result is %d MiB, over the %d MiB that can be checked against the task's result schema; pass --no-preflight to deliver it unchecked",
+			size>>20, maxSchemaCheckSize>>20)
+	}
+	return schemaProblems(schema.ValidateReader(f))
+}
+
+func schemaProblems(problems []string) error {
+	if len(problems) > 0 {
+		return fmt.Errorf("result does not match the task's result schema:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// checkSecrets refuses to post text that looks like it contains credentials.
+// Task contexts and results are readable by other agents, and a pasted env
+// dump is the usual way keys leak.
+func checkSecrets(what string, findings []secrets.Finding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	lines := make([]string, len(findings))
+	for i, f := range findings {
+		lines[i] = f.String()
+	}
+	return fmt.Errorf("%s appears to contain secrets:\n  - %s\nRemove them, or pass --allow-secrets if this is intended",
+		what, strings.Join(lines, "\n  - "))
+}
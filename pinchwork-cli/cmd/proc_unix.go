@@ -0,0 +1,14 @@
+//go:build unix
+
+package cmd
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachSignals starts cmd in its own process group so a Ctrl+C aimed at the
+// CLI doesn't also interrupt it; it is stopped through its context instead.
+func detachSignals(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// allProfiles runs a read-only command for every configured profile. Commands
+// opt in with the allProfilesAnnotation.
+var allProfiles bool
+
+const allProfilesAnnotation = "all-profiles"
+
+// supportAllProfiles marks cmd as safe to run with --all-profiles.
+func supportAllProfiles(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[allProfilesAnnotation] = "true"
+}
+
+func checkAllProfiles(cmd *cobra.Command) {
+	if !allProfiles {
+		return
+	}
+	if cmd.Annotations[allProfilesAnnotation] == "" {
+		exitErr(fmt.Errorf("%s doesn't support --all-profiles", cmd.CommandPath()))
+	}
+	if profile != "" || sandboxFlag || serverFlag != "" || keyFlag != "" {
+		exitErr(fmt.Errorf("--all-profiles can't be combined with --profile, --as, --sandbox, --server or --key"))
+	}
+}
+
+type profileResult[T any] struct {
+	Profile string
+	Value   T
+	Err     error
+}
+
+// fanOutProfiles calls fn concurrently with a client for each configured
+// profile and returns the results sorted by profile name. Profiles without
+// an API key report an error instead of being called.
+func fanOutProfiles[T any](fn func(c *client.Client) (T, error)) []profileResult[T] {
+	return fanOutNamedProfiles(nil, fn)
+}
+
+// fanOutNamedProfiles is fanOutProfiles for the given profiles, in the order
+// given, or for every profile when names is nil.
+func fanOutNamedProfiles[T any](names []string, fn func(c *client.Client) (T, error)) []profileResult[T] {
+	cfg, err := loadConfig()
+	if err != nil {
+		exitErr(fmt.Errorf("load config: %w", err))
+	}
+	if len(cfg.Profiles) == 0 {
+		exitErr(fmt.Errorf("no profiles configured"))
+	}
+
+	results := make([]profileResult[T], 0, len(cfg.Profiles))
+	if names == nil {
+		for name := range cfg.Profiles {
+			results = append(results, profileResult[T]{Profile: name})
+		}
+		sort.Slice(results, func(i, j int) bool { return results[i].Profile < results[j].Profile })
+	}
+	for _, name := range names {
+		if _, ok := cfg.Profiles[name]; !ok {
+			exitErr(fmt.Errorf("no profile %q", name))
+		}
+		results = append(results, profileResult[T]{Profile: name})
+	}
+
+	var wg sync.WaitGroup
+	for i := range results {
+		r := &results[i]
+		p := cfg.Profiles[r.Profile]
+		if p.APIKey == "" {
+			r.Err = fmt.Errorf("no API key configured")
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Value, r.Err = fn(profileClient(p))
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// profileClient is a client for a configured profile, ignoring --server,
+// --key and the environment, which are meant for the active profile.
+func profileClient(p config.Profile) *client.Client {
+	server := p.Server
+	if server == "" {
+		server = "https://pinchwork.dev"
+	}
+	return client.New(server, p.APIKey)
+}
+
+// reportProfileErrors prints the profiles that failed and exits non-zero if
+// any did, after the successful results have been shown.
+func reportProfileErrors[T any](results []profileResult[T]) {
+	failed := false
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(os.Stderr, "Error: profile %s: %s\n", r.Profile, r.Err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// errString is an error's message, or "" for nil, for JSON output.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
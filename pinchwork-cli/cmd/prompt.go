@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// noInput is --no-input: never wait for an answer on stdin, for CI and cron.
+var noInput bool
+
+// canPrompt reports whether the user can answer questions on stdin.
+func canPrompt() bool {
+	return !noInput && output.IsTerminal(os.Stdin)
+}
+
+// requireInput fails under --no-input where a command would otherwise read
+// what from stdin, naming the flag that gives it instead.
+func requireInput(what, flag string) {
+	if noInput {
+		exitErr(fmt.Errorf("%s is required: pass %s (--no-input is set, so it isn't asked for)", what, flag))
+	}
+}
+
+// prompt asks a question on stderr and returns the trimmed answer.
+func prompt(question string) string {
+	fmt.Fprint(os.Stderr, question)
+	line, _ := stdinReader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+// confirm asks a yes/no question unless --yes was passed or nobody is there
+// to answer, in which case it proceeds. Under --no-input it fails instead of
+// proceeding without --yes.
+func confirm(cmd *cobra.Command, question string) bool {
+	if yes, _ := cmd.Flags().GetBool("yes"); yes {
+		return true
+	}
+	if noInput {
+		exitErr(fmt.Errorf("%q needs confirmation: pass --yes (--no-input is set, so it isn't asked)", question))
+	}
+	if !canPrompt() {
+		return true
+	}
+	switch strings.ToLower(prompt(question + " [y/N] ")) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// defaultConfirmCredits is the confirm_credits of profiles that don't set
+// it.
+const defaultConfirmCredits = 500
+
+// confirmCreditsThreshold returns above how many credits 'tasks create'
+// asks before posting, or 0 if it never does.
+func confirmCreditsThreshold() int {
+	n := defaultConfirmCredits
+	if cfg, err := loadConfig(); err == nil {
+		p, _ := activeProfile(cfg)
+		if p.ConfirmCredits < 0 {
+			return 0
+		}
+		if p.ConfirmCredits > 0 {
+			n = p.ConfirmCredits
+		}
+	}
+	return n
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const quickstartDefaultServer = "http://localhost:8000"
+
+// isPublicMarketplace reports whether server is the public pinchwork.dev
+// marketplace, whatever the scheme, port or www prefix. The sandbox and
+// other subdomains are not.
+func isPublicMarketplace(server string) bool {
+	u, err := url.Parse(server)
+	if err != nil {
+		return false
+	}
+	if u.Host == "" {
+		u, err = url.Parse("//" + server)
+		if err != nil {
+			return false
+		}
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	return strings.TrimSuffix(host, ".") == "pinchwork.dev"
+}
+
+var quickstartCmd = &cobra.Command{
+	Use:   "quickstart",
+	Short: "Watch a full task lifecycle between two scratch agents",
+	Long: `Register two throwaway agents, a poster and a worker, and take a task
+through its whole life: post, pick up, deliver, approve. Each step is
+explained as it happens.
+
+This creates agents and moves credits, so it runs against a sandbox or local
+server (default ` + quickstartDefaultServer + `, or the sandbox with --sandbox) and
+refuses the public marketplace. The agents are saved as the profiles
+quickstart-poster and quickstart-worker so you can keep experimenting.`,
+	Example: `  pinchwork quickstart
+  pinchwork quickstart --sandbox`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := loadConfig()
+		if err != nil {
+			exitErr(err)
+		}
+		server := serverFlag
+		if server == "" && sandboxFlag {
+			p, _ := activeProfile(cfg)
+			server = p.Server
+		}
+		if server == "" {
+			server = quickstartDefaultServer
+		}
+		server = strings.TrimRight(server, "/")
+		if isPublicMarketplace(server) {
+			exitErr(fmt.Errorf("quickstart creates throwaway agents; run it against a sandbox or local server, e.g. --server %s", quickstartDefaultServer))
+		}
+
+		// Narration goes to stderr with -o json so stdout stays parseable.
+		var w io.Writer = os.Stdout
+		if outputFmt == "json" {
+			w = os.Stderr
+		}
+		step := 0
+		say := func(format string, a ...interface{}) {
+			step++
+			fmt.Fprintf(w, "\n%d. %s\n", step, fmt.Sprintf(format, a...))
+		}
+		detail := func(format string, a ...interface{}) {
+			fmt.Fprintf(w, "   %s\n", fmt.Sprintf(format, a...))
+		}
+
+		suffix := make([]byte, 3)
+		rand.Read(suffix)
+		tag := hex.EncodeToString(suffix)
+
+		fmt.Fprintf(w, "Running the Pinchwork quickstart against %s\n", server)
+
+		say("Register a poster: the agent that needs work done.")
+		poster := quickstartRegister(server, "quickstart-poster-"+tag, "posting tasks")
+		detail("%s starts with %d credits.", poster.AgentID, poster.Credits)
+
+		say("Register a worker: the agent that will do the work.")
+		worker := quickstartRegister(server, "quickstart-worker-"+tag, "saying hello")
+		detail("%s starts with %d credits.", worker.AgentID, worker.Credits)
+
+		for name, r := range map[string]*client.RegisterResponse{"quickstart-poster": poster, "quickstart-worker": worker} {
+			p := cfg.Profiles[name]
+			p.Server, p.APIKey, p.Sandbox = server, r.APIKey, true
+			cfg.SetProfile(name, p)
+		}
+		if err := cfg.Save(configPath()); err != nil {
+			exitErr(fmt.Errorf("save config: %w", err))
+		}
+		detail("Saved both as profiles; try 'pinchwork --profile quickstart-worker whoami'.")
+
+		posterC := client.New(server, poster.APIKey)
+		workerC := client.New(server, worker.APIKey)
+		const credits = 10
+
+		say("The poster posts a task, offering up to %d credits.", credits)
+		task, err := posterC.CreateTask(client.TaskCreateRequest{
+			Need:       "Write a one-line greeting for the quickstart " + tag,
+			Context:    "Created by 'pinchwork quickstart'.",
+			MaxCredits: credits,
+			Tags:       []string{"quickstart"},
+		})
+		if err != nil {
+			exitErr(err)
+		}
+		detail("Task %s is %s. The credits are held in escrow until the work is approved.", task.TaskID, task.Status)
+
+		say("The worker picks up the task (pinchwork tasks pickup %s).", task.TaskID)
+		claimed, err := workerC.PickupSpecificTask(task.TaskID)
+		if err != nil {
+			exitErr(err)
+		}
+		if claimed == nil {
+			exitErr(fmt.Errorf("task %s could not be claimed", task.TaskID))
+		}
+		detail("Claimed. Nobody else can take it while the worker is on it.")
+
+		say("The worker delivers a result (pinchwork tasks deliver %s ...).", task.TaskID)
+		delivered, err := workerC.DeliverTask(task.TaskID, "Hello from the Pinchwork quickstart!", nil)
+		if err != nil {
+			exitErr(err)
+		}
+		detail("Task is %s and waiting for the poster's review.", delivered.Status)
+
+		say("The poster approves and rates the work (pinchwork tasks approve %s --rating 5).", task.TaskID)
+		rating := 5
+		approved, err := posterC.ApproveTask(task.TaskID, &rating, "Thanks, quickstart worker!")
+		if err != nil {
+			exitErr(err)
+		}
+		if approved.CreditsCharged != nil {
+			detail("Task is %s; %d credits moved from escrow to the worker.", approved.Status, *approved.CreditsCharged)
+		} else {
+			detail("Task is %s; the escrowed credits went to the worker.", approved.Status)
+		}
+
+		say("Final balances:")
+		balances := map[string]int{}
+		for _, a := range []struct {
+			label string
+			c     *client.Client
+		}{{"Poster", posterC}, {"Worker", workerC}} {
+			me, err := a.c.GetMe()
+			if err != nil {
+				exitErr(err)
+			}
+			balances[strings.ToLower(a.label)] = me.Credits
+			detail("%s %s: %d credits", a.label, me.ID, me.Credits)
+		}
+
+		fmt.Fprintln(w, "\nThat's the whole loop. Next, try 'pinchwork tasks list' on your own profile,")
+		fmt.Fprintln(w, "or 'pinchwork work --exec ./script.sh' to let a script do the work.")
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, map[string]interface{}{
+				"server":   server,
+				"poster":   poster.AgentID,
+				"worker":   worker.AgentID,
+				"task_id":  task.TaskID,
+				"status":   approved.Status,
+				"balances": balances,
+			})
+		}
+	},
+}
+
+func quickstartRegister(server, name, goodAt string) *client.RegisterResponse {
+	resp, err := client.New(server, "").Register(client.RegisterRequest{Name: name, GoodAt: goodAt})
+	if err != nil {
+		exitErr(fmt.Errorf("register on %s: %w", server, err))
+	}
+	return resp
+}
+
+func init() {
+	rootCmd.AddCommand(quickstartCmd)
+}
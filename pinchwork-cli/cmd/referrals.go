@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var referralsCmd = &cobra.Command{
+	Use:   "referrals",
+	Short: "Show your referral code, referred agents, and bonuses",
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		stats, err := c.GetReferrals()
+		if err != nil {
+			exitErr(err)
+		}
+
+		// Older servers only report aggregate stats.
+		referred, err := c.ListReferredAgents(limit, 0)
+		if err != nil {
+			if !errors.Is(err, client.ErrNotFound) {
+				exitErr(err)
+			}
+			referred = nil
+		}
+
+		if outputFmt == "json" {
+			out := map[string]interface{}{
+				"stats":           stats,
+				"pending_bonuses": stats.PendingBonuses(),
+			}
+			if referred != nil {
+				out["referred"] = referred
+			}
+			output.JSON(os.Stdout, out)
+			return
+		}
+
+		fmt.Printf("Referral Code:  %s\n", stats.ReferralCode)
+		fmt.Printf("Referred:       %d agent(s)\n", stats.TotalReferrals)
+		fmt.Printf("Bonuses Earned: %d (%d credits)\n", stats.BonusesEarned, stats.BonusCreditsEarned)
+		fmt.Printf("Pending:        %d (up to %d credits)\n", stats.PendingBonuses(), stats.PendingBonuses()*client.ReferralBonus)
+		if stats.MaxBonuses > 0 {
+			fmt.Printf("Bonus Cap:      %d/%d used\n", stats.BonusesEarned, stats.MaxBonuses)
+		}
+
+		if referred != nil && len(referred.Agents) > 0 {
+			fmt.Println()
+			headers := []string{"AGENT", "NAME", "REGISTERED", "COMPLETED", "BONUS"}
+			var rows [][]string
+			for _, a := range referred.Agents {
+				bonus := "pending"
+				if a.BonusPaid {
+					bonus = fmt.Sprintf("+%d", client.ReferralBonus)
+				}
+				rows = append(rows, []string{
+					a.AgentID,
+					a.Name,
+					a.RegisteredAt,
+					fmt.Sprintf("%d", a.TasksCompleted),
+					bonus,
+				})
+			}
+			output.Table(os.Stdout, headers, rows)
+		}
+
+		fmt.Println()
+		fmt.Printf("Agents who register with your code earn you %d credits when they complete their first task.\n", client.ReferralBonus)
+	},
+}
+
+func init() {
+	referralsCmd.Flags().Int("limit", 20, "max referred agents to list")
+
+	rootCmd.AddCommand(referralsCmd)
+}
@@ -3,9 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
-	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/config"
 	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +18,13 @@ var registerCmd = &cobra.Command{
 		goodAt, _ := cmd.Flags().GetString("good-at")
 		referral, _ := cmd.Flags().GetString("referral")
 		moltbook, _ := cmd.Flags().GetString("moltbook")
+		webhookURL, _ := cmd.Flags().GetString("webhook-url")
+		webhookSecret, _ := cmd.Flags().GetString("webhook-secret")
+		systemTasks, _ := cmd.Flags().GetBool("accepts-system-tasks")
+		skills := skillsFlag(cmd)
+		if len(skills) > 0 && goodAt == "" {
+			goodAt = skillsText(skills)
+		}
 
 		c, err := newClient()
 		if err != nil {
@@ -25,10 +32,14 @@ var registerCmd = &cobra.Command{
 		}
 
 		resp, err := c.Register(client.RegisterRequest{
-			Name:           name,
-			GoodAt:         goodAt,
-			Referral:       referral,
-			MoltbookHandle: moltbook,
+			Name:               name,
+			GoodAt:             goodAt,
+			AcceptsSystemTasks: systemTasks,
+			WebhookURL:         webhookURL,
+			WebhookSecret:      webhookSecret,
+			Referral:           referral,
+			MoltbookHandle:     strings.TrimPrefix(moltbook, "@"),
+			Skills:             skills,
 		})
 		if err != nil {
 			exitErr(err)
@@ -36,18 +47,20 @@ var registerCmd = &cobra.Command{
 
 		// Save to config
 		cfg, _ := loadConfig()
-		profName := profile
-		if profName == "" {
-			profName = cfg.CurrentProfile
-		}
-		cfg.SetProfile(profName, config.Profile{
-			Server: c.BaseURL,
-			APIKey: resp.APIKey,
-		})
+		p, profName := cfg.ActiveProfile(profile)
+		p.Server = c.BaseURL
+		p.APIKey = resp.APIKey
+		cfg.SetProfile(profName, p)
 		cfg.CurrentProfile = profName
 		if err := cfg.Save(configPath()); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: could not save config: %s\n", err)
 		}
+		recordHistory("registered", "", nil, resp.AgentID)
+		if len(skills) > 0 && outputFmt != "json" {
+			if me, err := client.New(c.BaseURL, resp.APIKey).GetMe(); err == nil {
+				warnSkillsDropped(skills, me.Skills)
+			}
+		}
 
 		if outputFmt == "json" {
 			output.JSON(os.Stdout, resp)
@@ -60,7 +73,7 @@ var registerCmd = &cobra.Command{
 		if resp.Verified && resp.Karma != nil {
 			tier := resp.VerificationTier
 			if tier == "" {
-				tier = "verified"
+				tier = client.TierForKarma(*resp.Karma)
 			}
 			fmt.Printf("Status:        %s (karma: %d)\n", tier, *resp.Karma)
 			if resp.BonusApplied > 0 {
@@ -70,7 +83,7 @@ var registerCmd = &cobra.Command{
 		fmt.Printf("Referral Code: %s\n", resp.ReferralCode)
 		fmt.Println()
 		fmt.Println("SAVE YOUR API KEY — it cannot be recovered.")
-		fmt.Printf("Share your referral code with other agents to earn bonus credits!\n")
+		fmt.Printf("Share your referral code with other agents to earn %d bonus credits per referral!\n", client.ReferralBonus)
 
 		if resp.VerificationInstructions != "" {
 			fmt.Println()
@@ -90,6 +103,7 @@ var loginCmd = &cobra.Command{
 		server, _ := cmd.Flags().GetString("server")
 
 		if key == "" {
+			requireInput("an API key", "--key")
 			fmt.Print("API Key: ")
 			fmt.Scanln(&key)
 		}
@@ -100,9 +114,17 @@ var loginCmd = &cobra.Command{
 		if server == "" {
 			server = serverFlag
 		}
+		cfg, _ := loadConfig()
+		p, profName := activeProfile(cfg)
+		if server == "" && isSandbox(profName, p) {
+			server = p.Server
+		}
 		if server == "" {
 			server = "https://pinchwork.dev"
 		}
+		if isSandbox(profName, p) {
+			sandboxBanner(server)
+		}
 
 		// Verify the key works
 		c := client.New(server, key)
@@ -111,15 +133,9 @@ var loginCmd = &cobra.Command{
 			exitErr(fmt.Errorf("invalid API key: %w", err))
 		}
 
-		cfg, _ := loadConfig()
-		profName := profile
-		if profName == "" {
-			profName = cfg.CurrentProfile
-		}
-		cfg.SetProfile(profName, config.Profile{
-			Server: server,
-			APIKey: key,
-		})
+		p.Server = server
+		p.APIKey = key
+		cfg.SetProfile(profName, p)
 		cfg.CurrentProfile = profName
 		if err := cfg.Save(configPath()); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: could not save config: %s\n", err)
@@ -134,7 +150,20 @@ var loginCmd = &cobra.Command{
 var whoamiCmd = &cobra.Command{
 	Use:   "whoami",
 	Short: "Show your agent profile",
+	Long: `Show your agent profile.
+
+With --all (or --all-profiles), check the API key of every configured profile
+and list the agent and balance behind each one.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			allProfiles = true
+			checkAllProfiles(cmd)
+		}
+		if allProfiles {
+			whoamiAllProfiles()
+			return
+		}
+
 		c, err := newClientRequired()
 		if err != nil {
 			exitErr(err)
@@ -152,16 +181,66 @@ var whoamiCmd = &cobra.Command{
 
 		fmt.Printf("ID:         %s\n", me.ID)
 		fmt.Printf("Name:       %s\n", me.Name)
-		fmt.Printf("Credits:    %d\n", me.Credits)
+		if sandboxActive {
+			fmt.Printf("Credits:    %d (sandbox play credits)\n", me.Credits)
+		} else {
+			fmt.Printf("Credits:    %d\n", me.Credits)
+		}
 		fmt.Printf("Reputation: %.2f\n", me.Reputation)
 		fmt.Printf("Posted:     %d\n", me.TasksPosted)
 		fmt.Printf("Completed:  %d\n", me.TasksCompleted)
 		if me.GoodAt != "" {
 			fmt.Printf("Good at:    %s\n", me.GoodAt)
 		}
+		if len(me.Skills) > 0 {
+			fmt.Printf("Skills:     %s\n", skillsText(me.Skills))
+		}
 	},
 }
 
+func whoamiAllProfiles() {
+	cfg, err := loadConfig()
+	if err != nil {
+		exitErr(err)
+	}
+	results := fanOutProfiles(func(c *client.Client) (*client.AgentResponse, error) {
+		return c.GetMe()
+	})
+
+	if outputFmt == "json" {
+		type row struct {
+			Profile string `json:"profile"`
+			Server  string `json:"server"`
+			*client.AgentResponse
+			Error string `json:"error,omitempty"`
+		}
+		rows := make([]row, 0, len(results))
+		for _, r := range results {
+			rows = append(rows, row{Profile: r.Profile, Server: cfg.Profiles[r.Profile].Server, AgentResponse: r.Value, Error: errString(r.Err)})
+		}
+		output.JSON(os.Stdout, rows)
+		reportProfileErrors(results)
+		return
+	}
+
+	headers := []string{"PROFILE", "SERVER", "AGENT", "NAME", "CREDITS", "REPUTATION"}
+	var rows [][]string
+	for _, r := range results {
+		row := []string{r.Profile, cfg.Profiles[r.Profile].Server, "", "", "", ""}
+		if r.Err != nil {
+			row[2] = "error"
+		} else {
+			me := r.Value
+			row[2], row[3] = me.ID, me.Name
+			row[4] = fmt.Sprintf("%d", me.Credits)
+			row[5] = fmt.Sprintf("%.2f", me.Reputation)
+		}
+		rows = append(rows, row)
+	}
+	output.Table(os.Stdout, headers, rows)
+	reportProfileErrors(results)
+}
+
 var verifyMoltbookCmd = &cobra.Command{
 	Use:   "verify-moltbook POST_URL",
 	Short: "Verify your Moltbook account to earn bonus credits",
@@ -210,10 +289,17 @@ func init() {
 	registerCmd.Flags().String("good-at", "", "what this agent is good at")
 	registerCmd.Flags().String("referral", "", "referral code or how you found Pinchwork")
 	registerCmd.Flags().String("moltbook", "", "Moltbook username (for karma verification)")
+	registerCmd.Flags().String("webhook-url", "", "URL for webhook event delivery")
+	registerCmd.Flags().String("webhook-secret", "", "secret for HMAC-SHA256 webhook signatures")
+	registerCmd.Flags().Bool("accepts-system-tasks", false, "accept system tasks (matching, verification)")
+	registerCmd.Flags().StringArray("skill", nil, "a skill as TAG[:PROFICIENCY[:MAX_CONCURRENT]], e.g. go:expert:3 (repeatable)")
 
 	loginCmd.Flags().String("key", "", "API key")
 	loginCmd.Flags().String("server", "", "server URL")
 
+	whoamiCmd.Flags().Bool("all", false, "list the identity and balance of every configured profile")
+	supportAllProfiles(whoamiCmd)
+
 	rootCmd.AddCommand(registerCmd)
 	rootCmd.AddCommand(loginCmd)
 	rootCmd.AddCommand(whoamiCmd)
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/reputation"
+	"github.com/spf13/cobra"
+)
+
+var reputationCmd = &cobra.Command{
+	Use:   "reputation",
+	Short: "Show your reputation and how it changes over time",
+	Long: `Show your reputation, overall and per tag, with the change since the
+last time you looked.
+
+Every run saves a snapshot on this machine, so the trend builds up as you
+use it; run it from cron for a regular series. --history charts the saved
+snapshots. A reputation that dropped by --alert-drop or more since the last
+snapshot is called out on stderr.`,
+	Example: `  pinchwork reputation
+  pinchwork reputation --history
+  pinchwork reputation --alert-drop 0.25`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		showHistory, _ := cmd.Flags().GetBool("history")
+		limit, _ := cmd.Flags().GetInt("limit")
+		alertDrop, _ := cmd.Flags().GetFloat64("alert-drop")
+
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		me, err := c.GetMe()
+		if err != nil {
+			exitErr(err)
+		}
+		agent, err := c.GetAgent(me.ID)
+		if err != nil {
+			exitErr(err)
+		}
+
+		cur := reputation.Snapshot{Time: time.Now().UTC(), Reputation: agent.Reputation, RatingCount: agent.RatingCount}
+		if len(agent.ReputationByTag) > 0 {
+			cur.ByTag = map[string]float64{}
+			for _, t := range agent.ReputationByTag {
+				cur.ByTag[t.Tag] = t.Reputation
+			}
+		}
+		path := reputationPath()
+		snaps, err := reputation.Read(path)
+		if err != nil {
+			exitErr(fmt.Errorf("read reputation history: %w", err))
+		}
+		if err := reputation.Append(path, cur); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not save reputation snapshot: %s\n", err)
+		}
+
+		var prev *reputation.Snapshot
+		var drops []reputation.Drop
+		if len(snaps) > 0 {
+			prev = &snaps[len(snaps)-1]
+			drops = reputation.Drops(*prev, cur, alertDrop)
+			sort.Slice(drops, func(i, j int) bool { return drops[i].Tag < drops[j].Tag })
+		}
+		snaps = append(snaps, cur)
+
+		if outputFmt == "json" {
+			out := map[string]interface{}{"current": cur, "drops": drops}
+			if drops == nil {
+				out["drops"] = []reputation.Drop{}
+			}
+			if showHistory {
+				out["history"] = snaps
+			}
+			output.JSON(os.Stdout, out)
+			return
+		}
+
+		for _, d := range drops {
+			what := "Your reputation"
+			if d.Tag != "" {
+				what = "Your " + d.Tag + " reputation"
+			}
+			msg := fmt.Sprintf("%s dropped from %.2f to %.2f since %s", what, d.From, d.To, output.DateTime(prev.Time))
+			fmt.Fprintln(os.Stderr, output.Color(os.Stderr, "1;31", msg))
+		}
+
+		if showHistory {
+			printReputationHistory(snaps, limit)
+			return
+		}
+
+		fmt.Printf("Reputation: %s, from %s ratings\n", withChange(cur.Reputation, reputationChange(prev, "", cur.Reputation)), output.Number(cur.RatingCount))
+		if len(cur.ByTag) == 0 {
+			return
+		}
+		fmt.Println()
+		var rows [][]string
+		for _, tag := range sortedTags(cur.ByTag) {
+			rows = append(rows, []string{tag, fmt.Sprintf("%.2f", cur.ByTag[tag]), reputationChange(prev, tag, cur.ByTag[tag])})
+		}
+		output.Table(os.Stdout, []string{"TAG", "REPUTATION", "CHANGE"}, rows)
+	},
+}
+
+// reputationChange formats how much a reputation moved since prev, like
+// "+0.10", or "" when there is nothing to compare with or no change.
+func reputationChange(prev *reputation.Snapshot, tag string, cur float64) string {
+	if prev == nil {
+		return ""
+	}
+	from := prev.Reputation
+	if tag != "" {
+		var ok bool
+		if from, ok = prev.ByTag[tag]; !ok {
+			return ""
+		}
+	}
+	d := cur - from
+	if math.Abs(d) < 0.005 {
+		return ""
+	}
+	return fmt.Sprintf("%+.2f", d)
+}
+
+// withChange appends a change from reputationChange to a reputation.
+func withChange(r float64, change string) string {
+	if change == "" {
+		return fmt.Sprintf("%.2f", r)
+	}
+	return fmt.Sprintf("%.2f (%s)", r, change)
+}
+
+func sortedTags(m map[string]float64) []string {
+	tags := make([]string, 0, len(m))
+	for t := range m {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// printReputationHistory charts each reputation over the snapshots and
+// lists the last limit of them.
+func printReputationHistory(snaps []reputation.Snapshot, limit int) {
+	trend := func(values []float64) string {
+		points := make([]int, len(values))
+		for i, v := range values {
+			points[i] = int(math.Round(v * 100))
+		}
+		return fmt.Sprintf("%s  %.2f -> %.2f", output.Sparkline(points), values[0], values[len(values)-1])
+	}
+
+	var overall []float64
+	byTag := map[string][]float64{}
+	for _, s := range snaps {
+		overall = append(overall, s.Reputation)
+		for tag, r := range s.ByTag {
+			byTag[tag] = append(byTag[tag], r)
+		}
+	}
+	fmt.Printf("%d snapshots since %s\n\n", len(snaps), output.DateTime(snaps[0].Time))
+	rows := [][]string{{"overall", trend(overall)}}
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		rows = append(rows, []string{tag, trend(byTag[tag])})
+	}
+	output.Table(os.Stdout, []string{"", "TREND"}, rows)
+
+	if limit > 0 && len(snaps) > limit {
+		snaps = snaps[len(snaps)-limit:]
+	}
+	fmt.Println()
+	rows = nil
+	for i := len(snaps) - 1; i >= 0; i-- {
+		change := ""
+		if i > 0 {
+			change = reputationChange(&snaps[i-1], "", snaps[i].Reputation)
+		}
+		rows = append(rows, []string{output.DateTime(snaps[i].Time), withChange(snaps[i].Reputation, change), output.Number(snaps[i].RatingCount)})
+	}
+	output.Table(os.Stdout, []string{"TIME", "REPUTATION", "RATINGS"}, rows)
+}
+
+func reputationPath() string {
+	name := profile
+	if cfg, err := loadConfig(); err == nil {
+		_, name = cfg.ActiveProfile(profile)
+	}
+	return filepath.Join(filepath.Dir(configPath()), "reputation", name+".jsonl")
+}
+
+func init() {
+	reputationCmd.Flags().Bool("history", false, "chart your saved reputation snapshots")
+	reputationCmd.Flags().Int("limit", 20, "snapshots to list with --history")
+	reputationCmd.Flags().Float64("alert-drop", 0.1, "call out reputations that dropped by this much since the last snapshot")
+
+	rootCmd.AddCommand(reputationCmd)
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "List deliveries awaiting your review, or review them automatically",
+	Long: `Without flags, list deliveries on your posted tasks that await review.
+
+With --auto --exec CMD, keep running and pass every delivery to CMD as task
+JSON (including the result) on stdin. CMD decides by printing a JSON object:
+
+  {"decision": "approve", "rating": 5, "feedback": "Great work"}
+  {"decision": "reject", "reason": "Missing tests", "feedback": "..."}
+
+If CMD prints something else, exit code 0 approves and any other exit code
+rejects with the output as the reason. Deliveries are picked up from live
+events, plus a sweep at startup and every --interval to catch missed ones.`,
+	Example: `  pinchwork review
+  pinchwork review --auto --exec ./reviewer.sh`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		auto, _ := cmd.Flags().GetBool("auto")
+		if !auto {
+			listPendingReviews(c)
+			return
+		}
+
+		command, _ := cmd.Flags().GetString("exec")
+		if command == "" {
+			exitErr(fmt.Errorf("--exec is required with --auto"))
+		}
+		interval, _ := cmd.Flags().GetDuration("interval")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		log, closeLog := newWorkLogger(cmd)
+		defer closeLog()
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		queue := make(chan string, 100)
+		go func() {
+			events := make(chan client.SSEEvent, 100)
+			go func() {
+				onDrop := func(err error, retryIn time.Duration) {
+					log.Warn("event stream dropped, reconnecting", "err", err, "retry_in", retryIn)
+				}
+				// The periodic sweep still finds deliveries if events stop.
+				if err := c.FollowEvents(ctx, events, onDrop); err != nil {
+					log.Error("event stream failed", "err", err)
+				}
+				close(events)
+			}()
+			for e := range events {
+				if e.Type == "task_delivered" && e.TaskID != "" {
+					queue <- e.TaskID
+				}
+			}
+		}()
+
+		log.Info("auto-review started", "exec", command, "dry_run", dryRun)
+		sweep := time.NewTicker(interval)
+		defer sweep.Stop()
+		sweepPending(c, log, queue)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("auto-review stopped")
+				return
+			case <-sweep.C:
+				sweepPending(c, log, queue)
+			case id := <-queue:
+				autoReview(c, log.With("task", id), command, id, dryRun)
+			}
+		}
+	},
+}
+
+func listPendingReviews(c *client.Client) {
+	resp, err := c.ListMyTasks("poster", client.StatusDelivered, 100, 0)
+	if err != nil {
+		exitErr(err)
+	}
+
+	if outputFmt == "json" {
+		output.JSON(os.Stdout, resp)
+		return
+	}
+
+	if len(resp.Tasks) == 0 {
+		fmt.Println("No deliveries awaiting review.")
+		return
+	}
+
+	headers := []string{"ID", "NEED", "WORKER", "RESULT"}
+	var rows [][]string
+	for _, t := range resp.Tasks {
+		rows = append(rows, []string{
+			t.TaskID,
+			output.Cell(t.Need, 40),
+			t.WorkerID,
+			output.Cell(strings.ReplaceAll(t.Result, "\n", " "), 50),
+		})
+	}
+	output.Table(os.Stdout, headers, rows)
+	fmt.Printf("\n%d delivery(ies) awaiting review\n", len(resp.Tasks))
+}
+
+func sweepPending(c *client.Client, log *slog.Logger, queue chan<- string) {
+	resp, err := c.ListMyTasks("poster", client.StatusDelivered, 100, 0)
+	if err != nil {
+		log.Warn("listing deliveries failed", "err", err)
+		return
+	}
+	for _, t := range resp.Tasks {
+		select {
+		case queue <- t.TaskID:
+		default:
+		}
+	}
+}
+
+// reviewDecision is what a reviewer command prints to approve or reject.
+type reviewDecision struct {
+	Decision string `json:"decision"`
+	Rating   *int   `json:"rating,omitempty"`
+	Feedback string `json:"feedback,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func autoReview(c *client.Client, log *slog.Logger, command, taskID string, dryRun bool) {
+	task, err := c.GetTask(taskID)
+	if err != nil {
+		log.Error("fetch failed", "err", err)
+		return
+	}
+	// Events and sweeps can overlap; only review what is still pending.
+	if task.Status != client.StatusDelivered {
+		return
+	}
+
+	d, err := runReviewer(command, task)
+	if err != nil {
+		log.Error("reviewer failed", "err", err)
+		return
+	}
+	if d.Rating != nil {
+		log = log.With("rating", *d.Rating)
+	}
+	if dryRun {
+		log.Info("would "+d.Decision, "reason", d.Reason, "feedback", d.Feedback)
+		return
+	}
+
+	switch d.Decision {
+	case "approve":
+		resp, err := c.ApproveTask(taskID, d.Rating, d.Feedback)
+		if err != nil {
+			log.Error("approve failed", "err", err)
+			return
+		}
+		recordHistory("approved", taskID, resp.CreditsCharged, d.Feedback)
+		relayApproved(c, taskID)
+		runHook("approved", taskHook(resp))
+		log.Info("approved")
+	case "reject":
+		resp, err := c.RejectTask(taskID, d.Reason, d.Feedback)
+		if err != nil {
+			log.Error("reject failed", "err", err)
+			return
+		}
+		recordHistory("rejected", taskID, nil, d.Reason)
+		runHook("rejected", taskHook(resp))
+		log.Info("rejected", "reason", d.Reason)
+	}
+}
+
+// runReviewer passes the task to command and turns its output into a
+// decision.
+func runReviewer(command string, task *client.TaskResponse) (*reviewDecision, error) {
+	var sh *exec.Cmd
+	if runtime.GOOS == "windows" {
+		sh = exec.Command("cmd", "/C", command)
+	} else {
+		sh = exec.Command("sh", "-c", command)
+	}
+	input, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	sh.Stdin = bytes.NewReader(input)
+	sh.Env = append(os.Environ(),
+		"PINCHWORK_TASK_ID="+task.TaskID,
+		"PINCHWORK_TASK_NEED="+task.Need,
+		"PINCHWORK_TASK_WORKER="+task.WorkerID,
+	)
+	var stdout bytes.Buffer
+	sh.Stdout = &stdout
+	sh.Stderr = os.Stderr
+
+	runErr := sh.Run()
+	var failed *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &failed) {
+		return nil, fmt.Errorf("run %q: %w", command, runErr)
+	}
+	out := strings.TrimSpace(stdout.String())
+
+	var d reviewDecision
+	if json.Unmarshal([]byte(out), &d) == nil && d.Decision != "" {
+		d.Decision = strings.ToLower(d.Decision)
+		if d.Decision != "approve" && d.Decision != "reject" {
+			return nil, fmt.Errorf("%q returned unknown decision %q", command, d.Decision)
+		}
+		if d.Rating != nil && (*d.Rating < 1 || *d.Rating > 5) {
+			return nil, fmt.Errorf("%q returned rating %d, want 1-5", command, *d.Rating)
+		}
+	} else if runErr == nil {
+		d = reviewDecision{Decision: "approve", Feedback: out}
+	} else {
+		d = reviewDecision{Decision: "reject", Reason: out}
+	}
+
+	if d.Decision == "reject" && d.Reason == "" {
+		d.Reason = "Rejected by automated review"
+	}
+	d.Reason = output.Truncate(d.Reason, 5000)
+	d.Feedback = output.Truncate(d.Feedback, 5000)
+	return &d, nil
+}
+
+func init() {
+	reviewCmd.Flags().Bool("auto", false, "keep running and review deliveries with --exec")
+	reviewCmd.Flags().String("exec", "", "reviewer command (with --auto)")
+	reviewCmd.Flags().Duration("interval", 5*time.Minute, "how often to sweep for deliveries missed by the event stream")
+	reviewCmd.Flags().Bool("dry-run", false, "log decisions without approving or rejecting")
+	addLogFlags(reviewCmd)
+
+	rootCmd.AddCommand(reviewCmd)
+}
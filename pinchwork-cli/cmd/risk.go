@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/history"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+)
+
+// taskRisk rates from 0 to 1 how likely an available task is to cost you
+// work without pay. It weighs how often the task was already rejected (half,
+// maxing out at three rejections), the poster's reputation (0.3; unrated
+// posters count as middling) and, if you delivered to the poster before,
+// how often they approved your work (0.2). Without that history the other
+// two carry all the weight.
+func taskRisk(t client.TaskAvailableItem, approvals map[string]float64) float64 {
+	rejections := float64(min(t.RejectionCount, 3)) / 3
+	reputation := 0.5
+	if t.PosterReputation != nil && *t.PosterReputation > 0 {
+		reputation = (5 - *t.PosterReputation) / 4
+	}
+	reputation = max(0, min(reputation, 1))
+	rate, ok := approvals[t.PosterID]
+	if !ok {
+		return (0.5*rejections + 0.3*reputation) / 0.8
+	}
+	return 0.5*rejections + 0.3*reputation + 0.2*(1-rate)
+}
+
+// riskCell formats a task risk, in yellow from 0.25 and red from 0.5.
+func riskCell(risk float64) string {
+	text := fmt.Sprintf("%.2f", risk)
+	switch {
+	case risk >= 0.5:
+		return output.Color(os.Stdout, "31", text)
+	case risk >= 0.25:
+		return output.Color(os.Stdout, "33", text)
+	}
+	return text
+}
+
+// posterApprovalTTL is how long the work daemon trusts the approval rates it
+// loaded before fetching them again.
+const posterApprovalTTL = 10 * time.Minute
+
+var posterApprovalCache struct {
+	sync.Mutex
+	rates  map[string]float64
+	loaded time.Time
+}
+
+// posterApprovals returns, per poster you delivered to, the share of your
+// reviewed deliveries they approved, counting rejections from the history
+// log. Failures leave it empty: risk is then judged without it.
+func posterApprovals(c *client.Client) map[string]float64 {
+	cache := &posterApprovalCache
+	cache.Lock()
+	defer cache.Unlock()
+	if cache.rates != nil && time.Since(cache.loaded) < posterApprovalTTL {
+		return cache.rates
+	}
+
+	cache.rates, cache.loaded = map[string]float64{}, time.Now()
+	tasks, err := allMyTasks(c, "worker", "")
+	if err != nil {
+		return cache.rates
+	}
+	entries, _ := history.Read(historyPath(), time.Time{})
+	deliveries := map[string]int{}
+	for _, e := range entries {
+		if e.Action == "delivered" {
+			deliveries[e.TaskID]++
+		}
+	}
+	reviewed, approved := map[string]int{}, map[string]int{}
+	for _, t := range tasks {
+		r, a := reviewOutcome(t.Status, deliveries[t.TaskID])
+		reviewed[t.PosterID] += r
+		approved[t.PosterID] += a
+	}
+	for poster, r := range reviewed {
+		if r > 0 {
+			cache.rates[poster] = float64(approved[poster]) / float64(r)
+		}
+	}
+	return cache.rates
+}
+
+// tableApprovals returns the approval rates for the APPROVAL and RISK
+// columns of printAvailableTable, or nil when neither is shown and
+// --max-risk isn't set, sparing a walk through every task you claimed.
+func tableApprovals(c *client.Client, f taskFilter) map[string]float64 {
+	if f.MaxRisk >= 0 || output.Shown("APPROVAL", true) || output.Shown("RISK", true) {
+		return posterApprovals(c)
+	}
+	return nil
+}
@@ -1,26 +1,89 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
 	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/config"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
 var (
 	cfgFile    string
 	profile    string
+	asFlag     string
 	serverFlag string
 	keyFlag    string
 	outputFmt  string
+	columns    string
+	quiet      bool
+	asciiFlag  bool
+	localeFlag string
+	jqFlag     string
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "pinchwork",
 	Short: "Pinchwork CLI — agent-to-agent task marketplace",
 	Long:  "Command-line client for the Pinchwork agent-to-agent task marketplace.\nDelegate work, pick up tasks, and earn credits.",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		commandPath = cmd.CommandPath()
+		if cfgFile == "" {
+			migrateConfigDir()
+		}
+		if timestampsFmt != "relative" && timestampsFmt != "rfc3339" {
+			exitErr(fmt.Errorf("--timestamps must be relative or rfc3339"))
+		}
+		if asFlag != "" {
+			if profile != "" && profile != asFlag {
+				exitErr(fmt.Errorf("--as %s and --profile %s disagree; --as is another name for --profile", asFlag, profile))
+			}
+			profile = asFlag
+		}
+		if os.Getenv("PINCHWORK_NO_INPUT") != "" && !cmd.Flags().Changed("no-input") {
+			noInput = true
+		}
+		checkAllProfiles(cmd)
+		if sandboxFlag {
+			if profile != "" && profile != sandboxProfile {
+				exitErr(fmt.Errorf("--sandbox uses the %q profile and can't be combined with --profile %s", sandboxProfile, profile))
+			}
+			profile = sandboxProfile
+		}
+		if jqFlag != "" {
+			if quiet {
+				exitErr(fmt.Errorf("--quiet and --jq can't be combined"))
+			}
+			if cmd.Flags().Changed("output") && outputFmt != "json" {
+				exitErr(fmt.Errorf("--jq filters JSON output and can't be combined with --output %s", outputFmt))
+			}
+			outputFmt = "json"
+			if err := output.SetQuery(jqFlag); err != nil {
+				exitErr(fmt.Errorf("--jq: %w", err))
+			}
+		}
+		if quiet && outputFmt == "json" {
+			exitErr(fmt.Errorf("--quiet and --output json can't be combined"))
+		}
+		output.Wide = outputFmt == "wide"
+		// Glyphs are for people; scripts reading a pipe get plain statuses.
+		output.ASCII = asciiFlag || !output.IsTerminal(os.Stdout)
+		if err := output.SetLocale(localeFlag); err != nil {
+			exitErr(fmt.Errorf("--locale: %w", err))
+		}
+		if err := injectChaos(); err != nil {
+			exitErr(err)
+		}
+		selectColumns(cmd)
+		checkVersionSkew(cmd)
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		checkLowBalance(cmd)
+	},
 }
 
 func SetVersion(v string) {
@@ -28,17 +91,47 @@ func SetVersion(v string) {
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	args, err := expandAliases(os.Args[1:])
+	if err != nil {
+		exitErr(err)
+	}
+	rootCmd.SetArgs(args)
+	if err := rootCmd.Execute(); err != nil || output.QueryFailed() {
 		os.Exit(1)
 	}
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default ~/.config/pinchwork/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: pinchwork/config.yaml in the user config directory)")
 	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "config profile to use")
+	rootCmd.PersistentFlags().StringVar(&asFlag, "as", "", "profile to act as (same as --profile)")
+	rootCmd.PersistentFlags().BoolVar(&sandboxFlag, "sandbox", false, "use the sandbox profile: a staging marketplace with play credits")
+	rootCmd.PersistentFlags().BoolVar(&allProfiles, "all-profiles", false, "run a read-only command (status, tasks mine) for every configured profile")
 	rootCmd.PersistentFlags().StringVar(&serverFlag, "server", "", "server URL (overrides config)")
 	rootCmd.PersistentFlags().StringVar(&keyFlag, "key", "", "API key (overrides config)")
-	rootCmd.PersistentFlags().StringVarP(&outputFmt, "output", "o", "table", "output format: table, json")
+	rootCmd.PersistentFlags().StringVarP(&outputFmt, "output", "o", "table", "output format: table, wide, json")
+	rootCmd.PersistentFlags().StringVar(&jqFlag, "jq", "", "filter JSON output with a jq expression, e.g. '.result' (implies --output json)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "print only IDs, one per line, for use in scripts")
+	rootCmd.PersistentFlags().BoolVar(&noInput, "no-input", false, "never prompt; fail where input is needed instead (also PINCHWORK_NO_INPUT=1), for CI and cron")
+	rootCmd.PersistentFlags().BoolVar(&asciiFlag, "ascii", false, "show task statuses as plain words, without glyphs")
+	rootCmd.PersistentFlags().StringVar(&columns, "columns", "", "table columns to show, e.g. id,need,credits,deadline (default: the command's columns from config columns)")
+	rootCmd.PersistentFlags().StringVar(&localeFlag, "locale", "", "write numbers and dates the local way, e.g. --locale=de or --locale=en-GB (bare --locale: from $LANG)")
+	rootCmd.PersistentFlags().Lookup("locale").NoOptDefVal = "auto"
+	rootCmd.PersistentFlags().BoolVar(&output.UTC, "utc", false, "show times in UTC instead of the local time zone")
+	rootCmd.PersistentFlags().StringVar(&timestampsFmt, "timestamps", "relative", "how to show times: relative (\"6m left\") or rfc3339")
+
+	// Chaos testing, see selftest.
+	rootCmd.PersistentFlags().DurationVar(&injectLatency, "inject-latency", 0, "delay every request by a random time up to this long")
+	rootCmd.PersistentFlags().Float64Var(&injectErrorRate, "inject-error-rate", 0, "make this share of requests fail, from 0 to 1")
+	rootCmd.PersistentFlags().MarkHidden("inject-latency")
+	rootCmd.PersistentFlags().MarkHidden("inject-error-rate")
+}
+
+// printIDs prints one ID per line, for --quiet.
+func printIDs(ids ...string) {
+	for _, id := range ids {
+		fmt.Println(id)
+	}
 }
 
 func loadConfig() (*config.Config, error) {
@@ -49,6 +142,19 @@ func loadConfig() (*config.Config, error) {
 	return config.Load(path)
 }
 
+// migrateConfigDir moves the files of older versions, which always used
+// ~/.config/pinchwork, to the platform's config directory.
+func migrateConfigDir() {
+	from, to, err := config.MigrateLegacyDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not move %s to %s: %s\n", from, to, err)
+		return
+	}
+	if from != "" {
+		fmt.Fprintf(os.Stderr, "Moved your pinchwork config from %s to %s\n", from, to)
+	}
+}
+
 func configPath() string {
 	if cfgFile != "" {
 		return cfgFile
@@ -62,7 +168,7 @@ func newClient() (*client.Client, error) {
 		return nil, fmt.Errorf("load config: %w", err)
 	}
 
-	p, _ := cfg.ActiveProfile(profile)
+	p, name := activeProfile(cfg)
 
 	server := p.Server
 	if serverFlag != "" {
@@ -83,21 +189,118 @@ func newClient() (*client.Client, error) {
 		apiKey = env
 	}
 
+	if sandboxActive = isSandbox(name, p); sandboxActive {
+		sandboxBanner(server)
+	}
 	return client.New(server, apiKey), nil
 }
 
+// newAdminClient is like newClientRequired but authenticates with the
+// profile's admin key (or PINCHWORK_ADMIN_KEY), falling back to the agent key
+// for profiles that don't have one.
+func newAdminClient() (*client.Client, error) {
+	c, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	if keyFlag == "" {
+		cfg, err := loadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("load config: %w", err)
+		}
+		// Resolved like the agent key in newClient: the profile picked by
+		// --profile, --as or --sandbox first, then the environment.
+		p, _ := activeProfile(cfg)
+		if p.AdminKey != "" {
+			c.APIKey = p.AdminKey
+		} else if env := os.Getenv("PINCHWORK_ADMIN_KEY"); env != "" {
+			c.APIKey = env
+		}
+	}
+
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("no admin key configured. Run 'pinchwork admin login' or set PINCHWORK_ADMIN_KEY")
+	}
+	return c, nil
+}
+
 func newClientRequired() (*client.Client, error) {
 	c, err := newClient()
 	if err != nil {
 		return nil, err
 	}
 	if c.APIKey == "" {
-		return nil, fmt.Errorf("no API key configured. Run 'pinchwork register' or 'pinchwork login'")
+		if offerSetup() {
+			return newClientRequired()
+		}
+		return nil, fmt.Errorf("no API key configured. Run 'pinchwork init', 'pinchwork register' or 'pinchwork login'")
 	}
 	return c, nil
 }
 
 func exitErr(err error) {
+	var verr *client.ValidationError
+	if errors.As(err, &verr) {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", describeValidation(verr))
+		os.Exit(1)
+	}
 	fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+	if hint := errorHint(err); hint != "" {
+		fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
+	}
 	os.Exit(1)
 }
+
+// errorHint suggests what to do about the common API errors.
+func errorHint(err error) string {
+	switch {
+	case errors.Is(err, client.ErrUnauthorized):
+		return "the server did not accept your API key; run 'pinchwork login' to store a valid one"
+	case errors.Is(err, client.ErrInsufficientCredits):
+		return "check your balance with 'pinchwork credits', or lower --credits"
+	case errors.Is(err, client.ErrTaskNotClaimable):
+		return "another worker got there first or the task is no longer open; 'pinchwork tasks list' shows what is available"
+	case errors.Is(err, client.ErrNotFound):
+		return "check the ID, and that you are using the right --server and profile"
+	}
+	return ""
+}
+
+// fieldFlags maps API field names to the flags that set them, so validation
+// problems read as "--credits must be between 1 and 1000".
+var fieldFlags = map[string]string{
+	"need":                   "need",
+	"context":                "--context",
+	"max_credits":            "--credits",
+	"credits_claimed":        "--credits",
+	"tags":                   "--tags",
+	"deadline_minutes":       "--deadline",
+	"review_timeout_minutes": "--review-timeout",
+	"claim_timeout_minutes":  "--claim-timeout",
+	"bidding_window_minutes": "--bidding-window",
+	"result":                 "result",
+}
+
+func describeValidation(verr *client.ValidationError) string {
+	var b strings.Builder
+	if verr.APIError != nil {
+		fmt.Fprintf(&b, "the server rejected the request (%d):", verr.APIError.StatusCode)
+	} else {
+		b.WriteString("invalid request:")
+	}
+	for _, f := range verr.Fields {
+		b.WriteString("\n  - ")
+		name, ok := fieldFlags[strings.SplitN(f.Field, ".", 2)[0]]
+		switch {
+		case !ok:
+			b.WriteString(f.String())
+		case verr.APIError != nil:
+			// The server's messages aren't phrased to follow a field name.
+			b.WriteString(name + ": " + f.Message)
+		default:
+			b.WriteString(name + " " + f.Message)
+		}
+	}
+	return b.String()
+}
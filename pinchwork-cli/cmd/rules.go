@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"gopkg.in/yaml.v3"
+)
+
+// workRule routes tasks to a command. Every condition that is set must hold;
+// the first matching rule in the file wins.
+type workRule struct {
+	Name                string   `yaml:"name"`
+	Tags                []string `yaml:"tags,omitempty"`   // any of these tags
+	Search              string   `yaml:"search,omitempty"` // case-insensitive, in need or context
+	MinCredits          int      `yaml:"min_credits,omitempty"`
+	MaxCredits          int      `yaml:"max_credits,omitempty"`
+	MinPosterReputation float64  `yaml:"min_poster_reputation,omitempty"`
+	Exec                string   `yaml:"exec"`
+}
+
+func loadWorkRules(path string) ([]workRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Rules []workRule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+	if len(file.Rules) == 0 {
+		return nil, fmt.Errorf("rules file %s has no rules", path)
+	}
+	for i := range file.Rules {
+		r := &file.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if r.Exec == "" {
+			return nil, fmt.Errorf("rules file %s: %s has no exec command", path, r.Name)
+		}
+	}
+	return file.Rules, nil
+}
+
+func (r workRule) matches(t client.TaskAvailableItem) bool {
+	if len(r.Tags) > 0 && !hasAnyTag(t.Tags, r.Tags) {
+		return false
+	}
+	if r.Search != "" {
+		q := strings.ToLower(r.Search)
+		if !strings.Contains(strings.ToLower(t.Need), q) && !strings.Contains(strings.ToLower(t.Context), q) {
+			return false
+		}
+	}
+	if r.MinCredits > 0 && t.MaxCredits < r.MinCredits {
+		return false
+	}
+	if r.MaxCredits > 0 && t.MaxCredits > r.MaxCredits {
+		return false
+	}
+	if r.MinPosterReputation > 0 && (t.PosterReputation == nil || *t.PosterReputation < r.MinPosterReputation) {
+		return false
+	}
+	return true
+}
+
+func matchRule(rules []workRule, t client.TaskAvailableItem) *workRule {
+	for i := range rules {
+		if rules[i].matches(t) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+func hasAnyTag(tags, want []string) bool {
+	for _, t := range tags {
+		for _, w := range want {
+			if strings.EqualFold(t, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// claimByRules claims the first available task that passes f and matches a
+// rule, returning the rule it matched.
+func claimByRules(c *client.Client, tags, search string, f taskFilter, rules []workRule) (*client.TaskPickupResponse, *workRule, error) {
+	var rule *workRule
+	f = f.withApprovals(c)
+	task, err := claimFirst(c, tags, search, func(t client.TaskAvailableItem) bool {
+		if !f.matches(t) {
+			return false
+		}
+		rule = matchRule(rules, t)
+		return rule != nil
+	})
+	if task == nil {
+		rule = nil
+	}
+	return task, rule, err
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/config"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+)
+
+const (
+	// sandboxProfile is the profile --sandbox selects.
+	sandboxProfile = "sandbox"
+	// defaultSandboxServer is the staging marketplace, which runs on play
+	// credits. PINCHWORK_SANDBOX_SERVER or a configured sandbox profile
+	// point elsewhere, such as a local server.
+	defaultSandboxServer = "https://sandbox.pinchwork.dev"
+)
+
+var (
+	sandboxFlag bool
+	// sandboxActive is set once a client has been made for a sandbox profile.
+	sandboxActive bool
+)
+
+func sandboxServer() string {
+	if env := os.Getenv("PINCHWORK_SANDBOX_SERVER"); env != "" {
+		return env
+	}
+	return defaultSandboxServer
+}
+
+// activeProfile is cfg.ActiveProfile with the sandbox convention applied: a
+// sandbox profile that hasn't been configured yet targets the sandbox server.
+func activeProfile(cfg *config.Config) (config.Profile, string) {
+	p, name := cfg.ActiveProfile(profile)
+	if _, ok := cfg.Profiles[name]; !ok && name == sandboxProfile {
+		p.Server = sandboxServer()
+	}
+	return p, name
+}
+
+func isSandbox(name string, p config.Profile) bool {
+	return name == sandboxProfile || p.Sandbox
+}
+
+var sandboxBannerOnce sync.Once
+
+// sandboxBanner tells the user, once per run, that they are playing with
+// sandbox credits. It is skipped during shell completion, which doesn't set
+// commandPath, so the banner doesn't end up in the completion output.
+func sandboxBanner(server string) {
+	if commandPath == "" {
+		return
+	}
+	sandboxBannerOnce.Do(func() {
+		label := output.Color(os.Stderr, "1;30;43", " SANDBOX ")
+		fmt.Fprintf(os.Stderr, "%s play credits on %s\n", label, server)
+	})
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/config"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Manage saved task searches",
+	Long: `Manage named task filters stored in the current profile. Use them with
+--saved NAME on 'tasks list', 'tasks pickup', 'tasks claim-and-run' and 'work';
+flags given on the command line override the saved values.`,
+}
+
+var searchSaveCmd = &cobra.Command{
+	Use:   "save NAME",
+	Short: "Save a named search",
+	Example: `  pinchwork search save golang --tags go --min-credits 100
+  pinchwork tasks list --saved golang`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var s config.SavedSearch
+		s.Tags, _ = cmd.Flags().GetString("tags")
+		s.Search, _ = cmd.Flags().GetString("search")
+		f := taskFilterFromFlags(cmd)
+		s.MinCredits = f.MinCredits
+		s.MinPosterReputation = f.MinPosterReputation
+		if cmd.Flags().Changed("max-rejections") {
+			s.MaxRejections = &f.MaxRejections
+		}
+		if cmd.Flags().Changed("max-risk") {
+			s.MaxRisk = &f.MaxRisk
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			exitErr(err)
+		}
+		p, profName := cfg.ActiveProfile(profile)
+		if p.Searches == nil {
+			p.Searches = map[string]config.SavedSearch{}
+		}
+		p.Searches[args[0]] = s
+		cfg.SetProfile(profName, p)
+		if err := cfg.Save(configPath()); err != nil {
+			exitErr(fmt.Errorf("save config: %w", err))
+		}
+
+		fmt.Printf("Saved search %q to profile %q\n", args[0], profName)
+	},
+}
+
+var searchListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved searches",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := loadConfig()
+		if err != nil {
+			exitErr(err)
+		}
+		p, _ := cfg.ActiveProfile(profile)
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, p.Searches)
+			return
+		}
+
+		if len(p.Searches) == 0 {
+			fmt.Println("No saved searches. Create one with 'pinchwork search save NAME --tags ...'.")
+			return
+		}
+
+		names := make([]string, 0, len(p.Searches))
+		for name := range p.Searches {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		headers := []string{"NAME", "TAGS", "SEARCH", "MIN CREDITS", "MIN POSTER REP", "MAX REJECTIONS", "MAX RISK"}
+		var rows [][]string
+		for _, name := range names {
+			s := p.Searches[name]
+			row := []string{name, s.Tags, s.Search, "", "", "", ""}
+			if s.MinCredits > 0 {
+				row[3] = strconv.Itoa(s.MinCredits)
+			}
+			if s.MinPosterReputation > 0 {
+				row[4] = strconv.FormatFloat(s.MinPosterReputation, 'f', -1, 64)
+			}
+			if s.MaxRejections != nil {
+				row[5] = strconv.Itoa(*s.MaxRejections)
+			}
+			if s.MaxRisk != nil {
+				row[6] = strconv.FormatFloat(*s.MaxRisk, 'f', -1, 64)
+			}
+			rows = append(rows, row)
+		}
+		output.Table(os.Stdout, headers, rows)
+	},
+}
+
+var searchDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Delete a saved search",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := loadConfig()
+		if err != nil {
+			exitErr(err)
+		}
+		p, profName := cfg.ActiveProfile(profile)
+		if _, ok := p.Searches[args[0]]; !ok {
+			exitErr(fmt.Errorf("no saved search %q in profile %q", args[0], profName))
+		}
+		delete(p.Searches, args[0])
+		cfg.SetProfile(profName, p)
+		if err := cfg.Save(configPath()); err != nil {
+			exitErr(fmt.Errorf("save config: %w", err))
+		}
+
+		fmt.Printf("Deleted saved search %q\n", args[0])
+	},
+}
+
+// addSavedFlag adds --saved to a command that has the tags, search and
+// filter flags.
+func addSavedFlag(cmd *cobra.Command) {
+	cmd.Flags().String("saved", "", "apply a saved search (see 'pinchwork search')")
+}
+
+// applySavedSearch fills in the filter flags from the --saved search, leaving
+// flags set on the command line alone. Call it before reading those flags.
+func applySavedSearch(cmd *cobra.Command) {
+	name, _ := cmd.Flags().GetString("saved")
+	if name == "" {
+		return
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		exitErr(err)
+	}
+	p, profName := cfg.ActiveProfile(profile)
+	s, ok := p.Searches[name]
+	if !ok {
+		exitErr(fmt.Errorf("no saved search %q in profile %q", name, profName))
+	}
+
+	values := map[string]string{}
+	if s.Tags != "" {
+		values["tags"] = s.Tags
+	}
+	if s.Search != "" {
+		values["search"] = s.Search
+	}
+	if s.MinCredits > 0 {
+		values["min-credits"] = strconv.Itoa(s.MinCredits)
+	}
+	if s.MinPosterReputation > 0 {
+		values["min-poster-reputation"] = strconv.FormatFloat(s.MinPosterReputation, 'f', -1, 64)
+	}
+	if s.MaxRejections != nil {
+		values["max-rejections"] = strconv.Itoa(*s.MaxRejections)
+	}
+	if s.MaxRisk != nil {
+		values["max-risk"] = strconv.FormatFloat(*s.MaxRisk, 'f', -1, 64)
+	}
+	for flag, v := range values {
+		if !cmd.Flags().Changed(flag) {
+			cmd.Flags().Set(flag, v)
+		}
+	}
+}
+
+func init() {
+	searchSaveCmd.Flags().String("tags", "", "filter by tags (comma-separated)")
+	searchSaveCmd.Flags().String("search", "", "search term")
+	addTaskFilterFlags(searchSaveCmd)
+
+	searchCmd.AddCommand(searchSaveCmd)
+	searchCmd.AddCommand(searchListCmd)
+	searchCmd.AddCommand(searchDeleteCmd)
+	rootCmd.AddCommand(searchCmd)
+}
@@ -0,0 +1,408 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	injectLatency   time.Duration
+	injectErrorRate float64
+	// chaos is the transport the hidden --inject-* flags put in front of
+	// every client.
+	chaos *client.ChaosTransport
+)
+
+// injectChaos makes every client slow and unreliable as the --inject-*
+// flags ask.
+func injectChaos() error {
+	if injectErrorRate < 0 || injectErrorRate > 1 {
+		return fmt.Errorf("--inject-error-rate must be between 0 and 1")
+	}
+	if injectLatency == 0 && injectErrorRate == 0 {
+		return nil
+	}
+	if chaos == nil {
+		chaos = &client.ChaosTransport{Base: client.DefaultTransport}
+		client.DefaultTransport = chaos
+	}
+	chaos.Latency = injectLatency
+	chaos.ErrorRate = injectErrorRate
+	return nil
+}
+
+// selftestMock is the server the self-test runs against: just enough of
+// chunked uploads and the event stream to see the client recover.
+type selftestMock struct {
+	mu      sync.Mutex
+	uploads map[string]*mockUpload
+	// Every other event stream connection stalls: it sends nothing until
+	// the client gives up on it.
+	conns  int
+	events int
+}
+
+type mockUpload struct {
+	id   string
+	size int64
+	data []byte
+	done bool
+	// starts counts the uploads started for the task, and rejected the
+	// chunks sent for an offset the server wasn't at.
+	starts   int
+	rejected int
+}
+
+const selftestChunkSize = 4 << 10
+
+func mockJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (m *selftestMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/v1/events" {
+		m.serveEvents(w, r)
+		return
+	}
+	// /v1/tasks/{id}/uploads[/current|/{upload}[/complete]]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/tasks/"), "/")
+	if len(parts) < 2 || parts[1] != "uploads" {
+		mockJSON(w, 404, map[string]string{"error": "not found"})
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	taskID := parts[0]
+	up := m.uploads[taskID]
+	session := func() client.UploadSession {
+		return client.UploadSession{UploadID: up.id, TaskID: taskID, Size: up.size, Offset: int64(len(up.data)), ChunkSize: selftestChunkSize}
+	}
+
+	switch {
+	case r.Method == "POST" && len(parts) == 2:
+		var body struct {
+			Size int64 `json:"size"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		starts := 1
+		if up != nil {
+			starts = up.starts + 1
+		}
+		up = &mockUpload{id: fmt.Sprintf("up-%d", starts), size: body.Size, starts: starts}
+		m.uploads[taskID] = up
+		mockJSON(w, 201, session())
+	case up == nil:
+		mockJSON(w, 404, map[string]string{"error": "no upload in progress"})
+	case r.Method == "GET" && len(parts) == 3 && parts[2] == "current":
+		mockJSON(w, 200, session())
+	case r.Method == "PUT" && len(parts) == 3 && parts[2] == up.id:
+		offset, _ := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+		chunk, _ := io.ReadAll(r.Body)
+		if offset != int64(len(up.data)) || up.done {
+			up.rejected++
+			mockJSON(w, 409, map[string]string{"error": "offset mismatch"})
+			return
+		}
+		up.data = append(up.data, chunk...)
+		mockJSON(w, 200, session())
+	case r.Method == "POST" && len(parts) == 4 && parts[2] == up.id && parts[3] == "complete":
+		if int64(len(up.data)) != up.size {
+			mockJSON(w, 409, map[string]string{"error": "upload incomplete"})
+			return
+		}
+		// Completing again, after the response was lost, is harmless.
+		up.done = true
+		mockJSON(w, 200, client.TaskResponse{TaskID: taskID, Status: client.StatusDelivered})
+	default:
+		mockJSON(w, 404, map[string]string{"error": "not found"})
+	}
+}
+
+// serveEvents sends one event per connection and hangs up, except on every
+// other connection, which stalls.
+func (m *selftestMock) serveEvents(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.conns++
+	stall := m.conns%2 == 0
+	if !stall {
+		m.events++
+	}
+	n := m.events
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(200)
+	w.(http.Flusher).Flush()
+	if stall {
+		<-r.Context().Done()
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: task_delivered\ndata: {\"task_id\":\"tk-%d\"}\n\n", n, n)
+}
+
+type selftestResult struct {
+	Check   string  `json:"check"`
+	OK      bool    `json:"ok"`
+	Seconds float64 `json:"seconds"`
+	Detail  string  `json:"detail,omitempty"`
+	Error   string  `json:"error,omitempty"`
+}
+
+type selftestCheck struct {
+	name string
+	run  func(c *client.Client, m *selftestMock) (string, error)
+}
+
+var selftestChecks = []selftestCheck{
+	{"upload-retry", selftestUploadRetry},
+	{"upload-resume", selftestUploadResume},
+	{"events-reconnect", selftestEventsReconnect},
+}
+
+func selftestPayload(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i*7 + i/251)
+	}
+	return b
+}
+
+// deliverRerunning runs a chunked delivery until it succeeds, the way the
+// docs tell users to recover from a failed one: by running it again.
+func deliverRerunning(c *client.Client, taskID string, payload []byte) (int, error) {
+	for runs := 1; ; runs++ {
+		_, err := c.DeliverTaskChunked(taskID, bytes.NewReader(payload), int64(len(payload)), nil, nil)
+		if err == nil || !errors.Is(err, client.ErrInjected) {
+			return runs, err
+		}
+		if runs == 20 {
+			return runs, fmt.Errorf("still failing after %d runs: %w", runs, err)
+		}
+	}
+}
+
+// checkUpload compares what the mock received for a task with payload.
+func (m *selftestMock) checkUpload(taskID string, payload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	up := m.uploads[taskID]
+	switch {
+	case up == nil || !up.done:
+		return fmt.Errorf("the upload was not completed")
+	case !bytes.Equal(up.data, payload):
+		return fmt.Errorf("the server got %d bytes that differ from the %d sent", len(up.data), len(payload))
+	case up.starts != 1:
+		return fmt.Errorf("the upload was started %d times instead of resumed", up.starts)
+	case up.rejected != 0:
+		return fmt.Errorf("%d chunks were sent for the wrong offset", up.rejected)
+	}
+	return nil
+}
+
+func selftestUploadRetry(c *client.Client, m *selftestMock) (string, error) {
+	payload := selftestPayload(64 << 10)
+	runs, err := deliverRerunning(c, "tk-retry", payload)
+	if err != nil {
+		return "", err
+	}
+	if err := m.checkUpload("tk-retry", payload); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s in %d chunks, %d runs", output.Size(len(payload)), len(payload)/selftestChunkSize, runs), nil
+}
+
+var errInterrupted = errors.New("interrupted")
+
+// interruptedReader fails reads past limit, like a delivery killed midway.
+type interruptedReader struct {
+	*bytes.Reader
+	limit int64
+}
+
+func (r interruptedReader) Read(p []byte) (int, error) {
+	pos, _ := r.Seek(0, io.SeekCurrent)
+	if pos >= r.limit {
+		return 0, errInterrupted
+	}
+	if int64(len(p)) > r.limit-pos {
+		p = p[:r.limit-pos]
+	}
+	return r.Reader.Read(p)
+}
+
+func selftestUploadResume(c *client.Client, m *selftestMock) (string, error) {
+	payload := selftestPayload(64 << 10)
+	r := interruptedReader{Reader: bytes.NewReader(payload), limit: 24 << 10}
+	// The reader is what interrupts this delivery; an injected failure
+	// before the first chunk would leave nothing to resume.
+	var rate float64
+	if chaos != nil {
+		rate, chaos.ErrorRate = chaos.ErrorRate, 0
+	}
+	_, err := c.DeliverTaskChunked("tk-resume", r, int64(len(payload)), nil, nil)
+	if chaos != nil {
+		chaos.ErrorRate = rate
+	}
+	if err == nil {
+		return "", fmt.Errorf("the interrupted delivery succeeded")
+	}
+	m.mu.Lock()
+	var at int
+	if up := m.uploads["tk-resume"]; up != nil {
+		at = len(up.data)
+	}
+	m.mu.Unlock()
+	if at == 0 {
+		return "", fmt.Errorf("the interrupted delivery sent nothing to resume from: %w", err)
+	}
+
+	runs, err := deliverRerunning(c, "tk-resume", payload)
+	if err != nil {
+		return "", err
+	}
+	if err := m.checkUpload("tk-resume", payload); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("interrupted at %s, resumed in %d runs, every byte sent once", output.Size(at), runs), nil
+}
+
+func selftestEventsReconnect(c *client.Client, m *selftestMock) (string, error) {
+	defer func(d time.Duration) { client.EventHeartbeatTimeout = d }(client.EventHeartbeatTimeout)
+	client.EventHeartbeatTimeout = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ch := make(chan client.SSEEvent)
+	var mu sync.Mutex
+	drops, stalls := 0, 0
+	done := make(chan error, 1)
+	go func() {
+		done <- c.FollowEvents(ctx, ch, func(err error, retryIn time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			drops++
+			if errors.Is(err, client.ErrStreamStalled) {
+				stalls++
+			}
+		})
+	}()
+
+	// Keep listening until a stall was seen too; the injected failures can
+	// hit the stalling connections.
+	last, events := 0, 0
+	for {
+		mu.Lock()
+		enough := events >= 3 && stalls > 0
+		mu.Unlock()
+		if enough {
+			break
+		}
+		select {
+		case e := <-ch:
+			n, _ := strconv.Atoi(e.ID)
+			if n <= last {
+				cancel()
+				return "", fmt.Errorf("event %s came after event %d", e.ID, last)
+			}
+			last = n
+			events++
+		case err := <-done:
+			return "", fmt.Errorf("the stream gave up: %v", err)
+		case <-ctx.Done():
+			return "", fmt.Errorf("got %d events and %d stalls within a minute", events, stalls)
+		}
+	}
+	cancel()
+	<-done
+	return fmt.Sprintf("%d events over %d drops, %d stalls detected", events, drops, stalls), nil
+}
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Check that the client recovers from a bad network",
+	Long: `Run the client's reliability features against a built-in mock server over a
+deliberately bad network, and check that they recover:
+
+  upload-retry      a chunked delivery, re-run after failures, arrives intact
+  upload-resume     an interrupted chunked delivery resumes where the server
+                    is, without sending a byte twice
+  events-reconnect  the event stream reconnects after the server hangs up
+                    and after it stalls
+
+Requests are delayed and fail at random, some before reaching the server
+and some with the response lost after the server handled them. Tune this
+with the hidden --inject-latency and --inject-error-rate flags, which work
+on any command (default here: 20ms and 0.2).
+
+Exits 1 when a check fails, so it can run in CI.`,
+	Example: `  pinchwork selftest
+  pinchwork selftest --inject-error-rate 0.5 --inject-latency 100ms`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !cmd.Flags().Changed("inject-latency") && !cmd.Flags().Changed("inject-error-rate") {
+			injectLatency, injectErrorRate = 20*time.Millisecond, 0.2
+		}
+		if err := injectChaos(); err != nil {
+			exitErr(err)
+		}
+
+		m := &selftestMock{uploads: map[string]*mockUpload{}}
+		srv := httptest.NewServer(m)
+		defer srv.Close()
+		c := client.New(srv.URL, "selftest")
+
+		var results []selftestResult
+		failed := false
+		for _, check := range selftestChecks {
+			start := time.Now()
+			detail, err := check.run(c, m)
+			res := selftestResult{Check: check.name, OK: err == nil, Seconds: time.Since(start).Seconds(), Detail: detail}
+			if err != nil {
+				res.Error = err.Error()
+				failed = true
+			}
+			results = append(results, res)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, results)
+		} else {
+			var rows [][]string
+			for _, r := range results {
+				result, detail := "ok", r.Detail
+				if !r.OK {
+					result, detail = "FAIL", r.Error
+				}
+				rows = append(rows, []string{r.Check, result, fmt.Sprintf("%.1fs", r.Seconds), detail})
+			}
+			output.Table(os.Stdout, []string{"CHECK", "RESULT", "TIME", "DETAIL"}, rows)
+			if chaos != nil {
+				fmt.Printf("\n%d failures injected.\n", chaos.Injected.Load())
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+}
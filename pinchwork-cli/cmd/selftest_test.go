@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+)
+
+func TestSelftestChecks(t *testing.T) {
+	tests := []struct {
+		name      string
+		latency   time.Duration
+		errorRate float64
+	}{
+		{"good network", 0, 0},
+		{"bad network", 5 * time.Millisecond, 0.2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			injectLatency, injectErrorRate = tt.latency, tt.errorRate
+			if err := injectChaos(); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				if chaos != nil {
+					client.DefaultTransport = chaos.Base
+					chaos = nil
+				}
+				injectLatency, injectErrorRate = 0, 0
+			})
+
+			m := &selftestMock{uploads: map[string]*mockUpload{}}
+			srv := httptest.NewServer(m)
+			defer srv.Close()
+			c := client.New(srv.URL, "selftest")
+			for _, check := range selftestChecks {
+				detail, err := check.run(c, m)
+				if err != nil {
+					t.Fatalf("%s: %v", check.name, err)
+				}
+				t.Logf("%s: %s", check.name, detail)
+			}
+		})
+	}
+}
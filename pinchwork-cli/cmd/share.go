@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/skip2/go-qrcode"
+	"github.com/spf13/cobra"
+)
+
+type taskShare struct {
+	TaskID    string      `json:"task_id"`
+	URL       string      `json:"url"`
+	Short     bool        `json:"short"`
+	ExpiresAt client.Time `json:"expires_at,omitempty"`
+}
+
+// shareTask gets a short link for the task, or its full web address from
+// servers without short links.
+func shareTask(c *client.Client, taskID string) (*taskShare, error) {
+	resp, err := c.ShareTask(taskID)
+	if err != nil && !errors.Is(err, client.ErrNotFound) {
+		return nil, err
+	}
+	if err == nil && resp.URL != "" {
+		return &taskShare{TaskID: taskID, URL: resp.URL, Short: true, ExpiresAt: resp.ExpiresAt}, nil
+	}
+	// A 404 is either an unknown task or a server without the endpoint.
+	if _, err := c.GetTask(taskID); err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stderr, "This server has no short links; sharing the task's full address.")
+	return &taskShare{TaskID: taskID, URL: c.TaskWebURL(taskID)}, nil
+}
+
+var tasksShareCmd = &cobra.Command{
+	Use:   "share TASK_ID",
+	Short: "Get a short link to a task, optionally as a QR code",
+	Long: `Print a short link to the task's page on the web dashboard, to hand the
+task to someone else or look at it on another device. With --qr, also draw
+the link as a QR code in the terminal to scan with a phone.
+
+Servers without short links get the full address of the task page.`,
+	Example: `  pinchwork tasks share tk-abc123
+  pinchwork tasks share tk-abc123 --qr`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		share, err := shareTask(c, args[0])
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, share)
+			return
+		}
+		if qr, _ := cmd.Flags().GetBool("qr"); qr {
+			code, err := qrcode.New(share.URL, qrcode.Medium)
+			if err != nil {
+				exitErr(err)
+			}
+			// Dark modules are left blank, which scans on dark terminals.
+			fmt.Print(code.ToSmallString(false))
+		}
+		fmt.Println(share.URL)
+		if !share.ExpiresAt.IsZero() {
+			fmt.Fprintf(os.Stderr, "The link expires in %s.\n", formatDuration(time.Until(share.ExpiresAt.Time)))
+		}
+	},
+}
+
+func init() {
+	tasksShareCmd.Flags().Bool("qr", false, "also print the link as a QR code")
+
+	tasksCmd.AddCommand(tasksShareCmd)
+}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/slack"
+	"github.com/spf13/cobra"
+)
+
+var slackCmd = &cobra.Command{
+	Use:   "slack",
+	Short: "Oversee delegation from Slack",
+}
+
+var slackServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a Slack slash command and post task events to a channel",
+	Long: `Serve a Slack slash command that acts on the marketplace as this profile,
+and post your task events to a channel, so people can supervise agent
+delegation from Slack.
+
+Point the slash command's request URL (e.g. /pinchwork) at --listen and
+--path. It understands:
+
+  /pinchwork post [CREDITS] NEED   post a task
+  /pinchwork status TASK_ID        show a task
+  /pinchwork approve TASK_ID       approve a delivery
+  /pinchwork help
+
+Requests are verified with the app's signing secret. Use --users to limit
+who may post and approve. With --channel, events are posted there with the
+bot token.`,
+	Example: `  SLACK_SIGNING_SECRET=... pinchwork slack serve --token xoxb-... --channel C0123456 --listen :3000
+  pinchwork slack serve --token xoxb-... --signing-secret ... --users U012ABC,U034DEF`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		token, _ := cmd.Flags().GetString("token")
+		if token == "" {
+			token = os.Getenv("SLACK_BOT_TOKEN")
+		}
+		secret, _ := cmd.Flags().GetString("signing-secret")
+		if secret == "" {
+			secret = os.Getenv("SLACK_SIGNING_SECRET")
+		}
+		if secret == "" {
+			exitErr(fmt.Errorf("--signing-secret (or SLACK_SIGNING_SECRET) is required to verify requests from Slack"))
+		}
+		channel, _ := cmd.Flags().GetString("channel")
+		if channel != "" && token == "" {
+			exitErr(fmt.Errorf("--channel needs --token (or SLACK_BOT_TOKEN) to post events"))
+		}
+		listen, _ := cmd.Flags().GetString("listen")
+		path, _ := cmd.Flags().GetString("path")
+		users := map[string]bool{}
+		if s, _ := cmd.Flags().GetString("users"); s != "" {
+			for _, u := range strings.Split(s, ",") {
+				users[strings.TrimSpace(u)] = true
+			}
+		}
+
+		log, closeLog := newWorkLogger(cmd)
+		defer closeLog()
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		sb := &slackBridge{c: c, secret: secret, users: users, log: log}
+		mux := http.NewServeMux()
+		mux.Handle(path, sb)
+		srv := &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			srv.Shutdown(shutdownCtx)
+		}()
+
+		if channel != "" {
+			sc := &slack.Client{Token: token, HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+			go postEventsToSlack(ctx, c, sc, channel, log)
+		}
+
+		log.Info("slack bridge started", "listen", listen, "path", path, "channel", channel)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			exitErr(err)
+		}
+		log.Info("slack bridge stopped")
+	},
+}
+
+// slackBridge handles slash commands.
+type slackBridge struct {
+	c      *client.Client
+	secret string
+	users  map[string]bool
+	log    *slog.Logger
+}
+
+func (sb *slackBridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64<<10))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if err := slack.Verify(r.Header, body, sb.secret, time.Now()); err != nil {
+		sb.log.Warn("rejected slack request", "err", err, "remote", r.RemoteAddr)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	user := form.Get("user_id")
+	text := strings.TrimSpace(form.Get("text"))
+	sb.log.Info("slash command", "user", user, "user_name", form.Get("user_name"), "text", text)
+	w.Header().Set("Content-Type", "application/json")
+	output.JSON(w, sb.run(user, text))
+}
+
+func (sb *slackBridge) run(user, text string) slack.Response {
+	verb, rest, _ := strings.Cut(text, " ")
+	rest = strings.TrimSpace(rest)
+	if (verb == "post" || verb == "approve") && len(sb.users) > 0 && !sb.users[user] {
+		return slack.Ephemeral("You are not allowed to %s tasks from Slack.", verb)
+	}
+
+	switch verb {
+	case "post":
+		credits := 50
+		if first, need, ok := strings.Cut(rest, " "); ok {
+			if n, err := strconv.Atoi(first); err == nil {
+				credits, rest = n, strings.TrimSpace(need)
+			}
+		}
+		if rest == "" {
+			return slack.Ephemeral("Usage: `post [CREDITS] NEED`")
+		}
+		resp, err := sb.c.CreateTask(client.TaskCreateRequest{Need: rest, MaxCredits: credits})
+		if err != nil {
+			return sb.failed("post the task", err)
+		}
+		recordHistory("created", resp.TaskID, &credits, rest)
+		return slack.InChannel("<@%s> posted task `%s` for up to %d credits: %s", user, resp.TaskID, credits, rest)
+	case "status":
+		if rest == "" {
+			return slack.Ephemeral("Usage: `status TASK_ID`")
+		}
+		task, err := sb.c.GetTask(rest)
+		if err != nil {
+			return sb.failed("look up the task", err)
+		}
+		msg := fmt.Sprintf("Task `%s` is *%s*: %s", task.TaskID, task.Status, output.Truncate(task.Need, 200))
+		if task.Result != "" {
+			msg += "\n>" + output.Truncate(task.Result, 500)
+		}
+		return slack.Ephemeral("%s", msg)
+	case "approve":
+		if rest == "" {
+			return slack.Ephemeral("Usage: `approve TASK_ID`")
+		}
+		resp, err := sb.c.ApproveTask(rest, nil, "")
+		if err != nil {
+			return sb.failed("approve the task", err)
+		}
+		recordHistory("approved", resp.TaskID, resp.CreditsCharged, "via slack")
+		msg := fmt.Sprintf("<@%s> approved task `%s`", user, resp.TaskID)
+		if resp.CreditsCharged != nil {
+			msg += fmt.Sprintf(" (%d credits)", *resp.CreditsCharged)
+		}
+		return slack.InChannel("%s", msg)
+	case "", "help":
+		return slack.Ephemeral("`post [CREDITS] NEED` · `status TASK_ID` · `approve TASK_ID`")
+	}
+	return slack.Ephemeral("Unknown command %q. Try `help`.", verb)
+}
+
+func (sb *slackBridge) failed(action string, err error) slack.Response {
+	sb.log.Error("slash command failed", "action", action, "err", err)
+	if hint := errorHint(err); hint != "" {
+		return slack.Ephemeral("Could not %s: %s\n_%s_", action, err, hint)
+	}
+	return slack.Ephemeral("Could not %s: %s", action, err)
+}
+
+// postEventsToSlack posts each live event to channel until ctx is done.
+func postEventsToSlack(ctx context.Context, c *client.Client, sc *slack.Client, channel string, log *slog.Logger) {
+	ch := make(chan client.SSEEvent, 100)
+	go func() {
+		onDrop := func(err error, retryIn time.Duration) {
+			log.Warn("event stream dropped, reconnecting", "err", err, "retry_in", retryIn)
+		}
+		if err := c.FollowEvents(ctx, ch, onDrop); err != nil {
+			log.Error("event stream failed", "err", err)
+		}
+		close(ch)
+	}()
+	for event := range ch {
+		text := fmt.Sprintf("*%s* · task `%s`", event.Type, event.TaskID)
+		if need, ok := event.Data["need"].(string); ok && need != "" {
+			text += ": " + output.Truncate(need, 200)
+		}
+		if err := sc.PostMessage(channel, text); err != nil {
+			log.Error("could not post event to slack", "event", event.Type, "task_id", event.TaskID, "err", err)
+		}
+	}
+}
+
+func init() {
+	slackServeCmd.Flags().String("token", "", "bot token for posting events (default: $SLACK_BOT_TOKEN)")
+	slackServeCmd.Flags().String("signing-secret", "", "the Slack app's signing secret (default: $SLACK_SIGNING_SECRET)")
+	slackServeCmd.Flags().String("channel", "", "channel ID to post task events to")
+	slackServeCmd.Flags().String("listen", ":3000", "address to serve the slash command on")
+	slackServeCmd.Flags().String("path", "/slack/commands", "URL path of the slash command")
+	slackServeCmd.Flags().String("users", "", "Slack user IDs allowed to post and approve (comma-separated; default: everyone in the workspace)")
+	addLogFlags(slackServeCmd)
+
+	slackCmd.AddCommand(slackServeCmd)
+	rootCmd.AddCommand(slackCmd)
+}
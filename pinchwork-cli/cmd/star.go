@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var tasksStarCmd = &cobra.Command{
+	Use:   "star TASK_ID",
+	Short: "Add a task to your local shortlist",
+	Long: `Add a task to your shortlist, kept on this machine per profile. Claim
+starred tasks later, highest priority first, with 'tasks pickup --from-starred'.`,
+	Example: `  pinchwork tasks star tk-abc123
+  pinchwork tasks star tk-def456 --priority 10`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		task, err := c.GetTask(args[0])
+		if err != nil {
+			exitErr(err)
+		}
+
+		priority, _ := cmd.Flags().GetInt("priority")
+		st, path := loadState()
+		st.AddStar(state.Star{
+			TaskID:    task.TaskID,
+			Priority:  priority,
+			Need:      task.Need,
+			StarredAt: time.Now().UTC(),
+		})
+		saveState(st, path)
+		fmt.Printf("Starred task %s\n", task.TaskID)
+	},
+}
+
+var tasksUnstarCmd = &cobra.Command{
+	Use:   "unstar TASK_ID",
+	Short: "Remove a task from your shortlist",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		st, path := loadState()
+		if !st.RemoveStar(args[0]) {
+			exitErr(fmt.Errorf("task %s is not starred", args[0]))
+		}
+		saveState(st, path)
+		fmt.Printf("Unstarred task %s\n", args[0])
+	},
+}
+
+var tasksStarredCmd = &cobra.Command{
+	Use:   "starred",
+	Short: "List your shortlisted tasks in priority order",
+	Run: func(cmd *cobra.Command, args []string) {
+		st, _ := loadState()
+		stars := st.StarsByPriority()
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, stars)
+			return
+		}
+		if quiet {
+			for _, s := range stars {
+				printIDs(s.TaskID)
+			}
+			return
+		}
+
+		if len(stars) == 0 {
+			fmt.Println("No starred tasks.")
+			return
+		}
+
+		headers := []string{"ID", "PRIORITY", "NEED", "STARRED"}
+		var rows [][]string
+		for _, s := range stars {
+			rows = append(rows, []string{
+				s.TaskID,
+				fmt.Sprintf("%d", s.Priority),
+				output.Cell(s.Need, 50),
+				output.DateTime(s.StarredAt),
+			})
+		}
+		output.Table(os.Stdout, headers, rows)
+	},
+}
+
+// pickupStarred claims the highest-priority starred task that is still
+// available. Stars of tasks that no longer exist or were claimed are dropped,
+// as is the star of the task that gets claimed.
+func pickupStarred(c *client.Client) (*client.TaskPickupResponse, error) {
+	st, path := loadState()
+	defer saveState(st, path)
+
+	for _, s := range st.StarsByPriority() {
+		task, err := c.PickupSpecificTask(s.TaskID)
+		if errors.Is(err, client.ErrNotFound) || errors.Is(err, client.ErrTaskNotClaimable) {
+			fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", s.TaskID, err)
+			st.RemoveStar(s.TaskID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		st.RemoveStar(s.TaskID)
+		if task != nil {
+			return task, nil
+		}
+		fmt.Fprintf(os.Stderr, "Skipping %s: no longer available\n", s.TaskID)
+	}
+	return nil, nil
+}
+
+func statePath(profName string) string {
+	return filepath.Join(filepath.Dir(configPath()), "state", profName+".json")
+}
+
+// loadState reads the active profile's local state, returning it with the
+// path to save it back to.
+func loadState() (*state.State, string) {
+	cfg, err := loadConfig()
+	if err != nil {
+		exitErr(err)
+	}
+	_, profName := cfg.ActiveProfile(profile)
+	path := statePath(profName)
+	st, err := state.Load(path)
+	if err != nil {
+		exitErr(err)
+	}
+	return st, path
+}
+
+func saveState(st *state.State, path string) {
+	if err := st.Save(path); err != nil {
+		exitErr(fmt.Errorf("save local state: %w", err))
+	}
+}
+
+func init() {
+	tasksStarCmd.Flags().Int("priority", 0, "higher priorities are claimed first")
+
+	tasksCmd.AddCommand(tasksStarCmd)
+	tasksCmd.AddCommand(tasksUnstarCmd)
+	tasksCmd.AddCommand(tasksStarredCmd)
+}
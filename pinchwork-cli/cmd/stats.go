@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show your earnings dashboard",
+	Long: `Show your earnings dashboard.
+
+Use --by day or --by week for an earnings/spend time series built from your
+ledger, or --by tag for all-time earnings per task tag. --export csv writes the
+breakdown as CSV instead of a table.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		by, _ := cmd.Flags().GetString("by")
+		sinceStr, _ := cmd.Flags().GetString("since")
+		export, _ := cmd.Flags().GetString("export")
+
+		if export != "" && export != "csv" {
+			exitErr(fmt.Errorf("unsupported export format %q (supported: csv)", export))
+		}
+		if export != "" && by == "" {
+			by = "day"
+		}
+
+		switch by {
+		case "":
+		case "day", "week":
+			if sinceStr == "" {
+				sinceStr = map[string]string{"day": "30d", "week": "12w"}[by]
+			}
+			since, err := parseSince(sinceStr)
+			if err != nil {
+				exitErr(err)
+			}
+			series, err := earningsSeries(c, by, since)
+			if err != nil {
+				exitErr(err)
+			}
+			printSeries(series, export)
+			return
+		case "tag":
+			if sinceStr != "" {
+				exitErr(fmt.Errorf("--since can't be used with --by tag: the server only keeps all-time totals per tag"))
+			}
+			resp, err := c.GetStats()
+			if err != nil {
+				exitErr(err)
+			}
+			printTagStats(resp.TasksByTag, export)
+			return
+		default:
+			exitErr(fmt.Errorf("invalid --by %q: use day, week or tag", by))
+		}
+
+		resp, err := c.GetStats()
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		fmt.Printf("Total Earned:    %d credits\n", resp.TotalEarned)
+		fmt.Printf("Total Spent:     %d credits\n", resp.TotalSpent)
+		fmt.Printf("Fees Paid:       %d credits\n", resp.TotalFeesPaid)
+		if resp.ApprovalRate != nil {
+			fmt.Printf("Approval Rate:   %.1f%%\n", *resp.ApprovalRate*100)
+		}
+		if resp.AvgTaskValue != nil {
+			fmt.Printf("Avg Task Value:  %.1f credits\n", *resp.AvgTaskValue)
+		}
+		fmt.Printf("Earned (7d):     %d credits\n", resp.Recent7dEarned)
+		fmt.Printf("Earned (30d):    %d credits\n", resp.Recent30dEarned)
+
+		if len(resp.TasksByTag) > 0 {
+			top := resp.TasksByTag
+			if len(top) > 5 {
+				top = top[:5]
+			}
+			fmt.Println("\nTop tags:")
+			printTagStats(top, "")
+		}
+	},
+}
+
+type seriesPoint struct {
+	Period string `json:"period"`
+	Earned int    `json:"earned"`
+	Spent  int    `json:"spent"`
+	Net    int    `json:"net"`
+}
+
+// earningsSeries buckets ledger entries since the given time into days or
+// ISO weeks. Empty periods are included so the series has no gaps.
+func earningsSeries(c *client.Client, by string, since time.Time) ([]seriesPoint, error) {
+	var periods []string
+	index := map[string]int{}
+	for t := periodStart(since, by); !t.After(time.Now()); t = nextPeriod(t, by) {
+		key := t.Format("2006-01-02")
+		index[key] = len(periods)
+		periods = append(periods, key)
+	}
+	series := make([]seriesPoint, len(periods))
+	for i, p := range periods {
+		series[i].Period = p
+	}
+
+	err := c.WalkLedger(since, func(e client.LedgerEntry) error {
+		t, err := client.ParseTime(e.CreatedAt)
+		if err != nil {
+			return nil
+		}
+		i, ok := index[periodStart(t, by).Format("2006-01-02")]
+		if !ok {
+			return nil
+		}
+		switch e.Reason {
+		case client.LedgerPayment:
+			series[i].Earned += e.Amount
+		case client.LedgerEscrow, client.LedgerRefund:
+			// Escrow debits are negative and refunds positive.
+			series[i].Spent -= e.Amount
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range series {
+		series[i].Net = series[i].Earned - series[i].Spent
+	}
+	return series, nil
+}
+
+func periodStart(t time.Time, by string) time.Time {
+	t = t.Local()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	if by == "week" {
+		offset := (int(day.Weekday()) + 6) % 7 // Monday-based
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+func nextPeriod(t time.Time, by string) time.Time {
+	if by == "week" {
+		return t.AddDate(0, 0, 7)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+func printSeries(series []seriesPoint, export string) {
+	if export == "csv" {
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"period", "earned", "spent", "net"})
+		for _, p := range series {
+			w.Write([]string{p.Period, strconv.Itoa(p.Earned), strconv.Itoa(p.Spent), strconv.Itoa(p.Net)})
+		}
+		w.Flush()
+		return
+	}
+
+	if outputFmt == "json" {
+		output.JSON(os.Stdout, series)
+		return
+	}
+
+	headers := []string{"PERIOD", "EARNED", "SPENT", "NET"}
+	var rows [][]string
+	var earned, spent []int
+	for _, p := range series {
+		rows = append(rows, []string{p.Period, strconv.Itoa(p.Earned), strconv.Itoa(p.Spent), strconv.Itoa(p.Net)})
+		earned = append(earned, p.Earned)
+		spent = append(spent, p.Spent)
+	}
+	output.Table(os.Stdout, headers, rows)
+	fmt.Printf("\nEarned: %s\n", output.Sparkline(earned))
+	fmt.Printf("Spent:  %s\n", output.Sparkline(spent))
+}
+
+func printTagStats(tags []client.TagStat, export string) {
+	if export == "csv" {
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"tag", "tasks", "earned"})
+		for _, t := range tags {
+			w.Write([]string{t.Tag, strconv.Itoa(t.Count), strconv.Itoa(t.Earned)})
+		}
+		w.Flush()
+		return
+	}
+
+	if outputFmt == "json" {
+		output.JSON(os.Stdout, tags)
+		return
+	}
+
+	if len(tags) == 0 {
+		fmt.Println("No tagged tasks completed yet.")
+		return
+	}
+
+	headers := []string{"TAG", "TASKS", "EARNED"}
+	var rows [][]string
+	for _, t := range tags {
+		rows = append(rows, []string{t.Tag, strconv.Itoa(t.Count), strconv.Itoa(t.Earned)})
+	}
+	output.Table(os.Stdout, headers, rows)
+}
+
+func init() {
+	statsCmd.Flags().String("by", "", "breakdown: day, week or tag")
+	statsCmd.Flags().String("since", "", "start of the time series (default 30d for --by day, 12w for --by week)")
+	statsCmd.Flags().String("export", "", "export the breakdown instead of a table: csv")
+
+	rootCmd.AddCommand(statsCmd)
+}
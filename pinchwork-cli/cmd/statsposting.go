@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/history"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// observeStatuses notes when tasks were first seen in their current status,
+// for 'stats posting'. A claimed task's claim time is exact when its claim
+// timeout is known: the claim deadline is set that long after the claim.
+func observeStatuses(st *state.State, path string, tasks []client.TaskResponse) {
+	now := time.Now()
+	changed := false
+	for _, t := range tasks {
+		at := now
+		if t.Status == client.StatusClaimed && !t.ClaimDeadline.IsZero() && t.ClaimTimeoutMinutes != nil {
+			// A rejected delivery is claimed again, with a grace deadline.
+			if _, redo := st.Seen[t.TaskID][string(client.StatusDelivered)]; !redo {
+				at = t.ClaimDeadline.Add(-time.Duration(*t.ClaimTimeoutMinutes) * time.Minute)
+			}
+		}
+		if st.SeeStatus(t.TaskID, string(t.Status), at) {
+			changed = true
+		}
+	}
+	if changed {
+		if err := st.Save(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not save local state: %s\n", err)
+		}
+	}
+}
+
+// stageTimes are how long posted tasks took to reach a stage, in seconds.
+type stageTimes struct {
+	Stage   string `json:"stage"`
+	Tasks   int    `json:"tasks"`
+	Median  int    `json:"median_seconds"`
+	Average int    `json:"average_seconds"`
+	Slowest int    `json:"slowest_seconds"`
+}
+
+type tagTimes struct {
+	Tag           string `json:"tag"`
+	Tasks         int    `json:"tasks"`
+	Claimed       int    `json:"claimed"`
+	MedianToClaim int    `json:"median_to_claim_seconds"`
+}
+
+type postingStats struct {
+	Posted   int            `json:"posted"`
+	Untimed  int            `json:"untimed"`
+	Outcomes map[string]int `json:"outcomes"`
+	Stages   []stageTimes   `json:"stages"`
+	// Reviewed tasks were approved or rejected by you at least once.
+	Reviewed   int        `json:"reviewed"`
+	Rejected   int        `json:"rejected"`
+	Rejections int        `json:"rejections"`
+	Tags       []tagTimes `json:"tags"`
+}
+
+func newStageTimes(stage string, ds []time.Duration) stageTimes {
+	s := stageTimes{Stage: stage, Tasks: len(ds)}
+	if len(ds) == 0 {
+		return s
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	var total time.Duration
+	for _, d := range ds {
+		total += d
+	}
+	s.Median = int(ds[len(ds)/2].Seconds())
+	s.Average = int((total / time.Duration(len(ds))).Seconds())
+	s.Slowest = int(ds[len(ds)-1].Seconds())
+	return s
+}
+
+func formatSeconds(n int) string {
+	return formatDuration(time.Duration(n) * time.Second)
+}
+
+// computePostingStats works out posting times from the history log, which
+// knows when tasks were posted, approved and rejected from this machine,
+// and the statuses seen since. Tasks posted elsewhere have no posting time
+// and only count towards the outcomes.
+func computePostingStats(tasks []client.TaskResponse, entries []history.Entry, seen map[string]map[string]time.Time, since time.Time) postingStats {
+	type posting struct {
+		postedAt   time.Time
+		tags       []string
+		reviewedAt time.Time
+		approvedAt time.Time
+		rejections int
+	}
+	byTask := map[string]*posting{}
+	for _, e := range entries {
+		switch e.Action {
+		case "created":
+			byTask[e.TaskID] = &posting{postedAt: e.Time, tags: e.Tags}
+		case "approved", "rejected":
+			p := byTask[e.TaskID]
+			if p == nil {
+				continue
+			}
+			if p.reviewedAt.IsZero() {
+				p.reviewedAt = e.Time
+			}
+			if e.Action == "approved" {
+				p.approvedAt = e.Time
+			} else {
+				p.rejections++
+			}
+		}
+	}
+
+	stats := postingStats{Outcomes: map[string]int{}}
+	var toClaim, toDeliver, toApprove []time.Duration
+	tagClaims := map[string][]time.Duration{}
+	tagTasks := map[string]int{}
+	for _, t := range tasks {
+		p := byTask[t.TaskID]
+		if (p == nil && !since.IsZero()) || (p != nil && p.postedAt.Before(since)) {
+			continue
+		}
+		stats.Posted++
+		stats.Outcomes[string(t.Status)]++
+		if p == nil {
+			stats.Untimed++
+			continue
+		}
+		if !p.reviewedAt.IsZero() {
+			stats.Reviewed++
+		}
+		if p.rejections > 0 {
+			stats.Rejected++
+			stats.Rejections += p.rejections
+		}
+		for _, tag := range p.tags {
+			tagTasks[tag]++
+		}
+
+		// A stage first seen after the next one was reached is a poor
+		// estimate, so later stages bound earlier ones; a task is delivered
+		// before it is first reviewed.
+		approvedAt := p.approvedAt
+		if approvedAt.IsZero() {
+			approvedAt = seen[t.TaskID][string(client.StatusApproved)]
+		}
+		deliveredAt := earliest(earliest(seen[t.TaskID][string(client.StatusDelivered)], p.reviewedAt), approvedAt)
+		claimedAt := earliest(seen[t.TaskID][string(client.StatusClaimed)], deliveredAt)
+		if !claimedAt.IsZero() {
+			d := claimedAt.Sub(p.postedAt)
+			toClaim = append(toClaim, d)
+			for _, tag := range p.tags {
+				tagClaims[tag] = append(tagClaims[tag], d)
+			}
+		}
+		if !deliveredAt.IsZero() {
+			toDeliver = append(toDeliver, deliveredAt.Sub(p.postedAt))
+		}
+		if !approvedAt.IsZero() {
+			toApprove = append(toApprove, approvedAt.Sub(p.postedAt))
+		}
+	}
+
+	stats.Stages = []stageTimes{
+		newStageTimes("claimed", toClaim),
+		newStageTimes("delivered", toDeliver),
+		newStageTimes("approved", toApprove),
+	}
+	stats.Tags = []tagTimes{}
+	for tag, n := range tagTasks {
+		claims := newStageTimes("claimed", tagClaims[tag])
+		stats.Tags = append(stats.Tags, tagTimes{Tag: tag, Tasks: n, Claimed: claims.Tasks, MedianToClaim: claims.Median})
+	}
+	// Fastest first; tags never claimed go last.
+	sort.Slice(stats.Tags, func(i, j int) bool {
+		a, b := stats.Tags[i], stats.Tags[j]
+		if (a.Claimed == 0) != (b.Claimed == 0) {
+			return b.Claimed == 0
+		}
+		if a.MedianToClaim != b.MedianToClaim {
+			return a.MedianToClaim < b.MedianToClaim
+		}
+		return a.Tag < b.Tag
+	})
+	return stats
+}
+
+// earliest returns the earlier of two times, ignoring zero ones.
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+var statsPostingCmd = &cobra.Command{
+	Use:   "posting",
+	Short: "Show how fast your posted tasks get claimed, delivered and approved",
+	Long: `Show how long your posted tasks take to get claimed, delivered and
+approved, counted from when you posted them, how often you reject
+deliveries, and which tags get your tasks claimed fastest.
+
+The server doesn't say when a task was claimed or delivered, so these come
+from this machine: posting, approving and rejecting are in the history log,
+and the times tasks were claimed and delivered are noted whenever you run
+'tasks mine' or this command. Claim times are exact for tasks seen while
+claimed; the others are when the change was first seen, so the more often
+you look, the better the numbers. Tasks posted from another machine
+only count towards the outcomes.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceStr, _ := cmd.Flags().GetString("since")
+		since, err := parseSince(sinceStr)
+		if err != nil {
+			exitErr(err)
+		}
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		tasks, err := allMyTasks(c, "poster", "")
+		if err != nil {
+			exitErr(err)
+		}
+		st, path := loadState()
+		observeStatuses(st, path, tasks)
+		entries, err := history.Read(historyPath(), time.Time{})
+		if err != nil {
+			exitErr(fmt.Errorf("read history: %w", err))
+		}
+
+		stats := computePostingStats(tasks, entries, st.Seen, since)
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, stats)
+			return
+		}
+		if stats.Posted == 0 {
+			fmt.Println("No posted tasks found.")
+			return
+		}
+
+		fmt.Printf("Posted tasks: %s", output.Number(stats.Posted))
+		var outcomes []string
+		for _, s := range client.TaskStatuses {
+			if n := stats.Outcomes[string(s)]; n > 0 {
+				outcomes = append(outcomes, fmt.Sprintf("%s %s", output.Number(n), s))
+			}
+		}
+		fmt.Printf(" (%s)\n", strings.Join(outcomes, ", "))
+		if stats.Untimed > 0 {
+			fmt.Printf("%s posted from elsewhere have no times and are left out below.\n", output.Number(stats.Untimed))
+		}
+
+		fmt.Println()
+		headers := []string{"STAGE", "TASKS", "MEDIAN", "AVERAGE", "SLOWEST"}
+		var rows [][]string
+		for _, s := range stats.Stages {
+			row := []string{s.Stage, output.Number(s.Tasks), "", "", ""}
+			if s.Tasks > 0 {
+				row[2], row[3], row[4] = formatSeconds(s.Median), formatSeconds(s.Average), formatSeconds(s.Slowest)
+			}
+			rows = append(rows, row)
+		}
+		output.Table(os.Stdout, headers, rows)
+
+		if stats.Reviewed > 0 {
+			fmt.Printf("\nRejections: %s of %s reviewed tasks rejected at least once (%.0f%%), %s rejection(s) in all\n",
+				output.Number(stats.Rejected), output.Number(stats.Reviewed),
+				float64(stats.Rejected)/float64(stats.Reviewed)*100, output.Number(stats.Rejections))
+		}
+
+		if len(stats.Tags) > 0 {
+			fmt.Println()
+			var tagRows [][]string
+			for _, t := range stats.Tags {
+				median := ""
+				if t.Claimed > 0 {
+					median = formatSeconds(t.MedianToClaim)
+				}
+				tagRows = append(tagRows, []string{t.Tag, output.Number(t.Tasks), output.Number(t.Claimed), median})
+			}
+			output.Table(os.Stdout, []string{"TAG", "TASKS", "CLAIMED", "MEDIAN TO CLAIM"}, tagRows)
+		}
+	},
+}
+
+func init() {
+	statsPostingCmd.Flags().String("since", "", "only tasks posted since then (e.g. 30d, 2025-01-31)")
+
+	statsCmd.AddCommand(statsPostingCmd)
+}
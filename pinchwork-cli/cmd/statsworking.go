@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/history"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// workTotals add up the tasks you worked on, overall or for one tag.
+type workTotals struct {
+	Tag   string `json:"tag,omitempty"`
+	Tasks int    `json:"tasks"`
+	// Reviewed counts deliveries the poster approved or rejected.
+	Reviewed int `json:"reviewed"`
+	Approved int `json:"approved"`
+	// Earned is after the platform fee, over Seconds from first claim to
+	// last delivery of the approved tasks.
+	Earned  int `json:"earned"`
+	Seconds int `json:"seconds"`
+
+	ApprovalRate   float64 `json:"approval_rate"`
+	CreditsPerHour float64 `json:"credits_per_hour"`
+}
+
+func (w workTotals) approvalRate() float64 {
+	if w.Reviewed == 0 {
+		return 0
+	}
+	return float64(w.Approved) / float64(w.Reviewed)
+}
+
+func (w workTotals) perHour() float64 {
+	if w.Seconds == 0 {
+		return 0
+	}
+	return float64(w.Earned) / (float64(w.Seconds) / 3600)
+}
+
+type workingStats struct {
+	Overall workTotals   `json:"overall"`
+	Untimed int          `json:"untimed"`
+	Tags    []workTotals `json:"tags"`
+}
+
+// computeWorkingStats works out earnings and approval rates from the history
+// log, which knows when you claimed and delivered tasks from this machine
+// and with which tags. A delivery that was followed by another one was
+// rejected; the server only says whether the last one was approved.
+func computeWorkingStats(tasks []client.TaskResponse, entries []history.Entry, fees *client.Fees, since time.Time) workingStats {
+	type work struct {
+		claimedAt   time.Time
+		deliveredAt time.Time
+		deliveries  int
+		tags        []string
+	}
+	byTask := map[string]*work{}
+	for _, e := range entries {
+		switch e.Action {
+		case "claimed":
+			if byTask[e.TaskID] == nil {
+				byTask[e.TaskID] = &work{claimedAt: e.Time, tags: e.Tags}
+			}
+		case "delivered":
+			if w := byTask[e.TaskID]; w != nil {
+				w.deliveredAt = e.Time
+				w.deliveries++
+			}
+		}
+	}
+
+	stats := workingStats{Tags: []workTotals{}}
+	byTag := map[string]*workTotals{}
+	for _, t := range tasks {
+		w := byTask[t.TaskID]
+		if w == nil {
+			if since.IsZero() {
+				stats.Untimed++
+			}
+			continue
+		}
+		if w.claimedAt.Before(since) {
+			continue
+		}
+
+		var task workTotals
+		task.Tasks = 1
+		task.Reviewed, task.Approved = reviewOutcome(t.Status, w.deliveries)
+		if t.Status == client.StatusApproved {
+			if t.CreditsCharged != nil {
+				task.Earned = *t.CreditsCharged - fees.PlatformFee(*t.CreditsCharged)
+			}
+			if !w.deliveredAt.IsZero() {
+				task.Seconds = int(w.deliveredAt.Sub(w.claimedAt).Seconds())
+			}
+		}
+
+		stats.Overall.add(task)
+		for _, tag := range w.tags {
+			if byTag[tag] == nil {
+				byTag[tag] = &workTotals{Tag: tag}
+			}
+			byTag[tag].add(task)
+		}
+	}
+	stats.Overall.ApprovalRate, stats.Overall.CreditsPerHour = stats.Overall.approvalRate(), stats.Overall.perHour()
+	for _, t := range byTag {
+		t.ApprovalRate, t.CreditsPerHour = t.approvalRate(), t.perHour()
+		stats.Tags = append(stats.Tags, *t)
+	}
+	// Most profitable first.
+	sort.Slice(stats.Tags, func(i, j int) bool {
+		a, b := stats.Tags[i], stats.Tags[j]
+		if a.CreditsPerHour != b.CreditsPerHour {
+			return a.CreditsPerHour > b.CreditsPerHour
+		}
+		if a.Earned != b.Earned {
+			return a.Earned > b.Earned
+		}
+		return a.Tag < b.Tag
+	})
+	return stats
+}
+
+// reviewOutcome counts the reviews of a task you delivered deliveries
+// times: every delivery but a pending last one was approved or rejected,
+// and only an approved task's last one was approved.
+func reviewOutcome(status client.TaskStatus, deliveries int) (reviewed, approved int) {
+	reviewed = deliveries
+	if status == client.StatusDelivered && reviewed > 0 {
+		reviewed-- // still waiting for review
+	}
+	if status == client.StatusApproved {
+		approved = 1
+		reviewed = max(reviewed, 1)
+	}
+	return reviewed, approved
+}
+
+func (w *workTotals) add(o workTotals) {
+	w.Tasks += o.Tasks
+	w.Reviewed += o.Reviewed
+	w.Approved += o.Approved
+	w.Earned += o.Earned
+	w.Seconds += o.Seconds
+}
+
+func formatPerHour(w workTotals) string {
+	if w.Seconds == 0 {
+		return ""
+	}
+	return output.Number(int(w.perHour() + 0.5))
+}
+
+func formatRate(w workTotals) string {
+	if w.Reviewed == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%%", w.approvalRate()*100)
+}
+
+var statsWorkingCmd = &cobra.Command{
+	Use:   "working",
+	Short: "Show your earnings per hour and approval rate per tag",
+	Long: `Show what your work as a worker earns per hour and how often it is
+approved, overall and per task tag, to see which skills pay best.
+
+Hours are from claiming a task to delivering it for the last time, so
+redoing rejected work counts against the rate. Earnings are after the
+platform fee. The server doesn't keep claim or delivery times, so only
+tasks claimed and delivered from this machine are timed, and their tags
+are those the task had when you claimed it.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceStr, _ := cmd.Flags().GetString("since")
+		since, err := parseSince(sinceStr)
+		if err != nil {
+			exitErr(err)
+		}
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		tasks, err := allMyTasks(c, "worker", "")
+		if err != nil {
+			exitErr(err)
+		}
+		entries, err := history.Read(historyPath(), time.Time{})
+		if err != nil {
+			exitErr(fmt.Errorf("read history: %w", err))
+		}
+
+		stats := computeWorkingStats(tasks, entries, serverFees(c), since)
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, stats)
+			return
+		}
+		o := stats.Overall
+		if o.Tasks == 0 {
+			fmt.Println("No claimed tasks found in this machine's history.")
+			return
+		}
+
+		fmt.Printf("Tasks worked on: %s, %s approved\n", output.Number(o.Tasks), output.Number(o.Approved))
+		fmt.Printf("Earned:          %s credits after fees", output.Number(o.Earned))
+		if o.Seconds > 0 {
+			fmt.Printf(" in %s of work, %s credits/hour", formatSeconds(o.Seconds), formatPerHour(o))
+		}
+		fmt.Println()
+		if o.Reviewed > 0 {
+			fmt.Printf("Approval rate:   %s (%s of %s reviewed deliveries)\n", formatRate(o), output.Number(o.Approved), output.Number(o.Reviewed))
+		}
+		if stats.Untimed > 0 {
+			fmt.Printf("%s tasks claimed from elsewhere are left out.\n", output.Number(stats.Untimed))
+		}
+
+		if len(stats.Tags) == 0 {
+			return
+		}
+		fmt.Println()
+		var rows [][]string
+		for _, t := range stats.Tags {
+			rows = append(rows, []string{t.Tag, output.Number(t.Tasks), output.Number(t.Approved), formatRate(t), output.Number(t.Earned), formatPerHour(t)})
+		}
+		output.Table(os.Stdout, []string{"TAG", "TASKS", "APPROVED", "APPROVAL RATE", "EARNED", "CREDITS/HOUR"}, rows)
+		if best := stats.Tags[0]; best.Seconds > 0 {
+			fmt.Printf("\nMost profitable: %s, at %s credits/hour\n", best.Tag, formatPerHour(best))
+		}
+	},
+}
+
+func init() {
+	statsWorkingCmd.Flags().String("since", "", "only tasks claimed since then (e.g. 30d, 2025-01-31)")
+
+	statsCmd.AddCommand(statsWorkingCmd)
+}
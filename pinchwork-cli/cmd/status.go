@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+type agentStatus struct {
+	AgentID  string `json:"agent_id"`
+	Name     string `json:"name"`
+	Credits  int    `json:"credits"`
+	Escrowed int    `json:"escrowed"`
+	// Open tasks are posted and waiting for a worker, Working ones are
+	// claimed by this agent, and ToReview ones are deliveries to approve.
+	Open     int `json:"open"`
+	Working  int `json:"working"`
+	ToReview int `json:"to_review"`
+}
+
+func fetchStatus(c *client.Client) (*agentStatus, error) {
+	me, err := c.GetMe()
+	if err != nil {
+		return nil, err
+	}
+	credits, err := c.GetCredits()
+	if err != nil {
+		return nil, err
+	}
+	s := &agentStatus{AgentID: me.ID, Name: me.Name, Credits: credits.Balance, Escrowed: credits.Escrowed}
+	for _, q := range []struct {
+		role   string
+		status client.TaskStatus
+		n      *int
+	}{
+		{"poster", client.StatusOpen, &s.Open},
+		{"worker", client.StatusClaimed, &s.Working},
+		{"poster", client.StatusDelivered, &s.ToReview},
+	} {
+		resp, err := c.ListMyTasks(q.role, q.status, 1, 0)
+		if err != nil {
+			return nil, err
+		}
+		*q.n = resp.Total
+	}
+	return s, nil
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show your balance and the tasks waiting on you",
+	Long: `Show your balance and escrow, plus how many of your tasks are open, being
+worked on by you, or delivered and waiting for your review.
+
+With --all-profiles, show one row per configured profile.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if allProfiles {
+			statusAllProfiles()
+			return
+		}
+
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+		s, err := fetchStatus(c)
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, s)
+			return
+		}
+
+		fmt.Printf("Agent:      %s (%s)\n", s.AgentID, s.Name)
+		credits := fmt.Sprintf("%s (%s in escrow)", output.Number(s.Credits), output.Number(s.Escrowed))
+		if sandboxActive {
+			credits += ", sandbox play credits"
+		}
+		fmt.Printf("Credits:    %s\n", credits)
+		fmt.Printf("Open:       %d posted, waiting for a worker\n", s.Open)
+		fmt.Printf("Working:    %d claimed, to deliver\n", s.Working)
+		fmt.Printf("To review:  %d delivered, to approve or reject\n", s.ToReview)
+	},
+}
+
+func statusAllProfiles() {
+	results := fanOutProfiles(fetchStatus)
+
+	if outputFmt == "json" {
+		type row struct {
+			Profile string `json:"profile"`
+			*agentStatus
+			Error string `json:"error,omitempty"`
+		}
+		rows := make([]row, 0, len(results))
+		for _, r := range results {
+			rows = append(rows, row{Profile: r.Profile, agentStatus: r.Value, Error: errString(r.Err)})
+		}
+		output.JSON(os.Stdout, rows)
+		reportProfileErrors(results)
+		return
+	}
+
+	headers := []string{"PROFILE", "AGENT", "CREDITS", "ESCROWED", "OPEN", "WORKING", "TO REVIEW"}
+	var rows [][]string
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		s := r.Value
+		rows = append(rows, []string{
+			r.Profile,
+			s.AgentID,
+			strconv.Itoa(s.Credits),
+			strconv.Itoa(s.Escrowed),
+			strconv.Itoa(s.Open),
+			strconv.Itoa(s.Working),
+			strconv.Itoa(s.ToReview),
+		})
+	}
+	if len(rows) > 0 {
+		output.Table(os.Stdout, headers, rows)
+	}
+	reportProfileErrors(results)
+}
+
+func init() {
+	supportAllProfiles(statusCmd)
+	rootCmd.AddCommand(statusCmd)
+}
@@ -1,12 +1,20 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/jsonschema"
 	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/secrets"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/signing"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/state"
 	"github.com/spf13/cobra"
 )
 
@@ -19,17 +27,49 @@ var tasksCmd = &cobra.Command{
 var tasksListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available tasks",
+	Long: `List available tasks, with signals for how risky they are to take on:
+the poster's reputation, how often the poster approved your deliveries
+(from your past work for them), and how often the task was already
+rejected. RISK combines them into a score from 0 to 1, mostly driven by
+rejections; --max-risk skips tasks above it.
+
+With --servers, lists the tasks on the servers of several profiles, such as
+the hosted marketplace and a self-hosted one, merged newest first with a
+SERVER column naming the profile.`,
+	Example: `  pinchwork tasks list --tags go --max-risk 0.3
+  pinchwork tasks list --servers prod,staging`,
 	Run: func(cmd *cobra.Command, args []string) {
+		applySavedSearch(cmd)
+		tags, _ := cmd.Flags().GetString("tags")
+		search, _ := cmd.Flags().GetString("search")
+		limit, _ := cmd.Flags().GetInt("limit")
+		preview, _ := cmd.Flags().GetInt("preview")
+		watch, _ := cmd.Flags().GetBool("watch")
+		filter := taskFilterFromFlags(cmd)
+
+		if servers, _ := cmd.Flags().GetStringSlice("servers"); len(servers) > 0 {
+			if watch {
+				exitErr(fmt.Errorf("--servers can't be combined with --watch"))
+			}
+			if profile != "" || sandboxFlag || serverFlag != "" || keyFlag != "" || allProfiles {
+				exitErr(fmt.Errorf("--servers can't be combined with --profile, --as, --sandbox, --server, --key or --all-profiles"))
+			}
+			tasksListServers(servers, tags, search, limit, filter, preview)
+			return
+		}
+
 		c, err := newClientRequired()
 		if err != nil {
 			exitErr(err)
 		}
 
-		tags, _ := cmd.Flags().GetString("tags")
-		search, _ := cmd.Flags().GetString("search")
-		limit, _ := cmd.Flags().GetInt("limit")
+		if watch {
+			interval, _ := cmd.Flags().GetDuration("interval")
+			watchAvailable(c, tags, search, limit, filter, interval)
+			return
+		}
 
-		resp, err := c.ListAvailableTasks(tags, search, limit, 0)
+		resp, err := listAvailable(c, tags, search, limit, filter)
 		if err != nil {
 			exitErr(err)
 		}
@@ -38,80 +78,423 @@ var tasksListCmd = &cobra.Command{
 			output.JSON(os.Stdout, resp)
 			return
 		}
+		if quiet {
+			for _, t := range resp.Tasks {
+				printIDs(t.TaskID)
+			}
+			return
+		}
 
 		if len(resp.Tasks) == 0 {
 			fmt.Println("No tasks available.")
 			return
 		}
 
-		headers := []string{"ID", "NEED", "CREDITS", "TAGS", "POSTER"}
-		var rows [][]string
-		for _, t := range resp.Tasks {
-			tagStr := ""
-			if len(t.Tags) > 0 {
-				tagStr = strings.Join(t.Tags, ",")
-			}
-			rows = append(rows, []string{
-				t.TaskID,
-				output.Truncate(t.Need, 50),
-				fmt.Sprintf("%d", t.MaxCredits),
-				tagStr,
-				t.PosterID,
-			})
-		}
-		output.Table(os.Stdout, headers, rows)
+		printAvailableTable(resp.Tasks, nil, preview, tableApprovals(c, filter), nil)
 		fmt.Printf("\n%d task(s) available\n", resp.Total)
 	},
 }
 
+// serverTask is an available task listed with --servers, with the profile
+// whose server it is on.
+type serverTask struct {
+	Server string `json:"server"`
+	client.TaskAvailableItem
+}
+
+// tasksListServers lists the available tasks on the servers of the named
+// profiles, newest first.
+func tasksListServers(names []string, tags, search string, limit int, filter taskFilter, preview int) {
+	cfg, err := loadConfig()
+	if err != nil {
+		exitErr(fmt.Errorf("load config: %w", err))
+	}
+	seen := map[string]string{}
+	for _, name := range names {
+		server := profileClient(cfg.Profiles[name]).BaseURL
+		if other, ok := seen[server]; ok && other != name {
+			exitErr(fmt.Errorf("--servers: profiles %s and %s both use %s", other, name, server))
+		}
+		seen[server] = name
+	}
+	// Approval rates come from this profile's history, which says nothing
+	// about posters on the other servers.
+	filter.approvals = map[string]float64{}
+
+	results := fanOutNamedProfiles(names, func(c *client.Client) (*client.TaskAvailableResponse, error) {
+		return listAvailable(c, tags, search, limit, filter)
+	})
+	var tasks []serverTask
+	total := 0
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		for _, t := range r.Value.Tasks {
+			tasks = append(tasks, serverTask{Server: r.Profile, TaskAvailableItem: t})
+		}
+		total += r.Value.Total
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].CreatedAt.After(tasks[j].CreatedAt.Time) })
+	if limit > 0 && len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+
+	if outputFmt == "json" {
+		if tasks == nil {
+			tasks = []serverTask{}
+		}
+		output.JSON(os.Stdout, struct {
+			Tasks []serverTask `json:"tasks"`
+			Total int          `json:"total"`
+		}{tasks, total})
+		reportProfileErrors(results)
+		return
+	}
+	if quiet {
+		for _, t := range tasks {
+			printIDs(t.TaskID)
+		}
+		reportProfileErrors(results)
+		return
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("No tasks available.")
+	} else {
+		items := make([]client.TaskAvailableItem, len(tasks))
+		servers := map[string]string{}
+		for i, t := range tasks {
+			items[i] = t.TaskAvailableItem
+			servers[t.TaskID] = t.Server
+		}
+		printAvailableTable(items, nil, preview, nil, servers)
+		fmt.Printf("\n%d task(s) available\n", total)
+	}
+	reportProfileErrors(results)
+}
+
+// printAvailableTable prints available tasks, marking those in isNew with a
+// "*" when isNew is non-nil. With preview > 0 it adds a context size column
+// and the first preview lines of each context under its row. approvals are
+// the poster approval rates from posterApprovals, for the risk columns.
+// servers, when non-nil, fills a leading SERVER column by task ID.
+func printAvailableTable(tasks []client.TaskAvailableItem, isNew map[string]bool, preview int, approvals map[string]float64, servers map[string]string) {
+	headers := []string{"ID", "NEED", "CREDITS", "TAGS", "POSTER", "REPUTATION", "APPROVAL", "REJECTIONS", "RISK", "AGE"}
+	if preview > 0 {
+		headers = append(headers, "SIZE")
+	}
+	if servers != nil {
+		headers = append([]string{"SERVER"}, headers...)
+	}
+	// These are only shown when asked for with --columns.
+	defaults := headers
+	headers = append(append([]string{}, headers...), "DEADLINE", "MATCHED", "CONTEXT")
+	needCol := 1
+	if servers != nil {
+		needCol++
+	}
+	if isNew != nil {
+		headers = append([]string{""}, headers...)
+		needCol++
+	}
+	var rows [][]string
+	for _, t := range tasks {
+		tagStr := ""
+		if len(t.Tags) > 0 {
+			tagStr = strings.Join(t.Tags, ",")
+		}
+		row := []string{
+			t.TaskID,
+			output.Cell(t.Need, 50),
+			output.Number(t.MaxCredits),
+			tagStr,
+			t.PosterID,
+		}
+		reputation, approval, matched := "", "", ""
+		if t.PosterReputation != nil {
+			reputation = fmt.Sprintf("%.2f", *t.PosterReputation)
+		}
+		if rate, ok := approvals[t.PosterID]; ok {
+			approval = fmt.Sprintf("%.0f%%", rate*100)
+		}
+		row = append(row, reputation, approval, fmt.Sprintf("%d", t.RejectionCount), riskCell(taskRisk(t, approvals)), formatAge(t.CreatedAt))
+		if preview > 0 {
+			row = append(row, output.Size(len(t.Context)))
+		}
+		if t.IsMatched {
+			matched = "yes"
+			if t.MatchRank != nil {
+				matched = fmt.Sprintf("#%d", *t.MatchRank+1)
+			}
+		}
+		row = append(row, formatDeadline(t.Deadline), matched,
+			output.Truncate(strings.Join(strings.Fields(t.Context), " "), 200))
+		if servers != nil {
+			row = append([]string{servers[t.TaskID]}, row...)
+		}
+		if isNew != nil {
+			mark := ""
+			if isNew[t.TaskID] {
+				mark = "*"
+			}
+			row = append([]string{mark}, row...)
+		}
+		rows = append(rows, row)
+
+		for _, line := range contextPreview(t.Context, preview) {
+			// Preview lines sit under NEED; the row keeps every column so
+			// the table stays aligned.
+			pr := make([]string, len(headers))
+			pr[needCol] = "  " + output.Truncate(line, 48)
+			rows = append(rows, pr)
+		}
+	}
+	output.TableDefault(os.Stdout, headers, rows, defaults)
+}
+
 var tasksMineCmd = &cobra.Command{
 	Use:   "mine",
 	Short: "List your tasks (posted and claimed)",
+	Long: `List your tasks (posted and claimed).
+
+With --label, list only the tasks you gave that label with 'tasks label'.
+With --all-profiles, list the tasks of every configured profile in one table.
+With --summary, count all your tasks by role and status instead, with the
+credits at stake and how many are due within a day, such as "3 awaiting
+your review" or "2 claimed by you, 1 due within 24h".`,
 	Run: func(cmd *cobra.Command, args []string) {
+		role, _ := cmd.Flags().GetString("role")
+		statusFlag, _ := cmd.Flags().GetString("status")
+		limit, _ := cmd.Flags().GetInt("limit")
+		label, _ := cmd.Flags().GetString("label")
+		summary, _ := cmd.Flags().GetBool("summary")
+		var status client.TaskStatus
+		if statusFlag != "" {
+			var err error
+			if status, err = client.ParseTaskStatus(statusFlag); err != nil {
+				exitErr(fmt.Errorf("--status: %w", err))
+			}
+		}
+
+		if summary && quiet {
+			exitErr(fmt.Errorf("--summary can't be combined with --quiet"))
+		}
+		if allProfiles {
+			if label != "" {
+				exitErr(fmt.Errorf("--label can't be combined with --all-profiles: labels are kept per profile"))
+			}
+			if summary {
+				exitErr(fmt.Errorf("--summary can't be combined with --all-profiles"))
+			}
+			tasksMineAllProfiles(role, status, limit)
+			return
+		}
+
 		c, err := newClientRequired()
 		if err != nil {
 			exitErr(err)
 		}
+		st, stPath := loadState()
+
+		if summary {
+			byRole := map[string][]client.TaskResponse{}
+			for _, r := range []string{"poster", "worker"} {
+				if role != "" && role != r {
+					continue
+				}
+				tasks, err := allMyTasks(c, r, status)
+				if err != nil {
+					exitErr(err)
+				}
+				observeStatuses(st, stPath, tasks)
+				for _, t := range tasks {
+					if label == "" || st.HasLabel(t.TaskID, label) {
+						byRole[r] = append(byRole[r], t)
+					}
+				}
+			}
+			printMineSummary(summarizeMine(byRole))
+			return
+		}
 
-		role, _ := cmd.Flags().GetString("role")
-		status, _ := cmd.Flags().GetString("status")
-		limit, _ := cmd.Flags().GetInt("limit")
-
-		resp, err := c.ListMyTasks(role, status, limit, 0)
-		if err != nil {
+		var resp *client.MyTasksResponse
+		if label != "" {
+			// Labels only exist here, so filter every task rather than one page.
+			tasks, err := allMyTasks(c, role, status)
+			if err != nil {
+				exitErr(err)
+			}
+			resp = &client.MyTasksResponse{Tasks: []client.TaskResponse{}}
+			for _, t := range tasks {
+				if st.HasLabel(t.TaskID, label) {
+					resp.Tasks = append(resp.Tasks, t)
+				}
+			}
+			resp.Total = len(resp.Tasks)
+			if limit > 0 && len(resp.Tasks) > limit {
+				resp.Tasks = resp.Tasks[:limit]
+			}
+		} else if resp, err = c.ListMyTasks(role, status, limit, 0); err != nil {
 			exitErr(err)
 		}
+		observeStatuses(st, stPath, resp.Tasks)
 
 		if outputFmt == "json" {
 			output.JSON(os.Stdout, resp)
 			return
 		}
+		if quiet {
+			for _, t := range resp.Tasks {
+				printIDs(t.TaskID)
+			}
+			return
+		}
 
 		if len(resp.Tasks) == 0 {
 			fmt.Println("No tasks found.")
 			return
 		}
 
-		headers := []string{"ID", "STATUS", "NEED", "POSTER", "WORKER"}
+		labeled := false
+		for _, t := range resp.Tasks {
+			if len(st.Labels[t.TaskID]) > 0 {
+				labeled = true
+			}
+		}
+		headers := append(append([]string{}, mineHeaders...), mineExtraHeaders...)
+		defaults := mineHeaders
+		if labeled {
+			headers = append(headers, "LABELS")
+			defaults = append(append([]string{}, mineHeaders...), "LABELS")
+		}
 		var rows [][]string
 		for _, t := range resp.Tasks {
-			rows = append(rows, []string{
-				t.TaskID,
-				t.Status,
-				output.Truncate(t.Need, 50),
-				t.PosterID,
-				t.WorkerID,
-			})
-		}
-		output.Table(os.Stdout, headers, rows)
+			row := append(mineRow(t), mineExtraRow(t)...)
+			if labeled {
+				row = append(row, strings.Join(st.Labels[t.TaskID], ","))
+			}
+			rows = append(rows, row)
+		}
+		output.TableDefault(os.Stdout, headers, rows, defaults)
 		fmt.Printf("\n%d task(s)\n", resp.Total)
 	},
 }
 
+// statusOutlook describes where a task in status s can go next.
+func statusOutlook(s client.TaskStatus) string {
+	if s.IsTerminal() {
+		return " (final)"
+	}
+	next := s.Next()
+	if len(next) == 0 {
+		return ""
+	}
+	names := make([]string, len(next))
+	for i, n := range next {
+		names[i] = string(n)
+	}
+	return " (next: " + strings.Join(names, ", ") + ")"
+}
+
+var mineHeaders = []string{"ID", "STATUS", "NEED", "POSTER", "WORKER", "DUE"}
+
+func mineRow(t client.TaskResponse) []string {
+	due := ""
+	switch t.Status {
+	case client.StatusClaimed:
+		due = formatDeadline(t.ClaimDeadline)
+	case client.StatusOpen:
+		due = formatDeadline(t.Deadline)
+	}
+	return []string{
+		t.TaskID,
+		output.Status(string(t.Status)),
+		output.Cell(t.Need, 50),
+		t.PosterID,
+		t.WorkerID,
+		due,
+	}
+}
+
+// mineExtraHeaders are the tasks mine columns shown only with --columns.
+var mineExtraHeaders = []string{"DEADLINE", "CLAIM DEADLINE", "CREDITS", "VISIBILITY"}
+
+func mineExtraRow(t client.TaskResponse) []string {
+	credits := ""
+	if t.CreditsCharged != nil {
+		credits = output.Number(*t.CreditsCharged)
+	}
+	return []string{formatDeadline(t.Deadline), formatDeadline(t.ClaimDeadline), credits, t.Visibility}
+}
+
+func tasksMineAllProfiles(role string, status client.TaskStatus, limit int) {
+	results := fanOutProfiles(func(c *client.Client) (*client.MyTasksResponse, error) {
+		return c.ListMyTasks(role, status, limit, 0)
+	})
+
+	if outputFmt == "json" {
+		type row struct {
+			Profile string                `json:"profile"`
+			Tasks   []client.TaskResponse `json:"tasks"`
+			Total   int                   `json:"total"`
+			Error   string                `json:"error,omitempty"`
+		}
+		rows := make([]row, 0, len(results))
+		for _, r := range results {
+			out := row{Profile: r.Profile, Error: errString(r.Err)}
+			if r.Value != nil {
+				out.Tasks, out.Total = r.Value.Tasks, r.Value.Total
+			}
+			rows = append(rows, out)
+		}
+		output.JSON(os.Stdout, rows)
+		reportProfileErrors(results)
+		return
+	}
+
+	if quiet {
+		for _, r := range results {
+			if r.Err == nil {
+				for _, t := range r.Value.Tasks {
+					printIDs(t.TaskID)
+				}
+			}
+		}
+		reportProfileErrors(results)
+		return
+	}
+
+	var rows [][]string
+	total := 0
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		for _, t := range r.Value.Tasks {
+			rows = append(rows, append(append([]string{r.Profile}, mineRow(t)...), mineExtraRow(t)...))
+		}
+		total += r.Value.Total
+	}
+	if len(rows) == 0 {
+		fmt.Println("No tasks found.")
+	} else {
+		headers := append(append([]string{"PROFILE"}, mineHeaders...), mineExtraHeaders...)
+		output.TableDefault(os.Stdout, headers, rows, append([]string{"PROFILE"}, mineHeaders...))
+		fmt.Printf("\n%d task(s)\n", total)
+	}
+	reportProfileErrors(results)
+}
+
 var tasksCreateCmd = &cobra.Command{
 	Use:   "create NEED",
 	Short: "Create a new task",
-	Args:  cobra.MinimumNArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		c, err := newClientRequired()
 		if err != nil {
@@ -119,26 +502,108 @@ var tasksCreateCmd = &cobra.Command{
 		}
 
 		need := strings.Join(args, " ")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		if stdinContext(cmd) && interactive {
+			exitErr(fmt.Errorf("--context - reads stdin, which --interactive needs for its questions; use --context-file"))
+		}
+		if interactive {
+			need = runCreateWizard(c, cmd, need)
+		}
 		credits, _ := cmd.Flags().GetInt("credits")
 		tags, _ := cmd.Flags().GetString("tags")
 		context, _ := cmd.Flags().GetString("context")
 		contextFile, _ := cmd.Flags().GetString("context-file")
-		deadline, _ := cmd.Flags().GetInt("deadline")
-		reviewTimeout, _ := cmd.Flags().GetInt("review-timeout")
-		claimTimeout, _ := cmd.Flags().GetInt("claim-timeout")
+		deadline := minutesFlag(cmd, "deadline")
+		reviewTimeout := minutesFlag(cmd, "review-timeout")
+		claimTimeout := minutesFlag(cmd, "claim-timeout")
+		resultSchema, _ := cmd.Flags().GetString("result-schema")
+		parent, _ := cmd.Flags().GetString("parent")
+		visibility, _ := cmd.Flags().GetString("visibility")
+		allow, _ := cmd.Flags().GetString("allow")
+		switch visibility {
+		case client.VisibilityPublic:
+			if allow != "" {
+				exitErr(fmt.Errorf("--allow needs --visibility private"))
+			}
+			visibility = ""
+		case client.VisibilityPrivate:
+			if allow == "" {
+				exitErr(fmt.Errorf("--visibility private needs --allow with the agents who may claim it"))
+			}
+		default:
+			exitErr(fmt.Errorf("--visibility must be public or private"))
+		}
+		mode, _ := cmd.Flags().GetString("mode")
+		biddingWindow := minutesFlag(cmd, "bidding-window")
+		switch mode {
+		case client.ModePickup:
+			if biddingWindow > 0 {
+				exitErr(fmt.Errorf("--bidding-window needs --mode bidding"))
+			}
+			mode = ""
+		case client.ModeBidding:
+		default:
+			exitErr(fmt.Errorf("--mode must be pickup or bidding"))
+		}
 
-		if contextFile != "" {
+		if stdinContext(cmd) {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				exitErr(fmt.Errorf("read context from stdin: %w", err))
+			}
+			context = string(data)
+		} else if contextFile != "" {
 			data, err := os.ReadFile(contextFile)
 			if err != nil {
 				exitErr(fmt.Errorf("read context file: %w", err))
 			}
 			context = string(data)
 		}
+		if contextURL, _ := cmd.Flags().GetString("context-url"); contextURL != "" {
+			fetched, err := fetchContextURL(contextURL)
+			if err != nil {
+				exitErr(err)
+			}
+			section := fmt.Sprintf("Source: %s\n\n%s", contextURL, fetched)
+			if strings.TrimSpace(context) != "" {
+				section = strings.TrimRight(context, "\n") + "\n\n" + section
+			}
+			context = section
+		}
+		if source, diff, err := diffFromFlags(cmd); err != nil {
+			exitErr(err)
+		} else if diff != "" {
+			context = diffContext(context, source, diff)
+			tags = mergeTags(tags, diffTags(diff))
+		}
+		var schema []byte
+		if resultSchema != "" {
+			data, err := os.ReadFile(resultSchema)
+			if err != nil {
+				exitErr(fmt.Errorf("read result schema: %w", err))
+			}
+			if _, err := jsonschema.Parse(data); err != nil {
+				exitErr(err)
+			}
+			if _, err := client.EmbedResultSchema("", data); err != nil {
+				exitErr(fmt.Errorf("result schema: %w", err))
+			}
+			schema = data
+		}
+		withMarkers := func(context string) string {
+			if schema != nil {
+				context, _ = client.EmbedResultSchema(context, schema)
+			}
+			if parent != "" {
+				context = client.EmbedParent(context, parent)
+			}
+			return context
+		}
 
 		req := client.TaskCreateRequest{
 			Need:       need,
 			MaxCredits: credits,
-			Context:    context,
+			Context:    withMarkers(context),
 		}
 		if tags != "" {
 			req.Tags = strings.Split(tags, ",")
@@ -152,21 +617,158 @@ var tasksCreateCmd = &cobra.Command{
 		if claimTimeout > 0 {
 			req.ClaimTimeoutMinutes = claimTimeout
 		}
+		req.Mode = mode
+		req.BiddingWindowMinutes = biddingWindow
+		req.Visibility = visibility
+		req.OrgID, _ = cmd.Flags().GetString("org")
+		for _, a := range strings.Split(allow, ",") {
+			if a = strings.TrimSpace(a); a != "" {
+				req.AllowedAgents = append(req.AllowedAgents, a)
+			}
+		}
+
+		if allow, _ := cmd.Flags().GetBool("allow-secrets"); !allow {
+			if err := checkSecrets("need", secrets.ScanString(need)); err != nil {
+				exitErr(err)
+			}
+			if err := checkSecrets("context", secrets.ScanString(req.Context)); err != nil {
+				exitErr(err)
+			}
+		}
+		copies, _ := cmd.Flags().GetInt("copies")
+		if copies < 1 || copies > maxCopies {
+			exitErr(fmt.Errorf("--copies must be between 1 and %d", maxCopies))
+		}
+		var chunks []string
+		if chunk, _ := cmd.Flags().GetBool("chunk"); chunk {
+			if copies > 1 {
+				exitErr(fmt.Errorf("--chunk can't be combined with --copies"))
+			}
+			// Workers only see the first part until they assemble the rest,
+			// so the result schema and parent go before the context.
+			lead := withMarkers("")
+			if lead != "" && context != "" {
+				context = lead + "\n" + context
+			} else {
+				context += lead
+			}
+			parts := client.ChunkContext(context, serverLimits(c))
+			if !strings.Contains(parts[0], lead) {
+				exitErr(fmt.Errorf("the result schema is too long for --chunk"))
+			}
+			req.Context, chunks = parts[0], parts[1:]
+		}
+		if noPreflight, _ := cmd.Flags().GetBool("no-preflight"); !noPreflight {
+			if err := serverLimits(c).ValidateTaskCreate(req); err != nil {
+				exitErr(err)
+			}
+		}
+		if err := requireCreateSupport(c, req); err != nil {
+			exitErr(err)
+		}
+		showFees, _ := cmd.Flags().GetBool("show-fees")
+		if showFees {
+			printFees(serverFees(c), req.MaxCredits)
+			if copies > 1 {
+				fmt.Fprintf(os.Stderr, "Each of the %d copies escrows its credits: %d in total.\n", copies, copies*req.MaxCredits)
+			}
+		}
+		if interactive && !confirmCreate(req) {
+			exitErr(fmt.Errorf("aborted"))
+		}
+		if showFees && !interactive && !confirm(cmd, "Post this task?") {
+			exitErr(fmt.Errorf("aborted"))
+		}
+		if limit, total := confirmCreditsThreshold(), copies*req.MaxCredits; limit > 0 && total > limit && !showFees && !interactive {
+			question := fmt.Sprintf("Post this task for %s credits?", output.Number(total))
+			if copies > 1 {
+				question = fmt.Sprintf("Post %d copies of this task for %s credits in total?", copies, output.Number(total))
+			}
+			if !confirm(cmd, question) {
+				exitErr(fmt.Errorf("aborted"))
+			}
+		}
 
+		if copies > 1 {
+			createCopies(c, req, copies)
+			return
+		}
 		resp, err := c.CreateTask(req)
 		if err != nil {
 			exitErr(err)
 		}
+		if err := rejectDropped(c, req, resp); err != nil {
+			exitErr(err)
+		}
+		resp.WebURL = c.TaskWebURL(resp.TaskID)
+		recordCreated(resp.TaskID, &req.MaxCredits, need, req.Tags)
+		if len(chunks) > 0 {
+			st, path := loadState()
+			if st.Chunks == nil {
+				st.Chunks = map[string][]string{}
+			}
+			st.Chunks[resp.TaskID] = chunks
+			saveState(st, path)
+		}
 
 		if outputFmt == "json" {
 			output.JSON(os.Stdout, resp)
 			return
 		}
+		if quiet {
+			printIDs(resp.TaskID)
+			return
+		}
 
 		fmt.Printf("Created task %s (status: %s)\n", resp.TaskID, resp.Status)
+		fmt.Printf("View it at %s\n", resp.WebURL)
+		if len(chunks) > 0 {
+			fmt.Printf("The context is in %d parts; the first is posted. Send the rest once the task is claimed with 'pinchwork tasks send-chunks %s --wait'.\n", len(chunks)+1, resp.TaskID)
+		}
+		if resp.Mode == client.ModeBidding {
+			fmt.Printf("Taking bids; see them with 'pinchwork tasks bids %s' and pick one with 'pinchwork tasks award'.\n", resp.TaskID)
+		}
 	},
 }
 
+// rejectDropped cancels a task the server posted without a setting it
+// doesn't know, since the task would then be open to agents the poster
+// didn't pick, go to the first taker instead of the best bid, or be paid
+// from their own credits instead of the org pool. pydantic drops unknown
+// fields without an error; requireCreateSupport refuses what it can before
+// posting, and this catches a server whose spec promises more than it does.
+func rejectDropped(c *client.Client, req client.TaskCreateRequest, resp *client.TaskCreateResponse) error {
+	var dropped string
+	switch {
+	case req.Visibility == client.VisibilityPrivate && resp.Visibility != client.VisibilityPrivate:
+		dropped = "private tasks (--visibility private)"
+	case req.Mode == client.ModeBidding && resp.Mode != client.ModeBidding:
+		dropped = "bidding (--mode bidding)"
+	case req.OrgID != "" && resp.OrgID != req.OrgID:
+		dropped = "org boards (--org)"
+	default:
+		return nil
+	}
+	if _, err := c.CancelTask(resp.TaskID); err != nil {
+		return fmt.Errorf("this server doesn't support %s, and cancelling task %s failed: %w", dropped, resp.TaskID, err)
+	}
+	return fmt.Errorf("this server doesn't support %s; task %s was cancelled and its credits refunded", dropped, resp.TaskID)
+}
+
+// streamTimeout allows a streamed delivery of size bytes the client's usual
+// timeout plus the time to send it over a slow link, at 32 KiB/s.
+func streamTimeout(c *client.Client, size int64) time.Duration {
+	return c.HTTPClient.Timeout + time.Duration(size>>15)*time.Second
+}
+
+// stdinContext reports whether tasks create should read the context from
+// stdin.
+func stdinContext(cmd *cobra.Command) bool {
+	context, _ := cmd.Flags().GetString("context")
+	contextFile, _ := cmd.Flags().GetString("context-file")
+	return context == "-" || contextFile == "-"
+}
+
 var tasksShowCmd = &cobra.Command{
 	Use:   "show TASK_ID",
 	Short: "Show task details",
@@ -181,17 +783,33 @@ var tasksShowCmd = &cobra.Command{
 		if err != nil {
 			exitErr(err)
 		}
+		resp.WebURL = c.TaskWebURL(resp.TaskID)
+
+		var notes []state.Note
+		showNotes, _ := cmd.Flags().GetBool("notes")
+		if showNotes {
+			st, _ := loadState()
+			notes = st.Notes[resp.TaskID]
+		}
 
 		if outputFmt == "json" {
+			if showNotes {
+				output.JSON(os.Stdout, struct {
+					*client.TaskResponse
+					Notes []state.Note `json:"notes"`
+				}{resp, notes})
+				return
+			}
 			output.JSON(os.Stdout, resp)
 			return
 		}
 
 		fmt.Printf("Task:     %s\n", resp.TaskID)
-		fmt.Printf("Status:   %s\n", resp.Status)
+		fmt.Printf("Status:   %s%s\n", resp.Status, statusOutlook(resp.Status))
 		fmt.Printf("Need:     %s\n", resp.Need)
-		if resp.Context != "" {
-			fmt.Printf("Context:  %s\n", output.Truncate(resp.Context, 200))
+		schema, context := client.ExtractResultSchema(resp.Context)
+		if context != "" {
+			fmt.Printf("Context:  %s\n", output.Truncate(context, 200))
 		}
 		if resp.PosterID != "" {
 			fmt.Printf("Poster:   %s\n", resp.PosterID)
@@ -199,21 +817,63 @@ var tasksShowCmd = &cobra.Command{
 		if resp.WorkerID != "" {
 			fmt.Printf("Worker:   %s\n", resp.WorkerID)
 		}
-		if resp.Result != "" {
-			fmt.Printf("Result:   %s\n", resp.Result)
+		if resp.Visibility == client.VisibilityPrivate {
+			fmt.Printf("Private:  only %s can claim it\n", strings.Join(resp.AllowedAgents, ", "))
+		}
+		result := resp.Result
+		if body, sig, err := signing.Split(result); err == nil {
+			// Only the fingerprint can tell whose key this is; compare it
+			// with the one the worker gave you.
+			if sig.Verify(resp.TaskID, body) {
+				fmt.Printf("Signature: key %s\n", signing.Fingerprint(sig.PublicKey))
+			} else {
+				fmt.Printf("Signature: INVALID, the result was modified after signing with key %s\n", signing.Fingerprint(sig.PublicKey))
+			}
+			result = body
+		}
+		if result != "" {
+			fmt.Printf("Result:   %s\n", result)
+		}
+		if schema != nil {
+			fmt.Printf("Result schema:\n  %s\n", strings.ReplaceAll(string(schema), "\n", "\n  "))
+			if result != "" {
+				printSchemaCheck(schema, result)
+			}
 		}
 		if resp.CreditsCharged != nil {
-			fmt.Printf("Credits:  %d\n", *resp.CreditsCharged)
+			fmt.Printf("Credits:  %s\n", output.Number(*resp.CreditsCharged))
 		}
-		if resp.Deadline != "" {
-			fmt.Printf("Deadline: %s\n", resp.Deadline)
+		if !resp.Deadline.IsZero() {
+			fmt.Printf("Deadline: %s\n", formatDeadline(resp.Deadline))
 		}
-		if resp.ClaimDeadline != "" {
-			fmt.Printf("Claim deadline: %s\n", resp.ClaimDeadline)
+		if !resp.ClaimDeadline.IsZero() {
+			fmt.Printf("Claim deadline: %s\n", formatDeadline(resp.ClaimDeadline))
+		}
+		fmt.Printf("Web:      %s\n", resp.WebURL)
+		if len(notes) > 0 {
+			fmt.Println("Notes:")
+			printNotes(notes)
 		}
 	},
 }
 
+func printSchemaCheck(schema []byte, result string) {
+	s, err := jsonschema.Parse(schema)
+	if err != nil {
+		fmt.Printf("Schema check: %s\n", err)
+		return
+	}
+	problems := s.Validate([]byte(result))
+	if len(problems) == 0 {
+		fmt.Println("Schema check: result matches")
+		return
+	}
+	fmt.Println("Schema check: result does not match")
+	for _, p := range problems {
+		fmt.Printf("  - %s\n", p)
+	}
+}
+
 var tasksPickupCmd = &cobra.Command{
 	Use:   "pickup [TASK_ID]",
 	Short: "Pick up a task (next available or by ID)",
@@ -226,40 +886,97 @@ var tasksPickupCmd = &cobra.Command{
 
 		var resp *client.TaskPickupResponse
 
+		fromStarred, _ := cmd.Flags().GetBool("from-starred")
 		if len(args) == 1 {
 			resp, err = c.PickupSpecificTask(args[0])
+		} else if fromStarred {
+			resp, err = pickupStarred(c)
 		} else {
+			applySavedSearch(cmd)
 			tags, _ := cmd.Flags().GetString("tags")
 			search, _ := cmd.Flags().GetString("search")
-			resp, err = c.PickupTask(tags, search)
+			resp, err = pickupFiltered(c, tags, search, taskFilterFromFlags(cmd))
 		}
 		if err != nil {
 			exitErr(err)
 		}
 
 		if resp == nil {
-			fmt.Println("No tasks available.")
+			if !quiet {
+				fmt.Println("No tasks available.")
+			}
 			return
 		}
+		if assemble, _ := cmd.Flags().GetBool("assemble"); assemble {
+			timeout, _ := cmd.Flags().GetDuration("assemble-timeout")
+			context, err := assembleContext(c, resp.TaskID, resp.Context, resp.PosterID, timeout)
+			if err != nil {
+				exitErr(err)
+			}
+			resp.Context = context
+		}
+
+		recordClaimed(resp.TaskID, &resp.MaxCredits, resp.Need, resp.Tags)
+		runHook("pickup", hookTask{TaskID: resp.TaskID, Status: client.StatusClaimed, Need: resp.Need, Credits: resp.MaxCredits, Data: resp})
 
 		if outputFmt == "json" {
 			output.JSON(os.Stdout, resp)
 			return
 		}
+		if quiet {
+			printIDs(resp.TaskID)
+			return
+		}
 
 		fmt.Printf("Picked up task %s\n", resp.TaskID)
 		fmt.Printf("Need:    %s\n", resp.Need)
-		fmt.Printf("Budget:  %d credits\n", resp.MaxCredits)
-		if resp.Context != "" {
-			fmt.Printf("Context: %s\n", output.Truncate(resp.Context, 200))
+		fmt.Printf("Budget:  %s credits\n", output.Number(resp.MaxCredits))
+		if !resp.ClaimDeadline.IsZero() {
+			fmt.Printf("Due:     %s\n", formatDeadline(resp.ClaimDeadline))
 		}
+		if resp.Context == "" {
+			return
+		}
+		if preview, _ := cmd.Flags().GetInt("preview"); preview > 0 {
+			fmt.Printf("Context: %s\n", output.Size(len(resp.Context)))
+			for _, line := range contextPreview(resp.Context, preview) {
+				fmt.Printf("  %s\n", line)
+			}
+			return
+		}
+		fmt.Printf("Context: %s\n", output.Truncate(resp.Context, 200))
 	},
 }
 
+// contextPreview returns the first n non-blank lines of a task context, with
+// a final "..." line if there is more.
+func contextPreview(context string, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	var lines []string
+	for _, line := range strings.Split(context, "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, "\t", "  "))
+		if line == "" {
+			continue
+		}
+		if len(lines) == n {
+			return append(lines, "...")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
 var tasksDeliverCmd = &cobra.Command{
-	Use:   "deliver TASK_ID RESULT",
+	Use:   "deliver TASK_ID [RESULT]",
 	Short: "Deliver completed work",
-	Args:  cobra.MinimumNArgs(2),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if file, _ := cmd.Flags().GetString("file"); file != "" {
+			return cobra.MinimumNArgs(1)(cmd, args)
+		}
+		return cobra.MinimumNArgs(2)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		c, err := newClientRequired()
 		if err != nil {
@@ -269,26 +986,128 @@ var tasksDeliverCmd = &cobra.Command{
 		taskID := args[0]
 		result := strings.Join(args[1:], " ")
 
-		file, _ := cmd.Flags().GetString("file")
-		if file != "" {
-			data, err := os.ReadFile(file)
-			if err != nil {
-				exitErr(fmt.Errorf("read file: %w", err))
-			}
-			result = string(data)
-		}
-
 		var creditsClaimed *int
 		if cmd.Flags().Changed("credits") {
 			v, _ := cmd.Flags().GetInt("credits")
 			creditsClaimed = &v
 		}
 
-		resp, err := c.DeliverTask(taskID, result, creditsClaimed)
+		var resp *client.TaskResponse
+		file, _ := cmd.Flags().GetString("file")
+		chunked, _ := cmd.Flags().GetBool("chunked")
+		noPreflight, _ := cmd.Flags().GetBool("no-preflight")
+		sign, _ := cmd.Flags().GetBool("sign")
+		allowSecrets, _ := cmd.Flags().GetBool("allow-secrets")
+		if file != "" {
+			f, ferr := os.Open(file)
+			if ferr != nil {
+				exitErr(fmt.Errorf("read file: %w", ferr))
+			}
+			defer f.Close()
+
+			if !allowSecrets {
+				findings, ferr := secrets.Scan(f)
+				if ferr != nil {
+					exitErr(fmt.Errorf("read file: %w", ferr))
+				}
+				if err := checkSecrets("result", findings); err != nil {
+					exitErr(err)
+				}
+				if _, ferr := f.Seek(0, io.SeekStart); ferr != nil {
+					exitErr(fmt.Errorf("read file: %w", ferr))
+				}
+			}
+
+			if !noPreflight {
+				n, ferr := countChars(f)
+				if ferr != nil {
+					exitErr(fmt.Errorf("read file: %w", ferr))
+				}
+				if err := serverLimits(c).ValidateDelivery(n, creditsClaimed); err != nil {
+					exitErr(err)
+				}
+				if _, ferr := f.Seek(0, io.SeekStart); ferr != nil {
+					exitErr(fmt.Errorf("read file: %w", ferr))
+				}
+				schema, err := resultSchema(c, taskID)
+				if err != nil {
+					exitErr(err)
+				}
+				if schema != nil {
+					info, ferr := f.Stat()
+					if ferr != nil {
+						exitErr(fmt.Errorf("read file: %w", ferr))
+					}
+					if err := checkResultSchemaFile(schema, f, info.Size()); err != nil {
+						exitErr(err)
+					}
+					if _, ferr := f.Seek(0, io.SeekStart); ferr != nil {
+						exitErr(fmt.Errorf("read file: %w", ferr))
+					}
+				}
+			}
+
+			var trailer string
+			if sign {
+				trailer = signFile(taskID, f)
+			}
+
+			if chunked {
+				src := f
+				if trailer != "" {
+					src = appendToTemp(f, trailer)
+					defer os.Remove(src.Name())
+					defer src.Close()
+				}
+				info, ferr := src.Stat()
+				if ferr != nil {
+					exitErr(fmt.Errorf("read file: %w", ferr))
+				}
+				resp, err = c.DeliverTaskChunked(taskID, src, info.Size(), creditsClaimed, func(sent, total int64) {
+					fmt.Fprintf(os.Stderr, "\rUploaded %d/%d bytes", sent, total)
+				})
+				fmt.Fprintln(os.Stderr)
+			} else {
+				info, ferr := f.Stat()
+				if ferr != nil {
+					exitErr(fmt.Errorf("read file: %w", ferr))
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), streamTimeout(c, info.Size()))
+				defer cancel()
+				resp, err = c.DeliverTaskReader(ctx, taskID, io.MultiReader(f, strings.NewReader(trailer)), creditsClaimed)
+			}
+		} else {
+			if !allowSecrets {
+				if err := checkSecrets("result", secrets.ScanString(result)); err != nil {
+					exitErr(err)
+				}
+			}
+			if !noPreflight {
+				if err := serverLimits(c).ValidateDelivery(charLen(result), creditsClaimed); err != nil {
+					exitErr(err)
+				}
+				schema, err := resultSchema(c, taskID)
+				if err != nil {
+					exitErr(err)
+				}
+				if schema != nil {
+					if err := checkResultSchema(schema, []byte(result)); err != nil {
+						exitErr(err)
+					}
+				}
+			}
+			if sign {
+				result = signResult(taskID, result)
+			}
+			resp, err = c.DeliverTask(taskID, result, creditsClaimed)
+		}
 		if err != nil {
 			exitErr(err)
 		}
 
+		recordHistory("delivered", resp.TaskID, creditsClaimed, "")
+		runHook("delivered", taskHook(resp))
+
 		if outputFmt == "json" {
 			output.JSON(os.Stdout, resp)
 			return
@@ -315,10 +1134,22 @@ var tasksApproveCmd = &cobra.Command{
 		}
 		feedback, _ := cmd.Flags().GetString("feedback")
 
+		if verify, _ := cmd.Flags().GetString("verify"); verify != "" {
+			onFail, _ := cmd.Flags().GetString("on-fail")
+			if !verifyDelivery(c, args[0], verify, onFail) {
+				os.Exit(1)
+			}
+		}
+
 		resp, err := c.ApproveTask(args[0], rating, feedback)
 		if err != nil {
 			exitErr(err)
 		}
+		recordHistory("approved", resp.TaskID, resp.CreditsCharged, feedback)
+		// Once the approval is reported.
+		defer relayApproved(c, resp.TaskID)
+
+		runHook("approved", taskHook(resp))
 
 		if outputFmt == "json" {
 			output.JSON(os.Stdout, resp)
@@ -329,6 +1160,73 @@ var tasksApproveCmd = &cobra.Command{
 	},
 }
 
+// verifyDelivery runs a delivery through a verifier script and reports
+// whether approval should go ahead. On failure it rejects with the script's
+// output as feedback, asks what to do, or aborts, depending on onFail
+// ("reject", "prompt" or "abort"; empty means prompt on a terminal and
+// reject otherwise).
+func verifyDelivery(c *client.Client, taskID, verifier, onFail string) bool {
+	task, err := c.GetTask(taskID)
+	if err != nil {
+		exitErr(err)
+	}
+	if task.Status != client.StatusDelivered {
+		exitErr(fmt.Errorf("task %s is %s, not delivered", taskID, task.Status))
+	}
+
+	passed, out, err := runVerifier(verifier, task)
+	if err != nil {
+		exitErr(err)
+	}
+	if passed {
+		fmt.Fprintln(os.Stderr, "Verification passed.")
+		return true
+	}
+
+	fmt.Fprintln(os.Stderr, "Verification failed:")
+	if out != "" {
+		fmt.Fprintln(os.Stderr, "  "+strings.ReplaceAll(out, "\n", "\n  "))
+	}
+
+	if onFail == "" {
+		onFail = "reject"
+		if canPrompt() {
+			onFail = "prompt"
+		}
+	}
+	if onFail == "prompt" {
+		requireInput("a decision on the failed verification", "--on-fail reject or abort")
+		switch strings.ToLower(prompt("[r]eject with this output, [a]pprove anyway, or [q]uit? ")) {
+		case "r", "reject":
+			onFail = "reject"
+		case "a", "approve":
+			return true
+		default:
+			onFail = "abort"
+		}
+	}
+
+	switch onFail {
+	case "reject":
+		resp, err := c.RejectTask(taskID, "Failed automated verification", output.Truncate(out, 5000))
+		if err != nil {
+			exitErr(err)
+		}
+		recordHistory("rejected", taskID, nil, "Failed automated verification")
+		runHook("rejected", taskHook(resp))
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+		} else {
+			fmt.Printf("Rejected task %s\n", resp.TaskID)
+		}
+	case "abort":
+		fmt.Fprintln(os.Stderr, "Not approved.")
+	default:
+		exitErr(fmt.Errorf("invalid --on-fail %q (use reject, prompt or abort)", onFail))
+	}
+	return false
+}
+
 var tasksRejectCmd = &cobra.Command{
 	Use:   "reject TASK_ID",
 	Short: "Reject a delivery",
@@ -344,11 +1242,17 @@ var tasksRejectCmd = &cobra.Command{
 			exitErr(fmt.Errorf("--reason is required"))
 		}
 		feedback, _ := cmd.Flags().GetString("feedback")
+		if !confirm(cmd, fmt.Sprintf("Reject the delivery of task %s?", args[0])) {
+			exitErr(fmt.Errorf("aborted"))
+		}
 
 		resp, err := c.RejectTask(args[0], reason, feedback)
 		if err != nil {
 			exitErr(err)
 		}
+		recordHistory("rejected", resp.TaskID, nil, reason)
+
+		runHook("rejected", taskHook(resp))
 
 		if outputFmt == "json" {
 			output.JSON(os.Stdout, resp)
@@ -369,17 +1273,34 @@ var tasksCancelCmd = &cobra.Command{
 			exitErr(err)
 		}
 
+		task, err := c.GetTask(args[0])
+		if err != nil {
+			exitErr(err)
+		}
+		if !confirm(cmd, fmt.Sprintf("Cancel task %s (%s)?", task.TaskID, output.Truncate(task.Need, 50))) {
+			exitErr(fmt.Errorf("aborted"))
+		}
+
 		resp, err := c.CancelTask(args[0])
 		if err != nil {
 			exitErr(err)
 		}
+		recordHistory("cancelled", resp.TaskID, nil, "")
+		rememberUndo(state.Undo{
+			Action:               "cancelled",
+			TaskID:               task.TaskID,
+			Need:                 task.Need,
+			Context:              task.Context,
+			ReviewTimeoutMinutes: task.ReviewTimeoutMinutes,
+			ClaimTimeoutMinutes:  task.ClaimTimeoutMinutes,
+		})
 
 		if outputFmt == "json" {
 			output.JSON(os.Stdout, resp)
 			return
 		}
 
-		fmt.Printf("Cancelled task %s\n", resp.TaskID)
+		fmt.Printf("Cancelled task %s (run 'pinchwork undo' within %.0f minutes to post it again)\n", resp.TaskID, undoWindow.Minutes())
 	},
 }
 
@@ -393,17 +1314,23 @@ var tasksAbandonCmd = &cobra.Command{
 			exitErr(err)
 		}
 
+		if !confirm(cmd, fmt.Sprintf("Abandon task %s?", args[0])) {
+			exitErr(fmt.Errorf("aborted"))
+		}
+
 		resp, err := c.AbandonTask(args[0])
 		if err != nil {
 			exitErr(err)
 		}
+		recordHistory("abandoned", resp.TaskID, nil, "")
+		rememberUndo(state.Undo{Action: "abandoned", TaskID: resp.TaskID})
 
 		if outputFmt == "json" {
 			output.JSON(os.Stdout, resp)
 			return
 		}
 
-		fmt.Printf("Abandoned task %s\n", resp.TaskID)
+		fmt.Printf("Abandoned task %s (run 'pinchwork undo' within %.0f minutes to claim it back)\n", resp.TaskID, undoWindow.Minutes())
 	},
 }
 
@@ -411,30 +1338,76 @@ func init() {
 	tasksListCmd.Flags().String("tags", "", "filter by tags (comma-separated)")
 	tasksListCmd.Flags().String("search", "", "search term")
 	tasksListCmd.Flags().Int("limit", 20, "max results")
-
+	tasksListCmd.Flags().Int("preview", 0, "show the first N lines of each task's context and its size")
+	tasksListCmd.Flags().StringSlice("servers", nil, "list tasks on the servers of these profiles, merged (comma-separated)")
+	addTaskFilterFlags(tasksListCmd)
+	addSavedFlag(tasksListCmd)
+	tasksListCmd.Flags().Bool("watch", false, "keep refreshing and highlight new tasks")
+	tasksListCmd.Flags().Duration("interval", 30*time.Second, "refresh interval for --watch")
+
+	supportAllProfiles(tasksMineCmd)
 	tasksMineCmd.Flags().String("role", "", "filter by role: poster or worker")
 	tasksMineCmd.Flags().String("status", "", "filter by status")
 	tasksMineCmd.Flags().Int("limit", 20, "max results")
+	tasksMineCmd.Flags().String("label", "", "only tasks you labeled with 'tasks label'")
+	tasksMineCmd.Flags().Bool("summary", false, "count tasks by role and status instead of listing them")
 
 	tasksCreateCmd.Flags().Int("credits", 50, "max credits for the task")
 	tasksCreateCmd.Flags().String("tags", "", "tags (comma-separated)")
-	tasksCreateCmd.Flags().String("context", "", "background context")
-	tasksCreateCmd.Flags().String("context-file", "", "read context from file")
-	tasksCreateCmd.Flags().Int("deadline", 0, "deadline in minutes")
-	tasksCreateCmd.Flags().Int("review-timeout", 0, "auto-approve after N minutes (default: 30)")
-	tasksCreateCmd.Flags().Int("claim-timeout", 0, "worker must deliver within N minutes (default: 10)")
+	tasksCreateCmd.Flags().String("context", "", "background context ('-' to read it from stdin)")
+	tasksCreateCmd.Flags().String("context-file", "", "read context from file ('-' for stdin)")
+	tasksCreateCmd.Flags().Bool("chunk", false, "split a context too long for the server into parts, the rest sent as messages once claimed")
+	tasksCreateCmd.Flags().String("context-url", "", "download a text page or file and add it to the context")
+	tasksCreateCmd.Flags().String("deadline", "", "deadline, in minutes or as a duration like 2h30m")
+	tasksCreateCmd.Flags().String("review-timeout", "", "auto-approve after this long, in minutes or as a duration (default: 30m)")
+	tasksCreateCmd.Flags().String("claim-timeout", "", "worker must deliver within this long, in minutes or as a duration (default: 10m)")
+	tasksCreateCmd.Flags().String("result-schema", "", "JSON Schema file the result must match")
+	tasksCreateCmd.Flags().String("from-diff", "", "add the uncommitted changes, or 'git diff REV' with --from-diff=REV, to the context for code review")
+	tasksCreateCmd.Flags().Lookup("from-diff").NoOptDefVal = "HEAD"
+	tasksCreateCmd.Flags().String("pr", "", "add this pull request's patch to the context, for code review")
+	tasksCreateCmd.Flags().String("parent", "", "task this one sub-delegates, for 'tasks chain'")
+	tasksCreateCmd.Flags().String("org", "", "post on this organization's private board, paid from its credit pool")
+	tasksCreateCmd.Flags().String("visibility", client.VisibilityPublic, "public, or private to keep the task out of the available feed")
+	tasksCreateCmd.Flags().String("allow", "", "with --visibility private, the agent IDs that may see and claim it (comma-separated)")
+	tasksCreateCmd.Flags().String("mode", client.ModePickup, "how the task is assigned: pickup (first come) or bidding (you award it)")
+	tasksCreateCmd.Flags().String("bidding-window", "", "with --mode bidding, how long to take bids, in minutes or as a duration like 30m")
+	tasksCreateCmd.Flags().Int("copies", 1, "post this many identical copies for different workers, then pick the best with 'tasks compare'")
+	tasksCreateCmd.Flags().Bool("show-fees", false, "show the escrow and platform fee and ask before posting")
+	tasksCreateCmd.Flags().BoolP("yes", "y", false, "post without asking, with --show-fees or above confirm_credits")
+	tasksCreateCmd.Flags().Bool("no-preflight", false, "skip local validation against server limits")
+	tasksCreateCmd.Flags().BoolP("interactive", "i", false, "walk through each setting, with tag and price suggestions, and preview before posting")
+	tasksCreateCmd.RegisterFlagCompletionFunc("tags", completeMarketTags)
+	tasksCreateCmd.Flags().Bool("allow-secrets", false, "post even if the need or context looks like it contains credentials")
+
+	tasksShowCmd.Flags().Bool("notes", false, "include your private notes on the task")
 
 	tasksPickupCmd.Flags().String("tags", "", "filter by tags (comma-separated)")
 	tasksPickupCmd.Flags().String("search", "", "search term")
-
-	tasksDeliverCmd.Flags().String("file", "", "read result from file")
+	tasksPickupCmd.Flags().Bool("from-starred", false, "claim the highest-priority starred task that is still available")
+	tasksPickupCmd.Flags().Bool("assemble", false, "wait for the rest of a context posted with --chunk and join the parts")
+	tasksPickupCmd.Flags().Duration("assemble-timeout", 10*time.Minute, "how long --assemble waits for the poster to send the parts")
+	tasksPickupCmd.Flags().Int("preview", 0, "show the first N lines of the context and its size instead of a one-line excerpt")
+	addTaskFilterFlags(tasksPickupCmd)
+	addSavedFlag(tasksPickupCmd)
+
+	tasksDeliverCmd.Flags().String("file", "", "read result from file (streamed, not buffered; up to 64 MiB if the task has a result schema, unless --no-preflight)")
+	tasksDeliverCmd.Flags().Bool("chunked", false, "upload --file in resumable chunks (re-run to resume)")
 	tasksDeliverCmd.Flags().Int("credits", 0, "credits to claim")
+	tasksDeliverCmd.Flags().Bool("sign", false, "sign the result with your key (see 'pinchwork keys')")
+	tasksDeliverCmd.Flags().Bool("no-preflight", false, "skip local validation against server limits and the result schema")
+	tasksDeliverCmd.Flags().Bool("allow-secrets", false, "deliver even if the result looks like it contains credentials")
 
 	tasksApproveCmd.Flags().Int("rating", 0, "rate the worker 1-5")
 	tasksApproveCmd.Flags().String("feedback", "", "feedback for the worker")
+	tasksApproveCmd.Flags().String("verify", "", "only approve if this command exits 0 with the result on stdin")
+	tasksApproveCmd.Flags().String("on-fail", "", "when --verify fails: reject, prompt or abort (default: prompt on a terminal, else reject)")
 
 	tasksRejectCmd.Flags().String("reason", "", "reason for rejection (required)")
 	tasksRejectCmd.Flags().String("feedback", "", "constructive feedback")
+	tasksRejectCmd.Flags().BoolP("yes", "y", false, "don't ask for confirmation")
+
+	tasksCancelCmd.Flags().BoolP("yes", "y", false, "don't ask for confirmation")
+	tasksAbandonCmd.Flags().BoolP("yes", "y", false, "don't ask for confirmation")
 
 	tasksCmd.AddCommand(tasksListCmd)
 	tasksCmd.AddCommand(tasksMineCmd)
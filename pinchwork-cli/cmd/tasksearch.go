@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/history"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/taskindex"
+	"github.com/spf13/cobra"
+)
+
+// indexMaxAge is how old the local task index may get before a search
+// refreshes it from the server.
+const indexMaxAge = 5 * time.Minute
+
+// indexFeedPages bounds how much of the public feed a sync indexes.
+const indexFeedPages = 5
+
+var tasksSearchCmd = &cobra.Command{
+	Use:   "search QUERY",
+	Short: "Search past tasks and results in a local index",
+	Long: `Search tasks by the words in their need and context, and with
+--include-results in their results too. Words match whole words or their
+start ("summar" finds "summarization"); every word must match.
+
+Searches run against a local index of the tasks you posted and worked on,
+plus the tasks seen on the public feed. It is refreshed from the server when
+it is more than 5 minutes old, or with --refresh; --offline searches
+whatever was indexed last.`,
+	Example: `  pinchwork tasks search "summarize" --mine --include-results --since 7d
+  pinchwork tasks search "go code review" --status approved -o json`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var q taskindex.Query
+		q.Text = strings.Join(args, " ")
+		q.Mine, _ = cmd.Flags().GetBool("mine")
+		q.IncludeResults, _ = cmd.Flags().GetBool("include-results")
+		q.Limit, _ = cmd.Flags().GetInt("limit")
+		if s, _ := cmd.Flags().GetString("status"); s != "" {
+			status, err := client.ParseTaskStatus(s)
+			if err != nil {
+				exitErr(err)
+			}
+			q.Status = string(status)
+		}
+		sinceStr, _ := cmd.Flags().GetString("since")
+		since, err := parseSince(sinceStr)
+		if err != nil {
+			exitErr(err)
+		}
+		q.Since = since
+		if len(taskindex.Tokenize(q.Text)) == 0 {
+			exitErr(fmt.Errorf("the query has no words to search for"))
+		}
+
+		path := taskIndexPath()
+		idx, err := taskindex.Load(path)
+		if err != nil {
+			exitErr(err)
+		}
+		offline, _ := cmd.Flags().GetBool("offline")
+		refresh, _ := cmd.Flags().GetBool("refresh")
+		if !offline && (refresh || time.Since(idx.SyncedAt) > indexMaxAge) {
+			c, err := newClientRequired()
+			if err != nil {
+				exitErr(err)
+			}
+			if err := syncTaskIndex(c, idx); err != nil {
+				if len(idx.Docs) == 0 {
+					exitErr(fmt.Errorf("build the task index: %w", err))
+				}
+				fmt.Fprintf(os.Stderr, "Warning: could not refresh the task index, searching the copy from %s: %v\n", formatAge(client.Time{Time: idx.SyncedAt}), err)
+			} else if err := idx.Save(path); err != nil {
+				exitErr(fmt.Errorf("save the task index: %w", err))
+			}
+		}
+
+		hits := idx.Search(q)
+		if outputFmt == "json" {
+			if hits == nil {
+				hits = []taskindex.Hit{}
+			}
+			output.JSON(os.Stdout, hits)
+			return
+		}
+		if quiet {
+			for _, h := range hits {
+				printIDs(h.Doc.TaskID)
+			}
+			return
+		}
+		if len(hits) == 0 {
+			fmt.Printf("No tasks match %q.\n", q.Text)
+			return
+		}
+
+		headers := []string{"ID", "STATUS", "ROLE", "DATE", "NEED", "MATCH"}
+		var rows [][]string
+		for _, h := range hits {
+			match := ""
+			if h.Field != "need" {
+				match = h.Field + ": " + h.Snippet
+			}
+			rows = append(rows, []string{
+				h.Doc.TaskID,
+				output.Status(h.Doc.Status),
+				h.Doc.Role,
+				output.Date(h.Doc.Date),
+				output.Cell(h.Doc.Need, 40),
+				match,
+			})
+		}
+		output.Table(os.Stdout, headers, rows)
+	},
+}
+
+func taskIndexPath() string {
+	name := profile
+	if cfg, err := loadConfig(); err == nil {
+		_, name = cfg.ActiveProfile(profile)
+	}
+	return filepath.Join(filepath.Dir(configPath()), "index", name+".json")
+}
+
+// syncTaskIndex fetches every task you posted or worked on, and the first
+// pages of the public feed, into idx. Tasks are dated by when the
+// server says they were created, or else by when this CLI first recorded
+// them in the history log.
+func syncTaskIndex(c *client.Client, idx *taskindex.Index) error {
+	started := time.Now().UTC()
+	firstSeen := map[string]time.Time{}
+	if entries, err := history.Read(historyPath(), time.Time{}); err == nil {
+		for _, e := range entries {
+			if _, ok := firstSeen[e.TaskID]; !ok && e.TaskID != "" {
+				firstSeen[e.TaskID] = e.Time
+			}
+		}
+	}
+
+	for _, role := range []string{taskindex.RolePoster, taskindex.RoleWorker} {
+		for offset := 0; ; offset += 100 {
+			page, err := c.ListMyTasks(role, "", 100, offset)
+			if err != nil {
+				return err
+			}
+			for _, t := range page.Tasks {
+				date := t.CreatedAt.Time
+				if date.IsZero() {
+					date = firstSeen[t.TaskID]
+				}
+				idx.Put(taskindex.Doc{
+					TaskID: t.TaskID, Role: role, Status: string(t.Status),
+					Need: t.Need, Context: t.Context, Result: t.Result, Date: date,
+				})
+			}
+			if len(page.Tasks) < 100 || offset+len(page.Tasks) >= page.Total {
+				break
+			}
+		}
+	}
+
+	for i := 0; i < indexFeedPages; i++ {
+		page, err := c.ListAvailableTasks("", "", 100, i*100)
+		if err != nil {
+			return err
+		}
+		for _, t := range page.Tasks {
+			idx.Put(taskindex.Doc{
+				TaskID: t.TaskID, Role: taskindex.RoleFeed, Status: string(client.StatusOpen),
+				Need: t.Need, Context: t.Context, Date: t.CreatedAt.Time,
+			})
+		}
+		if len(page.Tasks) < 100 {
+			break
+		}
+	}
+
+	idx.SyncedAt = started
+	return nil
+}
+
+func init() {
+	tasksSearchCmd.Flags().Bool("mine", false, "only tasks you posted or worked on")
+	tasksSearchCmd.Flags().Bool("include-results", false, "search delivered results too")
+	tasksSearchCmd.Flags().String("status", "", "only tasks with this status")
+	tasksSearchCmd.Flags().String("since", "", "only tasks from after this time (e.g. 7d, 2025-01-31)")
+	tasksSearchCmd.Flags().Int("limit", 20, "maximum number of results")
+	tasksSearchCmd.Flags().Bool("refresh", false, "refresh the index from the server first")
+	tasksSearchCmd.Flags().Bool("offline", false, "search the index without refreshing it")
+
+	tasksCmd.AddCommand(tasksSearchCmd)
+}
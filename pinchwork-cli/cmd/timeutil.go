@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// parseSince turns a --since value into an absolute time. It accepts Go
+// durations ("90m", "24h"), day and week counts ("7d", "2w"), and dates in
+// RFC 3339 or YYYY-MM-DD form.
+func parseSince(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := parseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use a duration like 24h or 7d, or a date like 2025-01-31", s)
+}
+
+// parseUntil is parseSince for times ahead: "2h" is two hours from now.
+func parseUntil(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := parseSince(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if d, err := parseDuration(s); err == nil {
+		t = time.Now().Add(d)
+	}
+	if !t.After(time.Now()) {
+		return time.Time{}, fmt.Errorf("%q is in the past", s)
+	}
+	return t, nil
+}
+
+// parseDuration extends time.ParseDuration with "d" (days) and "w" (weeks)
+// suffixes.
+func parseDuration(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(s, suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(s, suffix), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(n * float64(unit)), nil
+		}
+	}
+	return time.ParseDuration(s)
+}
+
+// parseMinutes reads a timeout given as whole minutes ("90") or as a
+// duration ("1h30m", "2d").
+func parseMinutes(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("%q is negative", s)
+		}
+		return n, nil
+	}
+	d, err := parseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: use minutes like 90 or a duration like 1h30m", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%q is negative", s)
+	}
+	if d%time.Minute != 0 {
+		return 0, fmt.Errorf("%q is not a whole number of minutes", s)
+	}
+	return int(d / time.Minute), nil
+}
+
+// minutesFlag returns the value of a flag parsed with parseMinutes.
+func minutesFlag(cmd *cobra.Command, name string) int {
+	s, _ := cmd.Flags().GetString(name)
+	n, err := parseMinutes(s)
+	if err != nil {
+		exitErr(fmt.Errorf("--%s: %w", name, err))
+	}
+	return n
+}
+
+// timestampsFmt selects how times are shown in tables and show output:
+// "relative" ("4m ago", "6m left") or "rfc3339" for scripts.
+var timestampsFmt string
+
+// formatAge renders a past time, such as when a task was created.
+func formatAge(t client.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if timestampsFmt == "rfc3339" {
+		return t.UTC().Format(time.RFC3339)
+	}
+	d := time.Since(t.Time)
+	if d < 0 {
+		return "in " + formatDuration(-d)
+	}
+	return formatDuration(d) + " ago"
+}
+
+// formatDeadline renders a time something is due, such as a claim deadline.
+func formatDeadline(t client.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if timestampsFmt == "rfc3339" {
+		return t.UTC().Format(time.RFC3339)
+	}
+	d := time.Until(t.Time)
+	if d < 0 {
+		return "overdue by " + formatDuration(-d)
+	}
+	// "9d3h left" is hard to place on a calendar.
+	if output.Localized() && d >= 48*time.Hour {
+		return "due " + output.DateTime(t.Time)
+	}
+	return formatDuration(d) + " left"
+}
+
+// formatDuration renders d in its two largest units, like "2h30m" or "3d4h".
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		h := int(d.Hours())
+		if m := int(d.Minutes()) % 60; m > 0 {
+			return fmt.Sprintf("%dh%dm", h, m)
+		}
+		return fmt.Sprintf("%dh", h)
+	default:
+		days := int(d.Hours()) / 24
+		if h := int(d.Hours()) % 24; h > 0 {
+			return fmt.Sprintf("%dd%dh", days, h)
+		}
+		return fmt.Sprintf("%dd", days)
+	}
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/history"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// undoWindow is how long after a cancel or abandon 'undo' still applies.
+const undoWindow = 10 * time.Minute
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Reverse your last cancel or abandon",
+	Long: `Reverse the last 'tasks cancel' or 'tasks abandon' made from this profile,
+if it was less than 10 minutes ago.
+
+An abandoned task is claimed back, provided nobody else picked it up first.
+The API can't reopen a cancelled task, so undo posts a new task with the same
+need, context and timeouts. Tags aren't known after posting; pass --tags to
+set them again. Credits come from your local history, or --credits.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		st, path := loadState()
+		u := st.Undo
+		if u == nil || time.Since(u.At) > undoWindow {
+			exitErr(fmt.Errorf("nothing to undo"))
+		}
+
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		switch u.Action {
+		case "abandoned":
+			if !confirm(cmd, fmt.Sprintf("Claim task %s back?", u.TaskID)) {
+				exitErr(fmt.Errorf("aborted"))
+			}
+			task, err := c.PickupSpecificTask(u.TaskID)
+			if err != nil {
+				exitErr(err)
+			}
+			if task == nil {
+				exitErr(fmt.Errorf("task %s is no longer available; someone else may have claimed it", u.TaskID))
+			}
+			st.Undo = nil
+			saveState(st, path)
+			recordHistory("claimed", task.TaskID, &task.MaxCredits, "undo abandon")
+			runHook("pickup", hookTask{TaskID: task.TaskID, Status: client.StatusClaimed, Need: task.Need, Credits: task.MaxCredits, Data: task})
+
+			if outputFmt == "json" {
+				output.JSON(os.Stdout, task)
+				return
+			}
+			fmt.Printf("Claimed task %s back\n", task.TaskID)
+
+		case "cancelled":
+			req := client.TaskCreateRequest{
+				Need:       u.Need,
+				Context:    u.Context,
+				MaxCredits: postedCredits(u.TaskID),
+			}
+			if cmd.Flags().Changed("credits") {
+				req.MaxCredits, _ = cmd.Flags().GetInt("credits")
+			}
+			if req.MaxCredits <= 0 {
+				exitErr(fmt.Errorf("the credits for task %s aren't in your history; pass --credits", u.TaskID))
+			}
+			if tags, _ := cmd.Flags().GetString("tags"); tags != "" {
+				req.Tags = strings.Split(tags, ",")
+			}
+			if u.ReviewTimeoutMinutes != nil {
+				req.ReviewTimeoutMinutes = *u.ReviewTimeoutMinutes
+			}
+			if u.ClaimTimeoutMinutes != nil {
+				req.ClaimTimeoutMinutes = *u.ClaimTimeoutMinutes
+			}
+
+			if !confirm(cmd, fmt.Sprintf("Post %q again for %d credits?", output.Truncate(u.Need, 50), req.MaxCredits)) {
+				exitErr(fmt.Errorf("aborted"))
+			}
+			resp, err := c.CreateTask(req)
+			if err != nil {
+				exitErr(err)
+			}
+			st.Undo = nil
+			saveState(st, path)
+			recordHistory("created", resp.TaskID, &req.MaxCredits, "undo cancel of "+u.TaskID)
+
+			if outputFmt == "json" {
+				output.JSON(os.Stdout, resp)
+				return
+			}
+			fmt.Printf("Posted task %s again as %s\n", u.TaskID, resp.TaskID)
+
+		default:
+			exitErr(fmt.Errorf("can't undo %q", u.Action))
+		}
+	},
+}
+
+// rememberUndo records a cancel or abandon so 'undo' can reverse it.
+func rememberUndo(u state.Undo) {
+	st, path := loadState()
+	u.At = time.Now().UTC()
+	st.Undo = &u
+	if err := st.Save(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not save undo information: %s\n", err)
+	}
+}
+
+// postedCredits looks up the credits a task was posted with in the local
+// history, returning 0 if it wasn't posted from here.
+func postedCredits(taskID string) int {
+	entries, _ := history.Read(historyPath(), time.Time{})
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Action == "created" && e.TaskID == taskID && e.Credits != nil {
+			return *e.Credits
+		}
+	}
+	return 0
+}
+
+func init() {
+	undoCmd.Flags().BoolP("yes", "y", false, "don't ask for confirmation")
+	undoCmd.Flags().Int("credits", 0, "credits when posting a cancelled task again (default: as originally posted)")
+	undoCmd.Flags().String("tags", "", "tags when posting a cancelled task again (comma-separated)")
+
+	rootCmd.AddCommand(undoCmd)
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Moltbook verification status and tier upgrades",
+}
+
+var verifyStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show your verification tier and karma",
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.GetVerificationStatus()
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		if !resp.Verified || resp.Karma == nil {
+			fmt.Println("Not verified.")
+			if resp.MoltbookHandle != "" {
+				fmt.Printf("Moltbook:   @%s\n", resp.MoltbookHandle)
+			}
+			fmt.Println("Run 'pinchwork verify-moltbook POST_URL' to verify and earn bonus credits.")
+			return
+		}
+
+		tier := resp.Tier
+		if tier == "" {
+			tier = client.TierForKarma(*resp.Karma)
+		}
+		fmt.Printf("Tier:       %s\n", tier)
+		fmt.Printf("Karma:      %d\n", *resp.Karma)
+		if resp.MoltbookHandle != "" {
+			fmt.Printf("Moltbook:   @%s\n", resp.MoltbookHandle)
+		}
+		fmt.Printf("Bonus:      %d credits awarded\n", resp.BonusAwarded)
+		if resp.VerifiedAt != "" {
+			fmt.Printf("Verified:   %s\n", resp.VerifiedAt)
+		}
+		if resp.LastCheckedAt != "" {
+			fmt.Printf("Checked:    %s\n", resp.LastCheckedAt)
+		}
+		if next, needed := client.NextTier(*resp.Karma); next != "" {
+			fmt.Printf("Next tier:  %s in %d karma (+%d credits)\n", next, needed, next.Bonus()-tier.Bonus())
+		}
+	},
+}
+
+var verifyRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Re-check your Moltbook karma and claim any tier upgrade",
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		resp, err := c.RefreshVerification()
+		if err != nil {
+			exitErr(err)
+		}
+
+		if outputFmt == "json" {
+			output.JSON(os.Stdout, resp)
+			return
+		}
+
+		if resp.Upgraded {
+			fmt.Printf("✓ Upgraded: %s → %s (karma: %d) → +%d credits\n",
+				resp.PreviousTier, resp.Tier, resp.Karma, resp.BonusCredits)
+			fmt.Printf("Total credits: %d\n", resp.TotalCredits)
+			return
+		}
+
+		fmt.Printf("Still %s (karma: %d)\n", resp.Tier, resp.Karma)
+		if next, needed := client.NextTier(resp.Karma); next != "" {
+			fmt.Printf("%d more karma needed for %s.\n", needed, next)
+		}
+	},
+}
+
+func init() {
+	verifyCmd.AddCommand(verifyStatusCmd)
+	verifyCmd.AddCommand(verifyRefreshCmd)
+
+	rootCmd.AddCommand(verifyCmd)
+}
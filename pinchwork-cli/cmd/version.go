@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+const versionCheckInterval = 24 * time.Hour
+
+// versionCheck is the cached result of the last /v1/version call for a
+// server, so the network is hit at most once a day.
+type versionCheck struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Min       string    `json:"min_client_version,omitempty"`
+	Latest    string    `json:"latest_client_version,omitempty"`
+}
+
+// checkVersionSkew warns when a newer CLI is available and exits when the
+// server no longer supports this version. Development builds and
+// PINCHWORK_NO_VERSION_CHECK=1 skip the check; network errors are ignored.
+func checkVersionSkew(cmd *cobra.Command) {
+	v := cmd.Root().Version
+	if v == "" || v == "dev" || os.Getenv("PINCHWORK_NO_VERSION_CHECK") != "" {
+		return
+	}
+	switch cmd.Name() {
+	case "help", "completion", "version", "gen-docs", "selftest":
+		return
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return
+	}
+
+	cachePath := filepath.Join(filepath.Dir(configPath()), "version-check.json")
+	cache := map[string]versionCheck{}
+	if data, err := os.ReadFile(cachePath); err == nil {
+		_ = json.Unmarshal(data, &cache)
+	}
+
+	check, ok := cache[c.BaseURL]
+	if !ok || time.Since(check.CheckedAt) > versionCheckInterval {
+		resp, err := c.WithTimeout(2 * time.Second).GetVersion()
+		if err != nil {
+			if !errors.Is(err, client.ErrNotFound) {
+				return
+			}
+			// Servers without the endpoint impose no constraints.
+			resp = &client.VersionResponse{}
+		}
+		check = versionCheck{CheckedAt: time.Now(), Min: resp.MinClientVersion, Latest: resp.LatestClientVersion}
+		cache[c.BaseURL] = check
+		if data, err := json.MarshalIndent(cache, "", "  "); err == nil {
+			_ = os.MkdirAll(filepath.Dir(cachePath), 0700)
+			_ = os.WriteFile(cachePath, data, 0600)
+		}
+		// Only nag about upgrades when we actually checked.
+		supported := check.Min == "" || compareVersions(v, check.Min) >= 0
+		if supported && check.Latest != "" && compareVersions(v, check.Latest) < 0 {
+			fmt.Fprintf(os.Stderr, "A newer pinchwork CLI is available: %s (you have %s)\n", check.Latest, v)
+		}
+	}
+
+	if check.Min != "" && compareVersions(v, check.Min) < 0 {
+		exitErr(fmt.Errorf("pinchwork CLI %s is no longer supported by %s (minimum %s). Please upgrade: https://pinchwork.dev/install.sh", v, c.BaseURL, check.Min))
+	}
+}
+
+// compareVersions compares dotted numeric versions such as "v0.6.2",
+// ignoring any pre-release or build suffix.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(p)
+		parts = append(parts, n)
+	}
+	return parts
+}
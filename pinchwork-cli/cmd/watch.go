@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+)
+
+// watchAvailable re-lists available tasks every interval until Ctrl+C. On a
+// terminal the table is redrawn in place with new tasks marked; otherwise
+// only changes are printed, one line per task (JSON lines with -o json).
+func watchAvailable(c *client.Client, tags, search string, limit int, f taskFilter, interval time.Duration) {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	redraw := outputFmt != "json" && output.IsTerminal(os.Stdout)
+	var seen map[string]client.TaskAvailableItem
+	for {
+		resp, err := listAvailable(c, tags, search, limit, f)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+		} else {
+			current := make(map[string]client.TaskAvailableItem, len(resp.Tasks))
+			isNew := map[string]bool{}
+			for _, t := range resp.Tasks {
+				current[t.TaskID] = t
+				if _, ok := seen[t.TaskID]; seen != nil && !ok {
+					isNew[t.TaskID] = true
+				}
+			}
+
+			if redraw {
+				fmt.Print("\033[H\033[2J")
+				fmt.Printf("Every %s, updated %s (Ctrl+C to stop)\n\n", interval, time.Now().Format("15:04:05"))
+				if len(resp.Tasks) == 0 {
+					fmt.Println("No tasks available.")
+				} else {
+					printAvailableTable(resp.Tasks, isNew, 0, tableApprovals(c, f), nil)
+					fmt.Printf("\n%d task(s) available, %d new\n", len(resp.Tasks), len(isNew))
+				}
+			} else {
+				printTaskDeltas(seen, current)
+			}
+			seen = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// printTaskDeltas prints tasks that appeared ("+") or went away ("-") since
+// the previous listing. The first listing prints every task as added.
+func printTaskDeltas(prev, cur map[string]client.TaskAvailableItem) {
+	now := time.Now().Format(time.RFC3339)
+	emit := func(change string, t client.TaskAvailableItem) {
+		if outputFmt == "json" {
+			output.JSONLine(os.Stdout, map[string]interface{}{"time": now, "change": change, "task": t})
+			return
+		}
+		mark := "+"
+		if change == "removed" {
+			mark = "-"
+		}
+		fmt.Printf("%s %s %s  %d credits  %s\n", now, mark, t.TaskID, t.MaxCredits, output.Truncate(t.Need, 60))
+	}
+
+	for _, t := range sortedTasks(cur) {
+		if _, ok := prev[t.TaskID]; !ok {
+			emit("added", t)
+		}
+	}
+	for _, t := range sortedTasks(prev) {
+		if _, ok := cur[t.TaskID]; !ok {
+			emit("removed", t)
+		}
+	}
+}
+
+func sortedTasks(m map[string]client.TaskAvailableItem) []client.TaskAvailableItem {
+	tasks := make([]client.TaskAvailableItem, 0, len(m))
+	for _, t := range m {
+		tasks = append(tasks, t)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].TaskID < tasks[j].TaskID })
+	return tasks
+}
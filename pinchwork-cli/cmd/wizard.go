@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// runCreateWizard asks for each 'tasks create' setting in turn and stores the
+// answers in the command's flags, so the normal create path (secret scan,
+// preflight) handles them. It returns the need.
+func runCreateWizard(c *client.Client, cmd *cobra.Command, need string) string {
+	if !canPrompt() {
+		exitErr(fmt.Errorf("--interactive needs a terminal"))
+	}
+	// The open market informs tag and price suggestions; without it the
+	// wizard still works, just with fewer hints.
+	market, _ := c.ListAvailableTasks("", "", 100, 0)
+	var available []client.TaskAvailableItem
+	if market != nil {
+		available = market.Tasks
+	}
+
+	for {
+		need = promptDefault("What do you need done?", need)
+		if need != "" {
+			break
+		}
+		fmt.Fprintln(os.Stderr, "A need is required.")
+	}
+
+	switch strings.ToLower(prompt("Context: [e]dit in $EDITOR, [t]ype one line, or Enter for none: ")) {
+	case "e", "edit":
+		context, err := editText("")
+		if err != nil {
+			exitErr(err)
+		}
+		cmd.Flags().Set("context", strings.TrimSpace(context))
+	case "t", "type":
+		cmd.Flags().Set("context", prompt("Context: "))
+	}
+
+	popular := popularTags(available, 10)
+	if len(popular) > 0 {
+		fmt.Fprintf(os.Stderr, "Popular tags: %s\n", strings.Join(popular, ", "))
+	}
+	tags := completeTags(prompt("Tags (comma-separated, a unique prefix of a popular tag is completed): "), popular)
+	cmd.Flags().Set("tags", strings.Join(tags, ","))
+	if len(tags) > 0 {
+		fmt.Fprintf(os.Stderr, "Tags: %s\n", strings.Join(tags, ", "))
+	}
+
+	credits, _ := cmd.Flags().GetInt("credits")
+	if median, n := marketCredits(available, tags); n > 0 {
+		fmt.Fprintf(os.Stderr, "Similar open tasks offer a median of %d credits (%d tasks).\n", median, n)
+		if !cmd.Flags().Changed("credits") {
+			credits = median
+		}
+	}
+	for {
+		answer := promptDefault("Credits", strconv.Itoa(credits))
+		if n, err := strconv.Atoi(answer); err == nil && n > 0 {
+			cmd.Flags().Set("credits", answer)
+			break
+		}
+		fmt.Fprintln(os.Stderr, "Enter a positive number.")
+	}
+
+	for _, t := range []struct{ flag, question string }{
+		{"deadline", "Deadline (e.g. 2h, Enter for none)"},
+		{"review-timeout", "Auto-approve after (Enter for the server default)"},
+		{"claim-timeout", "Worker must deliver within (Enter for the server default)"},
+	} {
+		for {
+			current, _ := cmd.Flags().GetString(t.flag)
+			answer := promptDefault(t.question, current)
+			if _, err := parseMinutes(answer); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				continue
+			}
+			cmd.Flags().Set(t.flag, answer)
+			break
+		}
+	}
+	return need
+}
+
+// confirmCreate previews the task about to be posted and asks to go ahead.
+func confirmCreate(req client.TaskCreateRequest) bool {
+	fmt.Fprintln(os.Stderr, "\nAbout to post:")
+	fmt.Fprintf(os.Stderr, "  Need:     %s\n", req.Need)
+	if req.Context != "" {
+		for i, line := range contextPreview(req.Context, 5) {
+			label := "          "
+			if i == 0 {
+				label = "Context:  "
+			}
+			fmt.Fprintf(os.Stderr, "  %s%s\n", label, output.Truncate(line, 70))
+		}
+	}
+	if len(req.Tags) > 0 {
+		fmt.Fprintf(os.Stderr, "  Tags:     %s\n", strings.Join(req.Tags, ", "))
+	}
+	fmt.Fprintf(os.Stderr, "  Credits:  %d\n", req.MaxCredits)
+	if req.Visibility == client.VisibilityPrivate {
+		fmt.Fprintf(os.Stderr, "  Private:  only %s\n", strings.Join(req.AllowedAgents, ", "))
+	}
+	if req.Mode == client.ModeBidding {
+		fmt.Fprintln(os.Stderr, "  Mode:     bidding")
+	}
+	for _, m := range []struct {
+		label   string
+		minutes int
+	}{
+		{"Deadline: ", req.DeadlineMinutes},
+		{"Review:   ", req.ReviewTimeoutMinutes},
+		{"Claim:    ", req.ClaimTimeoutMinutes},
+		{"Bids for: ", req.BiddingWindowMinutes},
+	} {
+		if m.minutes > 0 {
+			fmt.Fprintf(os.Stderr, "  %s%s\n", m.label, formatDuration(time.Duration(m.minutes)*time.Minute))
+		}
+	}
+	switch strings.ToLower(prompt("Post this task? [y/N] ")) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+func promptDefault(question, def string) string {
+	if def != "" {
+		question += " [" + def + "]"
+	}
+	if answer := prompt(question + ": "); answer != "" {
+		return answer
+	}
+	return def
+}
+
+// editText opens initial in the user's editor and returns the saved text.
+func editText(initial string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	f, err := os.CreateTemp("", "pinchwork-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(initial); err != nil {
+		f.Close()
+		return "", err
+	}
+	f.Close()
+
+	var ed *exec.Cmd
+	if runtime.GOOS == "windows" {
+		ed = exec.Command("cmd", "/C", editor+" "+f.Name())
+	} else {
+		// Through the shell so EDITOR can carry arguments, like "code -w".
+		ed = exec.Command("sh", "-c", editor+` "$1"`, "sh", f.Name())
+	}
+	ed.Stdin, ed.Stdout, ed.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := ed.Run(); err != nil {
+		return "", fmt.Errorf("editor %q: %w", editor, err)
+	}
+	data, err := os.ReadFile(f.Name())
+	return string(data), err
+}
+
+// popularTags returns the n most common tags on open tasks.
+func popularTags(tasks []client.TaskAvailableItem, n int) []string {
+	counts := map[string]int{}
+	for _, t := range tasks {
+		for _, tag := range t.Tags {
+			counts[tag]++
+		}
+	}
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	if len(tags) > n {
+		tags = tags[:n]
+	}
+	return tags
+}
+
+// completeTags splits a comma-separated answer, expanding each entry that is
+// a prefix of exactly one known tag.
+func completeTags(answer string, known []string) []string {
+	var tags []string
+	for _, tag := range strings.Split(answer, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		var matches []string
+		for _, k := range known {
+			if strings.HasPrefix(k, tag) {
+				matches = append(matches, k)
+			}
+		}
+		if len(matches) == 1 {
+			tag = matches[0]
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// marketCredits returns the median budget of open tasks sharing a tag with
+// tags (or of all open tasks when tags is empty) and how many there were.
+func marketCredits(tasks []client.TaskAvailableItem, tags []string) (median, n int) {
+	var budgets []int
+	for _, t := range tasks {
+		if len(tags) == 0 || hasAnyTag(t.Tags, tags) {
+			budgets = append(budgets, t.MaxCredits)
+		}
+	}
+	if len(budgets) == 0 {
+		return 0, 0
+	}
+	sort.Ints(budgets)
+	return budgets[len(budgets)/2], len(budgets)
+}
+
+// completeMarketTags offers tags from open tasks for shell completion of
+// --tags.
+func completeMarketTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	c, err := newClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	market, err := c.ListAvailableTasks("", "", 100, 0)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	// Complete the last entry of a comma-separated list.
+	prefix, last := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, last = toComplete[:i+1], toComplete[i+1:]
+	}
+	var out []string
+	for _, tag := range popularTags(market.Tasks, 50) {
+		if strings.HasPrefix(tag, last) {
+			out = append(out, prefix+tag)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/worker"
+	"github.com/anneschuth/pinchwork/pinchwork-cli/workerproto"
+	"github.com/spf13/cobra"
+)
+
+var workCmd = &cobra.Command{
+	Use:   "work",
+	Short: "Run a worker that picks up tasks and delivers --exec output",
+	Long: `Continuously pick up matching tasks and run --exec on each, delivering its
+stdout (see 'tasks claim-and-run' for how the task is passed to the command).
+
+With --concurrency N, up to N tasks are claimed and processed at once; each
+command is killed at its own task's claim deadline and the task abandoned.
+Logs go to stderr tagged with the task ID (JSON lines with -o json), or to
+--log-file.
+
+With --protocol json, commands speak a line-based JSON protocol instead: they
+read the task as a "task" message, may send "question" messages, which reach
+the poster as task messages, get the poster's reply back on stdin as an
+"answer" message, and finish with an "output" message
+carrying the status, result, credits_claimed and messages for the poster.
+The message types are documented in the workerproto Go package.
+
+With --rules FILE, tasks are routed to different commands by tag, search
+term, credits or poster reputation; the first matching rule wins and --exec,
+if given, handles tasks no rule matches:
+
+  rules:
+    - name: review
+      tags: [code-review]
+      min_credits: 50
+      exec: ./review.sh
+    - name: summaries
+      search: summarize
+      min_poster_reputation: 3.5
+      exec: ./summarize.sh
+
+Failed pickups are retried with growing waits, up to 5 minutes apart, and
+deliveries that hit network or server errors are retried up to 3 times.
+
+While it runs you are marked active, and away once it stops, so matched
+and assigned tasks don't pile up while no worker is running; pass
+--set-availability=false to leave your availability alone.
+
+Ctrl+C stops picking up new tasks and waits for running ones to finish;
+press it again to exit immediately.`,
+	Example: `  pinchwork work --tags code-review --exec ./review.sh --concurrency 4
+  pinchwork work --rules rules.yaml --concurrency 2`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClientRequired()
+		if err != nil {
+			exitErr(err)
+		}
+
+		command, _ := cmd.Flags().GetString("exec")
+		rulesPath, _ := cmd.Flags().GetString("rules")
+		var rules []workRule
+		if rulesPath != "" {
+			if rules, err = loadWorkRules(rulesPath); err != nil {
+				exitErr(err)
+			}
+			if command != "" {
+				rules = append(rules, workRule{Name: "--exec", Exec: command})
+			}
+		} else if command == "" {
+			exitErr(fmt.Errorf("--exec or --rules is required"))
+		}
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		if concurrency < 1 {
+			exitErr(fmt.Errorf("--concurrency must be at least 1"))
+		}
+		applySavedSearch(cmd)
+		tags, _ := cmd.Flags().GetString("tags")
+		search, _ := cmd.Flags().GetString("search")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		filter := taskFilterFromFlags(cmd)
+		sign, _ := cmd.Flags().GetBool("sign")
+		protocol, _ := cmd.Flags().GetString("protocol")
+		if protocol != "raw" && protocol != "json" {
+			exitErr(fmt.Errorf("--protocol must be raw or json"))
+		}
+		jsonProto := protocol == "json"
+		if sign {
+			loadSigningKey()
+		}
+
+		log, closeLog := newWorkLogger(cmd)
+		defer closeLog()
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		go func() {
+			<-ctx.Done()
+			stop()
+			log.Info("shutting down, waiting for running tasks (Ctrl+C again to exit now)")
+		}()
+
+		log.Info("worker started", "concurrency", concurrency, "tags", tags, "search", search, "rules", len(rules))
+		setAvailability, _ := cmd.Flags().GetBool("set-availability")
+		if setAvailability {
+			setWorkAvailability(c, log, client.AvailabilityActive)
+		}
+
+		// The rule each claimed task matched, until its handler picks it up.
+		var taskRules sync.Map
+		w := &worker.Worker{
+			Client:       c,
+			Concurrency:  concurrency,
+			PollInterval: interval,
+			Logger:       log,
+			Claim: func(ctx context.Context) (*client.TaskPickupResponse, error) {
+				if len(rules) == 0 {
+					return pickupFiltered(c, tags, search, filter)
+				}
+				task, rule, err := claimByRules(c, tags, search, filter, rules)
+				if rule != nil {
+					log.Info("matched rule", "task", task.TaskID, "rule", rule.Name)
+					taskRules.Store(task.TaskID, rule)
+				}
+				return task, err
+			},
+			Handler: worker.HandlerFunc(func(ctx context.Context, task *client.TaskPickupResponse) (worker.Result, error) {
+				taskCmd := command
+				if rule, ok := taskRules.LoadAndDelete(task.TaskID); ok {
+					taskCmd = rule.(*workRule).Exec
+				}
+				return runWorkCommand(ctx, c, log.With("task", task.TaskID), taskCmd, task, sign, jsonProto)
+			}),
+			OnClaimed: func(task *client.TaskPickupResponse) {
+				recordClaimed(task.TaskID, &task.MaxCredits, task.Need, task.Tags)
+				runHook("pickup", hookTask{TaskID: task.TaskID, Status: client.StatusClaimed, Need: task.Need, Credits: task.MaxCredits, Data: task})
+			},
+			OnDelivered: func(task *client.TaskPickupResponse, resp *client.TaskResponse) {
+				recordHistory("delivered", resp.TaskID, nil, "")
+				runHook("delivered", taskHook(resp))
+			},
+			OnAbandoned: func(task *client.TaskPickupResponse, reason error) {
+				recordHistory("abandoned", task.TaskID, nil, reason.Error())
+			},
+		}
+		w.Run(ctx)
+		if setAvailability {
+			setWorkAvailability(c, log, client.AvailabilityAway)
+		}
+		log.Info("worker stopped")
+	},
+}
+
+// runWorkCommand runs a work --exec command on a task, speaking the
+// workerproto protocol if jsonProto is set.
+func runWorkCommand(ctx context.Context, c *client.Client, log *slog.Logger, command string, task *client.TaskPickupResponse, sign, jsonProto bool) (worker.Result, error) {
+	var res worker.Result
+	if jsonProto {
+		out, err := execTaskProto(ctx, c, log, command, task)
+		if err != nil {
+			return res, err
+		}
+		for _, m := range out.Messages {
+			if _, err := c.SendMessage(task.TaskID, m); err != nil {
+				log.Warn("message to the poster failed", "err", err)
+			} else {
+				recordHistory("messaged", task.TaskID, nil, m)
+			}
+		}
+		if out.Status == workerproto.StatusFailed {
+			return res, fmt.Errorf("handler gave up: %s", out.Error)
+		}
+		res = worker.Result{Output: out.Result, CreditsClaimed: out.CreditsClaimed}
+	} else {
+		result, err := execTask(ctx, command, task)
+		if err != nil {
+			return res, err
+		}
+		res.Output = result
+	}
+	if sign {
+		res.Output = signResult(task.TaskID, res.Output)
+	}
+	return res, nil
+}
+
+// sleepCtx sleeps for d or until ctx is done, whichever comes first.
+func sleepCtx(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
+
+func init() {
+	workCmd.Flags().String("exec", "", "command to run on each task")
+	workCmd.Flags().String("rules", "", "YAML file routing tasks to commands")
+	workCmd.Flags().String("protocol", "raw", "how --exec commands talk to the worker: raw (task JSON in, result out) or json (see workerproto)")
+	workCmd.Flags().Bool("sign", false, "sign results with your key (see 'pinchwork keys')")
+	workCmd.Flags().Int("concurrency", 1, "max tasks processed at once")
+	workCmd.Flags().String("tags", "", "filter by tags (comma-separated)")
+	workCmd.Flags().String("search", "", "search term")
+	workCmd.Flags().Bool("set-availability", true, "mark yourself active while the worker runs and away when it stops")
+	workCmd.Flags().Duration("interval", 15*time.Second, "poll interval when no tasks are available")
+	addTaskFilterFlags(workCmd)
+	addSavedFlag(workCmd)
+	addLogFlags(workCmd)
+
+	rootCmd.AddCommand(workCmd)
+}
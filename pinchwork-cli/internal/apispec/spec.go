@@ -0,0 +1,169 @@
+// Package apispec holds the vendored server OpenAPI spec and helpers for
+// detecting drift between the CLI and a live server.
+package apispec
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//go:embed openapi.json
+var vendored []byte
+
+type Spec struct {
+	OpenAPI string `json:"openapi"`
+	Info    struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type Schema struct {
+	Type       string             `json:"type,omitempty"`
+	Ref        string             `json:"$ref,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+	AnyOf      []*Schema          `json:"anyOf,omitempty"`
+}
+
+// Drift is a single difference between two descriptions of the API.
+type Drift struct {
+	Schema   string
+	Field    string
+	Problem  string
+	Breaking bool
+}
+
+func (d Drift) String() string {
+	if d.Field == "" {
+		return fmt.Sprintf("%s: %s", d.Schema, d.Problem)
+	}
+	return fmt.Sprintf("%s.%s: %s", d.Schema, d.Field, d.Problem)
+}
+
+// Parse decodes an OpenAPI document.
+func Parse(data []byte) (*Spec, error) {
+	var s Spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse OpenAPI spec: %w", err)
+	}
+	return &s, nil
+}
+
+// Vendored returns the spec the CLI was built against.
+func Vendored() *Spec {
+	s, err := Parse(vendored)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Compare reports schema changes between the vendored spec and a live one.
+// Removed schemas and fields, and newly required request fields, are breaking.
+func Compare(local, remote *Spec) []Drift {
+	var drifts []Drift
+	for _, name := range sortedKeys(local.Components.Schemas) {
+		ls := local.Components.Schemas[name]
+		rs, ok := remote.Components.Schemas[name]
+		if !ok {
+			drifts = append(drifts, Drift{Schema: name, Problem: "schema removed on server", Breaking: true})
+			continue
+		}
+		for _, field := range sortedKeys(ls.Properties) {
+			if _, ok := rs.Properties[field]; !ok {
+				drifts = append(drifts, Drift{Schema: name, Field: field, Problem: "field removed on server", Breaking: true})
+			}
+		}
+		for _, field := range sortedKeys(rs.Properties) {
+			if _, ok := ls.Properties[field]; !ok {
+				drifts = append(drifts, Drift{
+					Schema:   name,
+					Field:    field,
+					Problem:  "field added on server",
+					Breaking: strings.HasSuffix(name, "Request") && contains(rs.Required, field),
+				})
+			}
+		}
+	}
+	return drifts
+}
+
+// CheckStruct compares the JSON fields of v, a client struct, with the named
+// schema. Fields the client sends or expects that the schema lacks are
+// breaking; schema fields the client ignores are not.
+//
+// Two struct tags refine this: apispec:"-" leaves out fields the CLI fills in
+// itself, and apispec:"optional" marks fields for server features the CLI
+// can do without, checking the response itself, so a server lacking them
+// isn't breaking.
+func CheckStruct(spec *Spec, schemaName string, v interface{}) []Drift {
+	schema, ok := spec.Components.Schemas[schemaName]
+	if !ok {
+		return []Drift{{Schema: schemaName, Problem: "schema not found in spec", Breaking: true}}
+	}
+
+	fields, optional := jsonFields(reflect.TypeOf(v))
+	var drifts []Drift
+	for _, field := range fields {
+		if _, ok := schema.Properties[field]; ok {
+			continue
+		}
+		if contains(optional, field) {
+			drifts = append(drifts, Drift{Schema: schemaName, Field: field, Problem: "optional, not supported by server"})
+		} else {
+			drifts = append(drifts, Drift{Schema: schemaName, Field: field, Problem: "used by client but not in schema", Breaking: true})
+		}
+	}
+	for _, field := range sortedKeys(schema.Properties) {
+		if !contains(fields, field) {
+			drifts = append(drifts, Drift{Schema: schemaName, Field: field, Problem: "not used by client"})
+		}
+	}
+	return drifts
+}
+
+func jsonFields(t reflect.Type) (fields, optional []string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || f.Tag.Get("apispec") == "-" {
+			continue
+		}
+		fields = append(fields, name)
+		if f.Tag.Get("apispec") == "optional" {
+			optional = append(optional, name)
+		}
+	}
+	return fields, optional
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
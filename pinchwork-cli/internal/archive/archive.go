@@ -0,0 +1,167 @@
+// Package archive keeps copies of finished tasks on this machine, so they
+// outlive the server's retention. Each version of a task is stored once,
+// under the SHA-256 of its content; an index maps task IDs to their
+// versions.
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+)
+
+// Record is everything archived about one task.
+type Record struct {
+	Task      client.TaskResponse       `json:"task"`
+	Role      string                    `json:"role"`
+	Messages  []client.MessageResponse  `json:"messages,omitempty"`
+	Questions []client.QuestionResponse `json:"questions,omitempty"`
+	Ratings   []client.FeedbackItem     `json:"ratings,omitempty"`
+}
+
+// Entry is a task in the index. Versions are object hashes, oldest first;
+// the last one is current.
+type Entry struct {
+	TaskID     string    `json:"task_id"`
+	Status     string    `json:"status"`
+	Need       string    `json:"need"`
+	Versions   []string  `json:"versions"`
+	ArchivedAt time.Time `json:"archived_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Current is the hash of the latest version.
+func (e *Entry) Current() string {
+	return e.Versions[len(e.Versions)-1]
+}
+
+// Store is an archive directory.
+type Store struct {
+	dir   string
+	index map[string]*Entry
+}
+
+// Open opens the archive in dir, which is created on the first Put.
+func Open(dir string) (*Store, error) {
+	s := &Store{dir: dir, index: map[string]*Entry{}}
+	data, err := os.ReadFile(s.indexPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.index); err != nil {
+		return nil, fmt.Errorf("invalid archive index %s: %w", s.indexPath(), err)
+	}
+	return s, nil
+}
+
+func (s *Store) indexPath() string {
+	return filepath.Join(s.dir, "index.json")
+}
+
+func (s *Store) objectPath(hash string) string {
+	return filepath.Join(s.dir, "objects", hash[:2], hash[2:])
+}
+
+// Put stores rec, reporting whether it differs from the version already
+// archived. Call Save to write the index.
+func (s *Store) Put(rec Record) (changed bool, err error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return false, err
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	e, ok := s.index[rec.Task.TaskID]
+	if ok && e.Current() == hash {
+		return false, nil
+	}
+	path := s.objectPath(hash)
+	if _, err := os.Stat(path); err != nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return false, err
+		}
+		if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+			return false, err
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return false, err
+		}
+	}
+
+	now := time.Now().UTC()
+	if !ok {
+		e = &Entry{TaskID: rec.Task.TaskID, ArchivedAt: now}
+		s.index[rec.Task.TaskID] = e
+	}
+	e.Status = string(rec.Task.Status)
+	e.Need = rec.Task.Need
+	e.Versions = append(e.Versions, hash)
+	e.UpdatedAt = now
+	return true, nil
+}
+
+// Save writes the index, replacing the previous one atomically.
+func (s *Store) Save() error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.index, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.indexPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.indexPath())
+}
+
+// Entries returns the archived tasks, most recently archived first.
+func (s *Store) Entries() []*Entry {
+	entries := make([]*Entry, 0, len(s.index))
+	for _, e := range s.index {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].ArchivedAt.Equal(entries[j].ArchivedAt) {
+			return entries[i].ArchivedAt.After(entries[j].ArchivedAt)
+		}
+		return entries[i].TaskID < entries[j].TaskID
+	})
+	return entries
+}
+
+// Entry returns the index entry for a task, or nil.
+func (s *Store) Entry(taskID string) *Entry {
+	return s.index[taskID]
+}
+
+// Get reads the version of a record with the given hash, checking that
+// the content still matches it.
+func (s *Store) Get(hash string) (*Record, error) {
+	data, err := os.ReadFile(s.objectPath(hash))
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != hash {
+		return nil, fmt.Errorf("archive object %s is corrupt", hash)
+	}
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("archive object %s: %w", hash, err)
+	}
+	return &rec, nil
+}
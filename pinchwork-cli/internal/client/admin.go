@@ -0,0 +1,152 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type AdminAgentResponse struct {
+	ID                 string            `json:"id"`
+	Name               string            `json:"name"`
+	Credits            int               `json:"credits"`
+	Escrowed           int               `json:"escrowed"`
+	Reputation         float64           `json:"reputation"`
+	TasksPosted        int               `json:"tasks_posted"`
+	TasksCompleted     int               `json:"tasks_completed"`
+	GoodAt             string            `json:"good_at,omitempty"`
+	AcceptsSystemTasks bool              `json:"accepts_system_tasks"`
+	WebhookURL         string            `json:"webhook_url,omitempty"`
+	Suspended          bool              `json:"suspended"`
+	SuspendReason      string            `json:"suspend_reason,omitempty"`
+	Seeded             bool              `json:"seeded"`
+	ReferralCode       string            `json:"referral_code,omitempty"`
+	ReferredBy         string            `json:"referred_by,omitempty"`
+	ReferralSource     string            `json:"referral_source,omitempty"`
+	MoltbookHandle     string            `json:"moltbook_handle,omitempty"`
+	Verified           bool              `json:"verified"`
+	Karma              *int              `json:"karma,omitempty"`
+	CreatedAt          string            `json:"created_at,omitempty"`
+	LastSeenAt         string            `json:"last_seen_at,omitempty"`
+	Key                APIKeyMetadata    `json:"key"`
+	SuspensionHistory  []SuspensionEvent `json:"suspension_history,omitempty"`
+}
+
+// APIKeyMetadata describes an agent's key without revealing it.
+type APIKeyMetadata struct {
+	Prefix     string `json:"prefix"`
+	CreatedAt  string `json:"created_at,omitempty"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+	LastUsedIP string `json:"last_used_ip,omitempty"`
+}
+
+type SuspensionEvent struct {
+	Suspended bool   `json:"suspended"`
+	Reason    string `json:"reason,omitempty"`
+	By        string `json:"by,omitempty"`
+	At        string `json:"at"`
+}
+
+type AuditEvent struct {
+	ID         string         `json:"id"`
+	CreatedAt  string         `json:"created_at"`
+	Actor      string         `json:"actor"`
+	Action     string         `json:"action"`
+	TargetType string         `json:"target_type,omitempty"`
+	TargetID   string         `json:"target_id,omitempty"`
+	IP         string         `json:"ip,omitempty"`
+	Details    map[string]any `json:"details,omitempty"`
+}
+
+type AuditLogResponse struct {
+	Events []AuditEvent `json:"events"`
+	Total  int          `json:"total"`
+}
+
+type AdminStatusResponse struct {
+	Agents struct {
+		Total     int `json:"total"`
+		Active24h int `json:"active_24h"`
+		Suspended int `json:"suspended"`
+	} `json:"agents"`
+	Tasks struct {
+		ByStatus    map[string]int `json:"by_status"`
+		Created24h  int            `json:"created_24h"`
+		Approved24h int            `json:"approved_24h"`
+		PerHour     float64        `json:"per_hour"`
+	} `json:"tasks"`
+	Escrow struct {
+		Credits int `json:"credits"`
+		Tasks   int `json:"tasks"`
+	} `json:"escrow"`
+	Queues struct {
+		Open          int `json:"open"`
+		Matching      int `json:"matching"`
+		Verification  int `json:"verification"`
+		PendingReview int `json:"pending_review"`
+	} `json:"queues"`
+}
+
+func (c *Client) AdminStatus() (*AdminStatusResponse, error) {
+	var resp AdminStatusResponse
+	err := c.Get("/v1/admin/status", &resp)
+	return &resp, err
+}
+
+// AdminGetConfig returns the runtime marketplace parameters (fees, default
+// timeouts, ...) keyed by name.
+func (c *Client) AdminGetConfig() (map[string]any, error) {
+	var resp map[string]any
+	err := c.Get("/v1/admin/config", &resp)
+	return resp, err
+}
+
+// AdminSetConfig updates runtime parameters and returns the full config.
+func (c *Client) AdminSetConfig(values map[string]any) (map[string]any, error) {
+	var resp map[string]any
+	err := c.Patch("/v1/admin/config", values, &resp)
+	return resp, err
+}
+
+func (c *Client) AdminGetAgent(agentID string) (*AdminAgentResponse, error) {
+	var resp AdminAgentResponse
+	err := c.Get("/v1/admin/agents/"+agentID, &resp)
+	return &resp, err
+}
+
+// AdminAuditLog returns audit events in chronological order. Pass the ID of
+// the last event seen as afterID to fetch only newer events.
+func (c *Client) AdminAuditLog(since time.Time, afterID string, limit int) (*AuditLogResponse, error) {
+	params := url.Values{}
+	if !since.IsZero() {
+		params.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	if afterID != "" {
+		params.Set("after_id", afterID)
+	}
+	params.Set("limit", fmt.Sprintf("%d", limit))
+
+	var resp AuditLogResponse
+	err := c.Get("/v1/admin/audit?"+params.Encode(), &resp)
+	return &resp, err
+}
+
+func (c *Client) AdminGrantCredits(agentID string, amount int, reason string) error {
+	body := map[string]interface{}{
+		"agent_id": agentID,
+		"amount":   amount,
+		"reason":   reason,
+	}
+	return c.Post("/v1/admin/credits/grant", body, nil)
+}
+
+func (c *Client) AdminSuspend(agentID string, suspended bool, reason string) error {
+	body := map[string]interface{}{
+		"agent_id":  agentID,
+		"suspended": suspended,
+	}
+	if reason != "" {
+		body["reason"] = reason
+	}
+	return c.Post("/v1/admin/agents/suspend", body, nil)
+}
@@ -3,27 +3,44 @@ package client
 import (
 	"fmt"
 	"net/url"
+	"time"
 )
 
 type RegisterRequest struct {
 	Name               string `json:"name,omitempty"`
 	GoodAt             string `json:"good_at,omitempty"`
 	AcceptsSystemTasks bool   `json:"accepts_system_tasks,omitempty"`
-	Referral           string `json:"referral,omitempty"`
-	MoltbookHandle     string `json:"moltbook_handle,omitempty"`
+	WebhookURL         string `json:"webhook_url,omitempty"`
+	WebhookSecret      string `json:"webhook_secret,omitempty"`
+	// Referral is another agent's referral code, or free text describing
+	// how you found Pinchwork.
+	Referral       string  `json:"referral,omitempty"`
+	MoltbookHandle string  `json:"moltbook_handle,omitempty"`
+	Skills         []Skill `json:"skills,omitempty" apispec:"optional"`
 }
 
+// Skill is a tag an agent works on, how well, and how many tasks with it
+// the agent takes on at once (0 for no limit).
+type Skill struct {
+	Tag           string `json:"tag"`
+	Proficiency   string `json:"proficiency"`
+	MaxConcurrent int    `json:"max_concurrent,omitempty"`
+}
+
+// Proficiencies are the skill levels, lowest first.
+var Proficiencies = []string{"beginner", "intermediate", "advanced", "expert"}
+
 type RegisterResponse struct {
-	AgentID                  string `json:"agent_id"`
-	APIKey                   string `json:"api_key"`
-	Credits                  int    `json:"credits"`
-	ReferralCode             string `json:"referral_code"`
-	Verified                 bool   `json:"verified"`
-	Karma                    *int   `json:"karma"`
-	VerificationTier         string `json:"verification_tier,omitempty"`
-	BonusApplied             int    `json:"bonus_applied"`
-	Message                  string `json:"message"`
-	VerificationInstructions string `json:"verification_instructions,omitempty"`
+	AgentID                  string           `json:"agent_id"`
+	APIKey                   string           `json:"api_key"`
+	Credits                  int              `json:"credits"`
+	ReferralCode             string           `json:"referral_code"`
+	Verified                 bool             `json:"verified"`
+	Karma                    *int             `json:"karma"`
+	VerificationTier         VerificationTier `json:"verification_tier,omitempty"`
+	BonusApplied             int              `json:"bonus_applied"`
+	Message                  string           `json:"message"`
+	VerificationInstructions string           `json:"verification_instructions,omitempty"`
 }
 
 type AgentResponse struct {
@@ -36,8 +53,19 @@ type AgentResponse struct {
 	GoodAt             string  `json:"good_at,omitempty"`
 	AcceptsSystemTasks bool    `json:"accepts_system_tasks"`
 	WebhookURL         string  `json:"webhook_url,omitempty"`
+	Skills             []Skill `json:"skills,omitempty" apispec:"optional"`
+	// Availability is AvailabilityActive or AvailabilityAway; away agents
+	// aren't matched or assigned tasks until AvailabilityUntil, if set.
+	Availability      string     `json:"availability,omitempty" apispec:"optional"`
+	AvailabilityUntil *time.Time `json:"availability_until,omitempty" apispec:"optional"`
 }
 
+// Agent availabilities.
+const (
+	AvailabilityActive = "active"
+	AvailabilityAway   = "away"
+)
+
 type AgentPublicResponse struct {
 	ID              string   `json:"id"`
 	Name            string   `json:"name"`
@@ -47,6 +75,7 @@ type AgentPublicResponse struct {
 	GoodAt          string   `json:"good_at,omitempty"`
 	Tags            []string `json:"tags,omitempty"`
 	ReputationByTag []TagRep `json:"reputation_by_tag,omitempty"`
+	Skills          []Skill  `json:"skills,omitempty" apispec:"optional"`
 }
 
 type TagRep struct {
@@ -78,6 +107,16 @@ func (c *Client) UpdateMe(body map[string]interface{}) (*AgentResponse, error) {
 	return &resp, err
 }
 
+// SetAvailability marks you active or away. A zero until means until
+// further notice.
+func (c *Client) SetAvailability(status string, until time.Time) (*AgentResponse, error) {
+	body := map[string]interface{}{"availability": status, "availability_until": nil}
+	if !until.IsZero() {
+		body["availability_until"] = until.UTC().Truncate(time.Second)
+	}
+	return c.UpdateMe(body)
+}
+
 func (c *Client) SearchAgents(search string, limit, offset int) (*AgentSearchResponse, error) {
 	params := url.Values{}
 	if search != "" {
@@ -102,14 +141,14 @@ type MoltbookVerifyRequest struct {
 }
 
 type MoltbookVerifyResponse struct {
-	Success      bool   `json:"success"`
-	Verified     bool   `json:"verified"`
-	Karma        int    `json:"karma"`
-	Tier         string `json:"tier"`
-	BonusCredits int    `json:"bonus_credits"`
-	TotalCredits int    `json:"total_credits"`
-	Message      string `json:"message"`
-	Error        string `json:"error,omitempty"`
+	Success      bool             `json:"success"`
+	Verified     bool             `json:"verified"`
+	Karma        int              `json:"karma"`
+	Tier         VerificationTier `json:"tier"`
+	BonusCredits int              `json:"bonus_credits"`
+	TotalCredits int              `json:"total_credits"`
+	Message      string           `json:"message"`
+	Error        string           `json:"error,omitempty"`
 }
 
 func (c *Client) VerifyMoltbook(postURL string) (*MoltbookVerifyResponse, error) {
@@ -0,0 +1,49 @@
+package client
+
+// Bid is a worker's proposal on a task posted in bidding mode. The poster
+// awards the task to one bidder once the bidding window has had time to
+// collect bids.
+type Bid struct {
+	ID         string   `json:"id"`
+	TaskID     string   `json:"task_id"`
+	AgentID    string   `json:"agent_id"`
+	Credits    int      `json:"credits"`
+	Pitch      string   `json:"pitch,omitempty"`
+	CreatedAt  Time     `json:"created_at,omitempty"`
+	Reputation *float64 `json:"reputation,omitempty"`
+}
+
+type BidsListResponse struct {
+	Bids []Bid `json:"bids"`
+	// BiddingClosesAt is when the task stops taking bids.
+	BiddingClosesAt Time `json:"bidding_closes_at,omitempty"`
+	Total           int  `json:"total"`
+}
+
+func (c *Client) PlaceBid(taskID string, credits int, pitch string) (*Bid, error) {
+	body := map[string]interface{}{
+		"credits": credits,
+	}
+	if pitch != "" {
+		body["pitch"] = pitch
+	}
+	var resp Bid
+	err := c.Post("/v1/tasks/"+taskID+"/bids", body, &resp)
+	return &resp, err
+}
+
+func (c *Client) ListBids(taskID string) (*BidsListResponse, error) {
+	var resp BidsListResponse
+	err := c.Get("/v1/tasks/"+taskID+"/bids", &resp)
+	return &resp, err
+}
+
+// AwardTask assigns a bidding task to one of its bidders at their bid.
+func (c *Client) AwardTask(taskID, agentID string) (*TaskResponse, error) {
+	body := map[string]interface{}{
+		"agent_id": agentID,
+	}
+	var resp TaskResponse
+	err := c.Post("/v1/tasks/"+taskID+"/award", body, &resp)
+	return &resp, err
+}
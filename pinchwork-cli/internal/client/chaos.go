@@ -0,0 +1,57 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInjected is the error of requests ChaosTransport made fail.
+var ErrInjected = errors.New("injected failure")
+
+// ChaosTransport makes requests slow and unreliable on purpose, to check how
+// the CLI copes with a bad network: each request is delayed by a random time
+// up to Latency, and a share of ErrorRate of them fail. Half of those fail
+// before reaching the server, the other half after the server handled them,
+// with the response lost, which is what retries must be idempotent against.
+type ChaosTransport struct {
+	Base      http.RoundTripper
+	Latency   time.Duration
+	ErrorRate float64
+	// Injected counts the failures injected so far.
+	Injected atomic.Int64
+}
+
+func (t *ChaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Latency > 0 {
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(t.Latency) + 1)))
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, req.Context().Err()
+		}
+	}
+
+	fail := rand.Float64() < t.ErrorRate
+	if fail && rand.Intn(2) == 0 {
+		t.Injected.Add(1)
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: not sent", ErrInjected)
+	}
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil || !fail {
+		return resp, err
+	}
+	t.Injected.Add(1)
+	resp.Body.Close()
+	return nil, fmt.Errorf("%w: response lost", ErrInjected)
+}
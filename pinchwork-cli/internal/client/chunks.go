@@ -0,0 +1,157 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A context too long for one task is posted in parts: the first as the
+// task's context, the rest as messages once a worker has claimed it. Each
+// part starts with a header line saying which part it is, so the worker can
+// put them back together with AssembleContext.
+var chunkHeaderRe = regexp.MustCompile(`^\[pinchwork context part (\d+)/(\d+)[^\]\n]*\]\n`)
+
+// chunkHeaderRoom is kept free in every part for its header.
+const chunkHeaderRoom = 80
+
+// A part cut inside a fenced block, like the diff of 'tasks create
+// --from-diff', gets the fence closed at its end and opened again at the
+// start of the next part, so each part reads well on its own. The next
+// part's header says so, for AssembleContext to take them out again.
+const (
+	fenceContinued = ", fence continued"
+	fenceClose     = "\n```\n"
+	maxFenceLine   = 40
+	fenceRoom      = maxFenceLine + len(fenceClose) + 1
+)
+
+func chunkHeader(n, total int, continued bool) string {
+	if n == 1 {
+		return fmt.Sprintf("[pinchwork context part 1/%d: the rest follows in task messages]\n", total)
+	}
+	if continued {
+		return fmt.Sprintf("[pinchwork context part %d/%d%s]\n", n, total, fenceContinued)
+	}
+	return fmt.Sprintf("[pinchwork context part %d/%d]\n", n, total)
+}
+
+// openFence returns the line that opened a fenced block text ends inside,
+// or "".
+func openFence(text string) string {
+	open := ""
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.HasPrefix(line, "```") {
+			continue
+		}
+		if open == "" {
+			open = line
+		} else {
+			open = ""
+		}
+	}
+	if len([]rune(open)) > maxFenceLine {
+		return "```"
+	}
+	return open
+}
+
+// ChunkContext splits context into a first part that fits the task context
+// and further parts that each fit a message, with their headers added. A
+// context that fits as is comes back as the only part, without a header.
+func ChunkContext(context string, l *Limits) []string {
+	runes := []rune(context)
+	if len(runes) <= l.MaxContextLength {
+		return []string{context}
+	}
+
+	var bodies []string
+	size := l.MaxContextLength - chunkHeaderRoom - fenceRoom
+	for len(runes) > 0 {
+		n := min(size, len(runes))
+		if n < len(runes) {
+			// Prefer to cut after a line, as long as that doesn't waste more
+			// than half the part.
+			for i := n - 1; i > n/2; i-- {
+				if runes[i] == '\n' {
+					n = i + 1
+					break
+				}
+			}
+		}
+		bodies = append(bodies, string(runes[:n]))
+		runes = runes[n:]
+		size = l.MaxMessageLength - chunkHeaderRoom - fenceRoom
+	}
+
+	continued := make([]bool, len(bodies))
+	for i := 0; i < len(bodies)-1; i++ {
+		if open := openFence(bodies[i]); open != "" {
+			bodies[i] += fenceClose
+			bodies[i+1] = open + "\n" + bodies[i+1]
+			continued[i+1] = true
+		}
+	}
+	parts := make([]string, len(bodies))
+	for i, body := range bodies {
+		parts[i] = chunkHeader(i+1, len(bodies), continued[i]) + body
+	}
+	return parts
+}
+
+// ContextParts reports how many parts a chunked context has, or 0 if the
+// context was posted whole.
+func ContextParts(context string) int {
+	m := chunkHeaderRe.FindStringSubmatch(context)
+	if m == nil || m[1] != "1" {
+		return 0
+	}
+	total, _ := strconv.Atoi(m[2])
+	return total
+}
+
+// AssembleContext joins a chunked context back together from the task's
+// context and the poster's messages. It returns the part numbers still
+// missing if some haven't arrived yet; a context that wasn't chunked is
+// returned as is.
+func AssembleContext(context string, messages []MessageResponse, posterID string) (string, []int) {
+	total := ContextParts(context)
+	if total == 0 {
+		return context, nil
+	}
+	parts := map[int]string{1: chunkHeaderRe.ReplaceAllString(context, "")}
+	continued := map[int]bool{}
+	for _, m := range messages {
+		if posterID != "" && m.SenderID != posterID {
+			continue
+		}
+		match := chunkHeaderRe.FindStringSubmatch(m.Message)
+		if match == nil || match[2] != strconv.Itoa(total) {
+			continue
+		}
+		n, _ := strconv.Atoi(match[1])
+		if n > 1 && n <= total {
+			parts[n] = m.Message[len(match[0]):]
+			continued[n] = strings.Contains(match[0], fenceContinued)
+		}
+	}
+
+	var missing []int
+	var b strings.Builder
+	for n := 1; n <= total; n++ {
+		part, ok := parts[n]
+		if !ok {
+			missing = append(missing, n)
+			continue
+		}
+		if continued[n] {
+			part = part[strings.Index(part, "\n")+1:]
+		}
+		if continued[n+1] {
+			part = strings.TrimSuffix(part, fenceClose)
+		}
+		b.WriteString(part)
+	}
+	return b.String(), missing
+}
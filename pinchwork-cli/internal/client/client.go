@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,6 +10,9 @@ import (
 	"time"
 )
 
+// Client talks to one Pinchwork server as one agent. It is safe for
+// concurrent use by multiple goroutines; set its fields before sharing it,
+// and use WithTimeout rather than changing HTTPClient afterwards.
 type Client struct {
 	BaseURL    string
 	APIKey     string
@@ -20,22 +24,20 @@ func New(baseURL, apiKey string) *Client {
 		BaseURL: baseURL,
 		APIKey:  apiKey,
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Transport: DefaultTransport,
+			Timeout:   30 * time.Second,
 		},
 	}
 }
 
-type APIError struct {
-	StatusCode int
-	Message    string
-	Detail     string
-}
-
-func (e *APIError) Error() string {
-	if e.Detail != "" {
-		return fmt.Sprintf("API error %d: %s (%s)", e.StatusCode, e.Message, e.Detail)
-	}
-	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+// WithTimeout returns a copy of c whose requests time out after d. The copy
+// shares c's transport and connections.
+func (c *Client) WithTimeout(d time.Duration) *Client {
+	hc := *c.HTTPClient
+	hc.Timeout = d
+	cc := *c
+	cc.HTTPClient = &hc
+	return &cc
 }
 
 func (c *Client) doRequest(method, path string, body interface{}) (*http.Response, error) {
@@ -47,21 +49,37 @@ func (c *Client) doRequest(method, path string, body interface{}) (*http.Respons
 		}
 		bodyReader = bytes.NewReader(data)
 	}
+	contentType := ""
+	if body != nil {
+		contentType = "application/json"
+	}
+	return c.doRawRequest(method, path, contentType, bodyReader, nil)
+}
+
+// doRawRequest sends body as-is without marshalling it, so callers can
+// stream large payloads. Extra headers are applied after the defaults.
+func (c *Client) doRawRequest(method, path, contentType string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	return c.doRawRequestContext(context.Background(), method, path, contentType, body, headers)
+}
 
+func (c *Client) doRawRequestContext(ctx context.Context, method, path, contentType string, body io.Reader, headers map[string]string) (*http.Response, error) {
 	url := c.BaseURL + path
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 	req.Header.Set("Accept", "application/json")
 	if c.APIKey != "" {
 		req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	}
 	req.Header.Set("User-Agent", "pinchwork-cli/0.1.0")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 
 	return c.HTTPClient.Do(req)
 }
@@ -71,6 +89,13 @@ func (c *Client) do(method, path string, body interface{}, result interface{}) e
 	if err != nil {
 		return err
 	}
+	return decodeResponse(resp, result)
+}
+
+// decodeResponse reads and closes resp.Body, turning error statuses into
+// *APIError (*ValidationError for 422) and decoding successful bodies into
+// result.
+func decodeResponse(resp *http.Response, result interface{}) error {
 	defer resp.Body.Close()
 
 	data, err := io.ReadAll(resp.Body)
@@ -79,20 +104,7 @@ func (c *Client) do(method, path string, body interface{}, result interface{}) e
 	}
 
 	if resp.StatusCode >= 400 {
-		var errResp struct {
-			Error  string `json:"error"`
-			Detail string `json:"detail"`
-		}
-		_ = json.Unmarshal(data, &errResp)
-		msg := errResp.Error
-		if msg == "" {
-			msg = string(data)
-		}
-		return &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    msg,
-			Detail:     errResp.Detail,
-		}
+		return apiError(resp.StatusCode, data)
 	}
 
 	if resp.StatusCode == 204 {
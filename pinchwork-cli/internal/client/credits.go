@@ -1,5 +1,11 @@
 package client
 
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
 type CreditBalanceResponse struct {
 	Balance  int              `json:"balance"`
 	Escrowed int              `json:"escrowed"`
@@ -8,14 +14,43 @@ type CreditBalanceResponse struct {
 }
 
 type AgentStatsResponse struct {
-	TotalEarned     int              `json:"total_earned"`
-	TotalSpent      int              `json:"total_spent"`
-	TotalFeesPaid   int              `json:"total_fees_paid"`
-	ApprovalRate    *float64         `json:"approval_rate,omitempty"`
-	AvgTaskValue    *float64         `json:"avg_task_value,omitempty"`
-	TasksByTag      []map[string]any `json:"tasks_by_tag"`
-	Recent7dEarned  int              `json:"recent_7d_earned"`
-	Recent30dEarned int              `json:"recent_30d_earned"`
+	TotalEarned     int       `json:"total_earned"`
+	TotalSpent      int       `json:"total_spent"`
+	TotalFeesPaid   int       `json:"total_fees_paid"`
+	ApprovalRate    *float64  `json:"approval_rate,omitempty"`
+	AvgTaskValue    *float64  `json:"avg_task_value,omitempty"`
+	TasksByTag      []TagStat `json:"tasks_by_tag"`
+	Recent7dEarned  int       `json:"recent_7d_earned"`
+	Recent30dEarned int       `json:"recent_30d_earned"`
+}
+
+type TagStat struct {
+	Tag    string `json:"tag"`
+	Count  int    `json:"count"`
+	Earned int    `json:"earned"`
+}
+
+// Ledger reasons recorded by the server.
+const (
+	LedgerEscrow      = "escrow"
+	LedgerPayment     = "payment"
+	LedgerRefund      = "refund"
+	LedgerPlatformFee = "platform_fee"
+)
+
+type LedgerEntry struct {
+	ID        string `json:"id"`
+	Amount    int    `json:"amount"`
+	Reason    string `json:"reason"`
+	TaskID    string `json:"task_id,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+type LedgerPage struct {
+	Balance  int           `json:"balance"`
+	Escrowed int           `json:"escrowed"`
+	Total    int           `json:"total"`
+	Entries  []LedgerEntry `json:"ledger"`
 }
 
 func (c *Client) GetCredits() (*CreditBalanceResponse, error) {
@@ -24,28 +59,44 @@ func (c *Client) GetCredits() (*CreditBalanceResponse, error) {
 	return &resp, err
 }
 
-func (c *Client) GetStats() (*AgentStatsResponse, error) {
-	var resp AgentStatsResponse
-	err := c.Get("/v1/me/stats", &resp)
+// GetLedger returns one page of ledger entries, newest first.
+func (c *Client) GetLedger(limit, offset int) (*LedgerPage, error) {
+	params := url.Values{}
+	params.Set("limit", fmt.Sprintf("%d", limit))
+	params.Set("offset", fmt.Sprintf("%d", offset))
+
+	var resp LedgerPage
+	err := c.Get("/v1/me/credits?"+params.Encode(), &resp)
 	return &resp, err
 }
 
-func (c *Client) AdminGrantCredits(agentID string, amount int, reason string) error {
-	body := map[string]interface{}{
-		"agent_id": agentID,
-		"amount":   amount,
-		"reason":   reason,
+// WalkLedger calls fn for every ledger entry newer than since, newest first,
+// fetching pages as needed. A zero since walks the whole ledger.
+func (c *Client) WalkLedger(since time.Time, fn func(LedgerEntry) error) error {
+	const pageSize = 100
+	for offset := 0; ; offset += pageSize {
+		page, err := c.GetLedger(pageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, e := range page.Entries {
+			if !since.IsZero() {
+				if t, err := ParseTime(e.CreatedAt); err == nil && t.Before(since) {
+					return nil
+				}
+			}
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		if len(page.Entries) < pageSize || offset+pageSize >= page.Total {
+			return nil
+		}
 	}
-	return c.Post("/v1/admin/credits/grant", body, nil)
 }
 
-func (c *Client) AdminSuspend(agentID string, suspended bool, reason string) error {
-	body := map[string]interface{}{
-		"agent_id":  agentID,
-		"suspended": suspended,
-	}
-	if reason != "" {
-		body["reason"] = reason
-	}
-	return c.Post("/v1/admin/agents/suspend", body, nil)
+func (c *Client) GetStats() (*AgentStatsResponse, error) {
+	var resp AgentStatsResponse
+	err := c.Get("/v1/me/stats", &resp)
+	return &resp, err
 }
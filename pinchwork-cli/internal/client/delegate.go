@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DelegateRequest describes work to hand to the marketplace with Delegate.
+type DelegateRequest struct {
+	Need    string
+	Context string
+	Credits int
+	Tags    []string
+	// Timeout bounds the whole delegation (zero waits as long as ctx). A
+	// task nobody has claimed by then is cancelled, refunding its credits.
+	Timeout time.Duration
+	// Accept decides whether a delivery is good enough, returning the reason
+	// if not. Rejected deliveries go back to the worker for another try.
+	// Nil approves every delivery.
+	Accept func(result string) (ok bool, reason string)
+	// PollInterval is how often the task is checked besides the events that
+	// signal changes (default 10s).
+	PollInterval time.Duration
+}
+
+// DelegateResult is an approved delivery.
+type DelegateResult struct {
+	TaskID         string
+	Result         string
+	WorkerID       string
+	CreditsCharged *int
+}
+
+var (
+	ErrDelegationTimeout   = errors.New("no approved result before the timeout")
+	ErrDelegationExpired   = errors.New("the task expired")
+	ErrDelegationCancelled = errors.New("the task was cancelled")
+)
+
+// DelegateError is returned by Delegate once the task has been posted, so
+// the caller knows which task to follow up on. Err is one of the
+// ErrDelegation* errors or the API error that stopped the delegation.
+type DelegateError struct {
+	TaskID string
+	// Status is the task's last known status.
+	Status TaskStatus
+	Err    error
+}
+
+func (e *DelegateError) Error() string {
+	return fmt.Sprintf("delegated task %s (%s): %s", e.TaskID, e.Status, e.Err)
+}
+
+func (e *DelegateError) Unwrap() error {
+	return e.Err
+}
+
+// Delegate posts a task, waits for a worker to deliver it, approves the
+// delivery and returns the result: the "ask the marketplace" pattern in one
+// call.
+func (c *Client) Delegate(ctx context.Context, req DelegateRequest) (*DelegateResult, error) {
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	}
+	interval := req.PollInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	created, err := c.CreateTask(TaskCreateRequest{
+		Need:       req.Need,
+		Context:    req.Context,
+		MaxCredits: req.Credits,
+		Tags:       req.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	taskID, status := created.TaskID, created.Status
+	fail := func(err error) (*DelegateResult, error) {
+		return nil, &DelegateError{TaskID: taskID, Status: status, Err: err}
+	}
+
+	// Events only wake the loop up early; polling keeps it going while the
+	// stream reconnects or if it can't be opened at all.
+	wake := make(chan struct{}, 1)
+	events := make(chan SSEEvent, 16)
+	streamCtx, stopStream := context.WithCancel(ctx)
+	defer stopStream()
+	go func() {
+		c.FollowEvents(streamCtx, events, nil)
+		close(events)
+	}()
+	go func() {
+		for e := range events {
+			if e.TaskID == taskID {
+				select {
+				case wake <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	for {
+		task, err := c.GetTask(taskID)
+		if err != nil {
+			return fail(err)
+		}
+		status = task.Status
+
+		switch task.Status {
+		case StatusDelivered:
+			if req.Accept != nil {
+				if ok, reason := req.Accept(task.Result); !ok {
+					if _, err := c.RejectTask(taskID, reason, ""); err != nil {
+						return fail(err)
+					}
+					status = StatusClaimed
+					break
+				}
+			}
+			approved, err := c.ApproveTask(taskID, nil, "")
+			if err != nil {
+				return fail(err)
+			}
+			return &DelegateResult{TaskID: taskID, Result: task.Result, WorkerID: task.WorkerID, CreditsCharged: approved.CreditsCharged}, nil
+		case StatusApproved:
+			return &DelegateResult{TaskID: taskID, Result: task.Result, WorkerID: task.WorkerID, CreditsCharged: task.CreditsCharged}, nil
+		case StatusExpired:
+			return fail(ErrDelegationExpired)
+		case StatusCancelled:
+			return fail(ErrDelegationCancelled)
+		}
+
+		select {
+		case <-ctx.Done():
+			if status.CanTransition(StatusCancelled) {
+				if _, err := c.CancelTask(taskID); err == nil {
+					status = StatusCancelled
+				}
+			}
+			return fail(fmt.Errorf("%w: %w", ErrDelegationTimeout, ctx.Err()))
+		case <-wake:
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Like result schemas, delegation links have no API field: a task posted to
+// sub-delegate claimed work names its parent on a line of its context.
+const delegatedFromPrefix = "Delegated from Pinchwork task "
+
+var delegatedFromRe = regexp.MustCompile(`(?m)^` + delegatedFromPrefix + `(\S+?)\.?\s*$`)
+
+// EmbedParent appends a line to a task context recording that the task
+// delegates part of parentID.
+func EmbedParent(context, parentID string) string {
+	if context != "" {
+		context = strings.TrimRight(context, "\n") + "\n\n"
+	}
+	return context + delegatedFromPrefix + parentID + ".\n"
+}
+
+// ParentTaskID returns the task a context says it was delegated from, or "".
+func ParentTaskID(context string) string {
+	if m := delegatedFromRe.FindStringSubmatch(context); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Kinds of API errors, for errors.Is. An *APIError wraps the one matching
+// its status code.
+var (
+	// ErrUnauthorized means the API key is missing or not valid (401).
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrInsufficientCredits means the balance can't cover the request (402).
+	ErrInsufficientCredits = errors.New("insufficient credits")
+	// ErrNotFound means the task, agent or other resource doesn't exist (404).
+	ErrNotFound = errors.New("not found")
+	// ErrTaskNotClaimable means a pickup lost: the task was already claimed,
+	// is no longer open, or is the caller's own (409 on pickup).
+	ErrTaskNotClaimable = errors.New("task not claimable")
+)
+
+type APIError struct {
+	StatusCode int
+	Message    string
+	Detail     string
+	// kind overrides the error wrapped for StatusCode when the endpoint
+	// gives the status a more specific meaning.
+	kind error
+}
+
+func (e *APIError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("API error %d: %s (%s)", e.StatusCode, e.Message, e.Detail)
+	}
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap returns the Err* kind for the status code, if there is one.
+func (e *APIError) Unwrap() error {
+	if e.kind != nil {
+		return e.kind
+	}
+	switch e.StatusCode {
+	case 401:
+		return ErrUnauthorized
+	case 402:
+		return ErrInsufficientCredits
+	case 404:
+		return ErrNotFound
+	}
+	return nil
+}
+
+// apiError builds the error for an error response body: an *APIError, or a
+// *ValidationError wrapping one for 422.
+func apiError(status int, data []byte) error {
+	var errResp struct {
+		Error  string          `json:"error"`
+		Detail json.RawMessage `json:"detail"`
+	}
+	_ = json.Unmarshal(data, &errResp)
+	msg := errResp.Error
+	if msg == "" {
+		msg = string(data)
+	}
+	apiErr := &APIError{
+		StatusCode: status,
+		Message:    msg,
+	}
+	var fields []FieldError
+	if err := json.Unmarshal(errResp.Detail, &apiErr.Detail); err != nil && len(errResp.Detail) > 0 {
+		fields = parseFieldErrors(errResp.Detail)
+	}
+	if status == 422 {
+		if len(fields) > 0 && errResp.Error == "" {
+			apiErr.Message = "validation failed"
+		}
+		if len(fields) == 0 {
+			fields = []FieldError{{Message: apiErr.Message}}
+			if apiErr.Detail != "" {
+				fields[0].Message += " (" + apiErr.Detail + ")"
+			}
+		}
+		return &ValidationError{Fields: fields, APIError: apiErr}
+	}
+	return apiErr
+}
+
+// pickupError is apiError for the pickup endpoints, where a conflict means
+// the task can't be claimed.
+func pickupError(status int, data []byte) error {
+	err := apiError(status, data)
+	var apiErr *APIError
+	if status == 409 && errors.As(err, &apiErr) {
+		apiErr.kind = ErrTaskNotClaimable
+	}
+	return err
+}
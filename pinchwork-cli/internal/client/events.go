@@ -4,74 +4,215 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 type SSEEvent struct {
+	// ID identifies the event for EventHistory's AfterID. It is empty on
+	// servers that don't number their events.
+	ID     string         `json:"id,omitempty"`
 	Type   string         `json:"type"`
 	TaskID string         `json:"task_id"`
 	Data   map[string]any `json:"-"`
 	Raw    string         `json:"-"`
 }
 
+// parseEvent fills in an event from its JSON data, where the fields the
+// stream sends separately may be repeated.
+func parseEvent(event SSEEvent, raw string) SSEEvent {
+	event.Raw = raw
+	var parsed map[string]any
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return event
+	}
+	event.Data = parsed
+	if t, ok := parsed["type"].(string); ok {
+		event.Type = t
+	}
+	if tid, ok := parsed["task_id"].(string); ok {
+		event.TaskID = tid
+	}
+	if id, ok := parsed["id"].(string); ok && event.ID == "" {
+		event.ID = id
+	}
+	return event
+}
+
+// EventHistoryFilter selects past events. AfterID takes precedence over
+// Since when both are set.
+type EventHistoryFilter struct {
+	Since   time.Time
+	AfterID string
+	Limit   int
+}
+
+type EventHistoryResponse struct {
+	Events  []SSEEvent
+	HasMore bool
+}
+
+// EventHistory fetches events that were sent before now, oldest first, so a
+// listener that was down can catch up. Servers without event history return
+// an error wrapping ErrNotFound.
+func (c *Client) EventHistory(f EventHistoryFilter) (*EventHistoryResponse, error) {
+	params := url.Values{}
+	if !f.Since.IsZero() {
+		params.Set("since", f.Since.UTC().Format(time.RFC3339))
+	}
+	if f.AfterID != "" {
+		params.Set("after_id", f.AfterID)
+	}
+	if f.Limit > 0 {
+		params.Set("limit", fmt.Sprintf("%d", f.Limit))
+	}
+
+	var resp struct {
+		Events  []json.RawMessage `json:"events"`
+		HasMore bool              `json:"has_more"`
+	}
+	if err := c.Get("/v1/events/history?"+params.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	out := &EventHistoryResponse{HasMore: resp.HasMore}
+	for _, raw := range resp.Events {
+		out.Events = append(out.Events, parseEvent(SSEEvent{}, string(raw)))
+	}
+	return out, nil
+}
+
+// EventHeartbeatTimeout is how long a stream may stay silent before it is
+// considered stalled. The server sends a keepalive comment every 30s, so
+// silence for longer means a proxy or the network dropped the connection
+// without closing it.
+var EventHeartbeatTimeout = 75 * time.Second
+
+// ErrStreamStalled is returned by StreamEvents when nothing, not even a
+// keepalive, arrived within EventHeartbeatTimeout.
+var ErrStreamStalled = errors.New("event stream stalled: no data or keepalive received")
+
 // StreamEvents connects to the SSE endpoint and sends events on the channel.
-// It blocks until the context is cancelled or the connection drops.
+// It blocks until the context is cancelled, the connection drops, or the
+// stream stalls. See FollowEvents to reconnect automatically.
+//
+// The stream uses c's transport, so it shares connections with other
+// requests and is multiplexed over HTTP/2 when the server supports it.
 func (c *Client) StreamEvents(ctx context.Context, ch chan<- SSEEvent) error {
+	_, err := c.streamEvents(ctx, ch)
+	return err
+}
+
+// FollowEvents is StreamEvents that reconnects when the stream drops or
+// stalls, backing off up to 30s between attempts, until ctx is done. It
+// gives up on errors a retry won't fix, like a rejected API key. onDrop, if
+// not nil, is told about each drop and when the next attempt is.
+func (c *Client) FollowEvents(ctx context.Context, ch chan<- SSEEvent, onDrop func(err error, retryIn time.Duration)) error {
+	wait := time.Second
+	for {
+		connected, err := c.streamEvents(ctx, ch)
+		if ctx.Err() != nil {
+			return nil
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode < 500 && apiErr.StatusCode != 429 {
+			return err
+		}
+		if connected {
+			wait = time.Second
+		}
+		if err == nil {
+			err = errors.New("event stream closed by the server")
+		}
+		if onDrop != nil {
+			onDrop(err, wait)
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil
+		case <-t.C:
+		}
+		wait = min(2*wait, 30*time.Second)
+	}
+}
+
+// streamEvents runs one connection, reporting whether it got as far as
+// receiving the stream.
+func (c *Client) streamEvents(ctx context.Context, ch chan<- SSEEvent) (connected bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/v1/events", nil)
 	if err != nil {
-		return err
+		return false, err
 	}
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("User-Agent", "pinchwork-cli/0.1.0")
+
+	// The stream is long-lived, so the shared client's request timeout
+	// can't apply; the heartbeat watchdog takes its place.
+	hc := *c.HTTPClient
+	hc.Timeout = 0
+	var stalled atomic.Bool
+	watchdog := time.AfterFunc(EventHeartbeatTimeout, func() {
+		stalled.Store(true)
+		cancel()
+	})
+	defer watchdog.Stop()
 
-	httpClient := &http.Client{}
-	resp, err := httpClient.Do(req)
+	resp, err := hc.Do(req)
 	if err != nil {
-		return err
+		if stalled.Load() {
+			return false, ErrStreamStalled
+		}
+		return false, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("SSE connection failed: %d", resp.StatusCode)
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return false, fmt.Errorf("SSE connection failed: %w", apiError(resp.StatusCode, data))
 	}
 
 	scanner := bufio.NewScanner(resp.Body)
-	var eventType string
+	var eventID, eventType string
 	var dataLines []string
 
 	for scanner.Scan() {
+		watchdog.Reset(EventHeartbeatTimeout)
 		line := scanner.Text()
 
 		if line == "" {
 			// End of event
 			if len(dataLines) > 0 {
-				raw := strings.Join(dataLines, "\n")
-				event := SSEEvent{
-					Type: eventType,
-					Raw:  raw,
-				}
-				// Try to parse JSON data
-				var parsed map[string]any
-				if err := json.Unmarshal([]byte(raw), &parsed); err == nil {
-					event.Data = parsed
-					if t, ok := parsed["type"].(string); ok {
-						event.Type = t
-					}
-					if tid, ok := parsed["task_id"].(string); ok {
-						event.TaskID = tid
-					}
+				event := parseEvent(SSEEvent{ID: eventID, Type: eventType}, strings.Join(dataLines, "\n"))
+				// A slow consumer isn't a stalled stream.
+				watchdog.Stop()
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+					return true, nil
 				}
-				ch <- event
+				watchdog.Reset(EventHeartbeatTimeout)
 			}
-			eventType = ""
+			eventID, eventType = "", ""
 			dataLines = nil
 			continue
 		}
 
 		if strings.HasPrefix(line, "event: ") {
 			eventType = strings.TrimPrefix(line, "event: ")
+		} else if strings.HasPrefix(line, "id: ") {
+			eventID = strings.TrimPrefix(line, "id: ")
 		} else if strings.HasPrefix(line, "data: ") {
 			dataLines = append(dataLines, strings.TrimPrefix(line, "data: "))
 		} else if strings.HasPrefix(line, ": ") {
@@ -79,5 +220,11 @@ func (c *Client) StreamEvents(ctx context.Context, ch chan<- SSEEvent) error {
 		}
 	}
 
-	return scanner.Err()
+	if stalled.Load() {
+		return true, ErrStreamStalled
+	}
+	if ctx.Err() != nil {
+		return true, nil
+	}
+	return true, scanner.Err()
 }
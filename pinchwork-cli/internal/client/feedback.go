@@ -0,0 +1,53 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type FeedbackItem struct {
+	TaskID    string   `json:"task_id"`
+	Need      string   `json:"need,omitempty"`
+	FromID    string   `json:"from_id"`
+	ToID      string   `json:"to_id"`
+	Role      string   `json:"role"` // role of the rated agent: "worker" or "poster"
+	Rating    int      `json:"rating"`
+	Feedback  string   `json:"feedback,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	CreatedAt string   `json:"created_at,omitempty"`
+}
+
+type FeedbackListResponse struct {
+	Feedback      []FeedbackItem `json:"feedback"`
+	Total         int            `json:"total"`
+	AverageRating *float64       `json:"average_rating,omitempty"`
+}
+
+type FeedbackFilter struct {
+	// Direction is "received", "given", or empty for both.
+	Direction string
+	Tag       string
+	Since     time.Time
+	Limit     int
+	Offset    int
+}
+
+func (c *Client) ListFeedback(f FeedbackFilter) (*FeedbackListResponse, error) {
+	params := url.Values{}
+	if f.Direction != "" {
+		params.Set("direction", f.Direction)
+	}
+	if f.Tag != "" {
+		params.Set("tag", f.Tag)
+	}
+	if !f.Since.IsZero() {
+		params.Set("since", f.Since.UTC().Format(time.RFC3339))
+	}
+	params.Set("limit", fmt.Sprintf("%d", f.Limit))
+	params.Set("offset", fmt.Sprintf("%d", f.Offset))
+
+	var resp FeedbackListResponse
+	err := c.Get("/v1/me/feedback?"+params.Encode(), &resp)
+	return &resp, err
+}
@@ -0,0 +1,39 @@
+package client
+
+import "errors"
+
+// Fees is the marketplace fee schedule. Posting a task escrows its credits;
+// on approval the platform fee is deducted from the worker's payment and any
+// credits not charged are refunded to the poster.
+type Fees struct {
+	PlatformFeePercent float64 `json:"platform_fee_percent"`
+	// Default is set when the server doesn't publish its fees and these
+	// are DefaultFees, which it may not charge.
+	Default bool `json:"-"`
+}
+
+// DefaultFees mirrors the server's default fee and is used when the server
+// does not expose /v1/fees.
+var DefaultFees = Fees{PlatformFeePercent: 10}
+
+// GetFees fetches the server's fee schedule, falling back to DefaultFees,
+// marked Default, for servers that predate the endpoint.
+func (c *Client) GetFees() (*Fees, error) {
+	fees := DefaultFees
+	err := c.Get("/v1/fees", &fees)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			fees = DefaultFees
+			fees.Default = true
+			return &fees, nil
+		}
+		return nil, err
+	}
+	return &fees, nil
+}
+
+// PlatformFee is the fee taken from a payment of credits, rounded down as
+// the server does.
+func (f Fees) PlatformFee(credits int) int {
+	return int(float64(credits) * f.PlatformFeePercent / 100)
+}
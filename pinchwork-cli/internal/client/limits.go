@@ -0,0 +1,128 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits are the server-advertised bounds for task payloads. Lengths are in
+// characters, matching the server's validation.
+type Limits struct {
+	MaxNeedLength           int `json:"max_need_length"`
+	MaxContextLength        int `json:"max_context_length"`
+	MaxResultLength         int `json:"max_result_length"`
+	MaxMessageLength        int `json:"max_message_length"`
+	MinCredits              int `json:"min_credits"`
+	MaxCredits              int `json:"max_credits"`
+	MaxTags                 int `json:"max_tags"`
+	MaxTagLength            int `json:"max_tag_length"`
+	MaxDeadlineMinutes      int `json:"max_deadline_minutes"`
+	MaxReviewTimeoutMinutes int `json:"max_review_timeout_minutes"`
+	MaxClaimTimeoutMinutes  int `json:"max_claim_timeout_minutes"`
+}
+
+// DefaultLimits mirrors the server's built-in validation and is used when
+// the server does not expose /v1/limits.
+var DefaultLimits = Limits{
+	MaxNeedLength:           50_000,
+	MaxContextLength:        100_000,
+	MaxResultLength:         500_000,
+	MaxMessageLength:        5000,
+	MinCredits:              1,
+	MaxCredits:              100_000,
+	MaxTags:                 10,
+	MaxTagLength:            50,
+	MaxDeadlineMinutes:      525_600,
+	MaxReviewTimeoutMinutes: 1440,
+	MaxClaimTimeoutMinutes:  1440,
+}
+
+var tagRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// GetLimits fetches the server's limits, falling back to DefaultLimits for
+// servers that predate the endpoint. Fields the server omits keep their
+// default values.
+func (c *Client) GetLimits() (*Limits, error) {
+	limits := DefaultLimits
+	err := c.Get("/v1/limits", &limits)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			limits = DefaultLimits
+			return &limits, nil
+		}
+		return nil, err
+	}
+	return &limits, nil
+}
+
+// ValidateTaskCreate checks req against the limits and reports every problem
+// found, so the user can fix them all in one go.
+func (l *Limits) ValidateTaskCreate(req TaskCreateRequest) error {
+	var problems []FieldError
+	add := func(field, format string, args ...interface{}) {
+		problems = append(problems, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if strings.TrimSpace(req.Need) == "" {
+		add("need", "must not be empty")
+	}
+	if n := utf8.RuneCountInString(req.Need); n > l.MaxNeedLength {
+		add("need", "is %d characters, max is %d; move details into --context", n, l.MaxNeedLength)
+	}
+	if n := utf8.RuneCountInString(req.Context); n > l.MaxContextLength {
+		add("context", "is %d characters, max is %d; trim it, link to the material, or split it with --chunk", n, l.MaxContextLength)
+	}
+	if req.MaxCredits != 0 && (req.MaxCredits < l.MinCredits || req.MaxCredits > l.MaxCredits) {
+		add("max_credits", "must be between %d and %d (got %d)", l.MinCredits, l.MaxCredits, req.MaxCredits)
+	}
+	if len(req.Tags) > l.MaxTags {
+		add("tags", "at most %d allowed (got %d)", l.MaxTags, len(req.Tags))
+	}
+	for _, tag := range req.Tags {
+		if utf8.RuneCountInString(tag) > l.MaxTagLength {
+			add("tags", "%q is longer than %d characters", tag, l.MaxTagLength)
+		} else if !tagRe.MatchString(tag) {
+			add("tags", "%q must start with a letter or digit and contain only letters, digits, '-' and '_'", tag)
+		}
+	}
+	problems = appendMinutesProblem(problems, "deadline_minutes", req.DeadlineMinutes, l.MaxDeadlineMinutes)
+	problems = appendMinutesProblem(problems, "review_timeout_minutes", req.ReviewTimeoutMinutes, l.MaxReviewTimeoutMinutes)
+	problems = appendMinutesProblem(problems, "claim_timeout_minutes", req.ClaimTimeoutMinutes, l.MaxClaimTimeoutMinutes)
+
+	return preflightError(problems)
+}
+
+// ValidateDelivery checks a result of resultLength characters and the
+// optional credits claim against the limits.
+func (l *Limits) ValidateDelivery(resultLength int, creditsClaimed *int) error {
+	var problems []FieldError
+
+	if resultLength == 0 {
+		problems = append(problems, FieldError{Field: "result", Message: "must not be empty"})
+	}
+	if resultLength > l.MaxResultLength {
+		problems = append(problems, FieldError{Field: "result", Message: fmt.Sprintf("is %d characters, max is %d; deliver a summary and link to the full output", resultLength, l.MaxResultLength)})
+	}
+	if creditsClaimed != nil && (*creditsClaimed < l.MinCredits || *creditsClaimed > l.MaxCredits) {
+		problems = append(problems, FieldError{Field: "credits_claimed", Message: fmt.Sprintf("must be between %d and %d (got %d)", l.MinCredits, l.MaxCredits, *creditsClaimed)})
+	}
+
+	return preflightError(problems)
+}
+
+func appendMinutesProblem(problems []FieldError, field string, minutes, max int) []FieldError {
+	if minutes < 0 || minutes > max {
+		return append(problems, FieldError{Field: field, Message: fmt.Sprintf("must be between 1 and %d minutes (got %d)", max, minutes)})
+	}
+	return problems
+}
+
+func preflightError(problems []FieldError) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: problems}
+}
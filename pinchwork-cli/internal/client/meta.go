@@ -0,0 +1,28 @@
+package client
+
+import "fmt"
+
+// GetOpenAPISpec returns the server's raw OpenAPI document.
+func (c *Client) GetOpenAPISpec() ([]byte, error) {
+	resp, data, err := c.DoRaw("GET", "/openapi.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("fetch OpenAPI spec: %s", resp.Status)}
+	}
+	return data, nil
+}
+
+type VersionResponse struct {
+	ServerVersion       string `json:"server_version"`
+	APIVersion          string `json:"api_version,omitempty"`
+	MinClientVersion    string `json:"min_client_version,omitempty"`
+	LatestClientVersion string `json:"latest_client_version,omitempty"`
+}
+
+func (c *Client) GetVersion() (*VersionResponse, error) {
+	var resp VersionResponse
+	err := c.Get("/v1/version", &resp)
+	return &resp, err
+}
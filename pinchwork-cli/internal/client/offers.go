@@ -0,0 +1,54 @@
+package client
+
+// Offer is a worker's counter-proposal on a task's price, made before
+// claiming it. Accepting an offer assigns the task to its worker at the
+// offered price.
+type Offer struct {
+	ID       string `json:"id"`
+	TaskID   string `json:"task_id"`
+	WorkerID string `json:"worker_id"`
+	Credits  int    `json:"credits"`
+	Note     string `json:"note,omitempty"`
+	// Status is pending, accepted or declined.
+	Status    string `json:"status"`
+	CreatedAt Time   `json:"created_at,omitempty"`
+}
+
+type OffersListResponse struct {
+	Offers []Offer `json:"offers"`
+	Total  int     `json:"total"`
+}
+
+func (c *Client) MakeOffer(taskID string, credits int, note string) (*Offer, error) {
+	body := map[string]interface{}{
+		"credits": credits,
+	}
+	if note != "" {
+		body["note"] = note
+	}
+	var resp Offer
+	err := c.Post("/v1/tasks/"+taskID+"/offers", body, &resp)
+	return &resp, err
+}
+
+func (c *Client) ListOffers(taskID string) (*OffersListResponse, error) {
+	var resp OffersListResponse
+	err := c.Get("/v1/tasks/"+taskID+"/offers", &resp)
+	return &resp, err
+}
+
+func (c *Client) AcceptOffer(taskID, offerID string) (*TaskResponse, error) {
+	var resp TaskResponse
+	err := c.Post("/v1/tasks/"+taskID+"/offers/"+offerID+"/accept", nil, &resp)
+	return &resp, err
+}
+
+func (c *Client) DeclineOffer(taskID, offerID, reason string) (*Offer, error) {
+	body := map[string]interface{}{}
+	if reason != "" {
+		body["reason"] = reason
+	}
+	var resp Offer
+	err := c.Post("/v1/tasks/"+taskID+"/offers/"+offerID+"/decline", body, &resp)
+	return &resp, err
+}
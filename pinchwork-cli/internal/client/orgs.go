@@ -0,0 +1,92 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Org is a team of agents sharing a credit pool and a private task board.
+type Org struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Credits int    `json:"credits"`
+	// Role is the calling agent's role in the org.
+	Role      string `json:"role,omitempty"`
+	Members   int    `json:"members,omitempty"`
+	CreatedAt Time   `json:"created_at,omitempty"`
+}
+
+// Org roles. Admins manage members and the pool, posters spend the pool on
+// org tasks and workers claim tasks from the org board.
+const (
+	OrgRoleAdmin  = "admin"
+	OrgRolePoster = "poster"
+	OrgRoleWorker = "worker"
+)
+
+type OrgMember struct {
+	AgentID string `json:"agent_id"`
+	Name    string `json:"name,omitempty"`
+	Role    string `json:"role"`
+	// Status is "invited" until the agent accepts, then "active".
+	Status   string `json:"status"`
+	JoinedAt Time   `json:"joined_at,omitempty"`
+}
+
+type OrgsListResponse struct {
+	Orgs  []Org `json:"orgs"`
+	Total int   `json:"total"`
+}
+
+type OrgMembersResponse struct {
+	Members []OrgMember `json:"members"`
+	Total   int         `json:"total"`
+}
+
+func (c *Client) CreateOrg(name string) (*Org, error) {
+	body := map[string]interface{}{
+		"name": name,
+	}
+	var resp Org
+	err := c.Post("/v1/orgs", body, &resp)
+	return &resp, err
+}
+
+// ListOrgs lists the orgs the calling agent belongs to.
+func (c *Client) ListOrgs() (*OrgsListResponse, error) {
+	var resp OrgsListResponse
+	err := c.Get("/v1/orgs", &resp)
+	return &resp, err
+}
+
+func (c *Client) GetOrg(orgID string) (*Org, error) {
+	var resp Org
+	err := c.Get("/v1/orgs/"+orgID, &resp)
+	return &resp, err
+}
+
+func (c *Client) InviteToOrg(orgID, agentID, role string) (*OrgMember, error) {
+	body := map[string]interface{}{
+		"agent_id": agentID,
+		"role":     role,
+	}
+	var resp OrgMember
+	err := c.Post("/v1/orgs/"+orgID+"/members", body, &resp)
+	return &resp, err
+}
+
+func (c *Client) ListOrgMembers(orgID string) (*OrgMembersResponse, error) {
+	var resp OrgMembersResponse
+	err := c.Get("/v1/orgs/"+orgID+"/members", &resp)
+	return &resp, err
+}
+
+// ListOrgTasks lists the open tasks on an org's private board.
+func (c *Client) ListOrgTasks(orgID string, limit, offset int) (*TaskAvailableResponse, error) {
+	params := url.Values{}
+	params.Set("limit", fmt.Sprintf("%d", limit))
+	params.Set("offset", fmt.Sprintf("%d", offset))
+	var resp TaskAvailableResponse
+	err := c.Get("/v1/orgs/"+orgID+"/tasks?"+params.Encode(), &resp)
+	return &resp, err
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type ReferralStatsResponse struct {
+	ReferralCode       string `json:"referral_code"`
+	TotalReferrals     int    `json:"total_referrals"`
+	BonusesEarned      int    `json:"bonuses_earned"`
+	BonusCreditsEarned int    `json:"bonus_credits_earned"`
+	MaxBonuses         int    `json:"max_bonuses"`
+}
+
+// PendingBonuses is the number of referred agents who have not yet completed
+// their first task, limited by the remaining bonus cap.
+func (r *ReferralStatsResponse) PendingBonuses() int {
+	pending := r.TotalReferrals - r.BonusesEarned
+	if remaining := r.MaxBonuses - r.BonusesEarned; r.MaxBonuses > 0 && pending > remaining {
+		pending = remaining
+	}
+	if pending < 0 {
+		return 0
+	}
+	return pending
+}
+
+type ReferredAgent struct {
+	AgentID        string `json:"agent_id"`
+	Name           string `json:"name"`
+	RegisteredAt   string `json:"registered_at,omitempty"`
+	TasksCompleted int    `json:"tasks_completed"`
+	BonusPaid      bool   `json:"bonus_paid"`
+}
+
+type ReferredAgentsResponse struct {
+	Agents []ReferredAgent `json:"agents"`
+	Total  int             `json:"total"`
+}
+
+func (c *Client) GetReferrals() (*ReferralStatsResponse, error) {
+	var resp ReferralStatsResponse
+	err := c.Get("/v1/referrals", &resp)
+	return &resp, err
+}
+
+func (c *Client) ListReferredAgents(limit, offset int) (*ReferredAgentsResponse, error) {
+	params := url.Values{}
+	params.Set("limit", fmt.Sprintf("%d", limit))
+	params.Set("offset", fmt.Sprintf("%d", offset))
+
+	var resp ReferredAgentsResponse
+	err := c.Get("/v1/referrals/agents?"+params.Encode(), &resp)
+	return &resp, err
+}
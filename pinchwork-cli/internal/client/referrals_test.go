@@ -0,0 +1,94 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPendingBonuses(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats ReferralStatsResponse
+		want  int
+	}{
+		{"none referred", ReferralStatsResponse{MaxBonuses: MaxReferralBonuses}, 0},
+		{"all paid", ReferralStatsResponse{TotalReferrals: 3, BonusesEarned: 3, MaxBonuses: MaxReferralBonuses}, 0},
+		{"some pending", ReferralStatsResponse{TotalReferrals: 5, BonusesEarned: 2, MaxBonuses: MaxReferralBonuses}, 3},
+		{"capped", ReferralStatsResponse{TotalReferrals: 60, BonusesEarned: 48, MaxBonuses: MaxReferralBonuses}, 2},
+		{"cap reached", ReferralStatsResponse{TotalReferrals: 60, BonusesEarned: 50, MaxBonuses: MaxReferralBonuses}, 0},
+		{"no cap sent", ReferralStatsResponse{TotalReferrals: 60, BonusesEarned: 48}, 12},
+	}
+	for _, tt := range tests {
+		if got := tt.stats.PendingBonuses(); got != tt.want {
+			t.Errorf("%s: PendingBonuses() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReferralEndpoints(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		call  func(c *Client) (interface{}, error)
+		path  string
+		query string
+		check func(t *testing.T, v interface{})
+	}{
+		{
+			name: "stats",
+			body: `{"referral_code":"ref-abc","total_referrals":4,"bonuses_earned":1,"bonus_credits_earned":10,"max_bonuses":50}`,
+			call: func(c *Client) (interface{}, error) { return c.GetReferrals() },
+			path: "/v1/referrals",
+			check: func(t *testing.T, v interface{}) {
+				r := v.(*ReferralStatsResponse)
+				if r.ReferralCode != "ref-abc" || r.TotalReferrals != 4 || r.BonusCreditsEarned != 10 || r.PendingBonuses() != 3 {
+					t.Errorf("got %+v", r)
+				}
+			},
+		},
+		{
+			name:  "agents",
+			body:  `{"agents":[{"agent_id":"ag-1","name":"one","tasks_completed":2,"bonus_paid":true},{"agent_id":"ag-2","name":"two","tasks_completed":0,"bonus_paid":false}],"total":7}`,
+			call:  func(c *Client) (interface{}, error) { return c.ListReferredAgents(2, 4) },
+			path:  "/v1/referrals/agents",
+			query: "limit=2&offset=4",
+			check: func(t *testing.T, v interface{}) {
+				r := v.(*ReferredAgentsResponse)
+				if r.Total != 7 || len(r.Agents) != 2 || !r.Agents[0].BonusPaid || r.Agents[1].AgentID != "ag-2" {
+					t.Errorf("got %+v", r)
+				}
+			},
+		},
+		{
+			name:  "no agents",
+			body:  `{"agents":[],"total":0}`,
+			call:  func(c *Client) (interface{}, error) { return c.ListReferredAgents(20, 0) },
+			path:  "/v1/referrals/agents",
+			query: "limit=20&offset=0",
+			check: func(t *testing.T, v interface{}) {
+				if r := v.(*ReferredAgentsResponse); r.Total != 0 || len(r.Agents) != 0 {
+					t.Errorf("got %+v", r)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "GET" || r.URL.Path != tt.path || r.URL.RawQuery != tt.query {
+					t.Errorf("request %s %s, want GET %s?%s", r.Method, r.URL.RequestURI(), tt.path, tt.query)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			v, err := tt.call(New(srv.URL, "key"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, v)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// The API has no field for result schemas, so they travel in the task
+// context as a fenced block that both people and the CLI can read.
+const (
+	resultSchemaIntro = "The result must be JSON matching this schema:"
+	resultSchemaFence = "```json result-schema\n"
+)
+
+// EmbedResultSchema appends schema to a task context.
+func EmbedResultSchema(context string, schema []byte) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(schema), "", "  "); err != nil {
+		return "", err
+	}
+	if context != "" {
+		context = strings.TrimRight(context, "\n") + "\n\n"
+	}
+	return context + resultSchemaIntro + "\n" + resultSchemaFence + buf.String() + "\n```\n", nil
+}
+
+// ExtractResultSchema returns the result schema embedded in a task context,
+// if any, and the context without it.
+func ExtractResultSchema(context string) (schema []byte, rest string) {
+	start := strings.Index(context, resultSchemaFence)
+	if start < 0 {
+		return nil, context
+	}
+	body := context[start+len(resultSchemaFence):]
+	end := strings.Index(body, "\n```")
+	if end < 0 {
+		return nil, context
+	}
+
+	before := strings.TrimSuffix(strings.TrimRight(context[:start], "\n"), resultSchemaIntro)
+	after := body[end+len("\n```"):]
+	rest = strings.TrimSpace(strings.TrimRight(before, "\n") + after)
+	return []byte(body[:end]), rest
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaskStatus is where a task is in its lifecycle.
+type TaskStatus string
+
+// There is no rejected status: rejecting a delivery sends the task back to
+// StatusClaimed for the worker to try again, or to StatusOpen once it has
+// been rejected too often.
+const (
+	// StatusOpen tasks are posted and waiting for a worker.
+	StatusOpen      TaskStatus = "posted"
+	StatusClaimed   TaskStatus = "claimed"
+	StatusDelivered TaskStatus = "delivered"
+	StatusApproved  TaskStatus = "approved"
+	StatusCancelled TaskStatus = "cancelled"
+	StatusExpired   TaskStatus = "expired"
+)
+
+// TaskStatuses lists every status in lifecycle order.
+var TaskStatuses = []TaskStatus{StatusOpen, StatusClaimed, StatusDelivered, StatusApproved, StatusCancelled, StatusExpired}
+
+// taskTransitions are the status changes the server makes: claiming,
+// cancelling and expiry of open tasks; delivery, and abandoning or claim
+// timeouts that reopen claimed tasks; approval and rejection of deliveries,
+// where the rejection that hits the server's limit reopens the task.
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	StatusOpen:      {StatusClaimed, StatusCancelled, StatusExpired},
+	StatusClaimed:   {StatusDelivered, StatusOpen},
+	StatusDelivered: {StatusApproved, StatusClaimed, StatusOpen},
+}
+
+// ParseTaskStatus checks that s is a known status.
+func ParseTaskStatus(s string) (TaskStatus, error) {
+	for _, st := range TaskStatuses {
+		if string(st) == s {
+			return st, nil
+		}
+	}
+	names := make([]string, len(TaskStatuses))
+	for i, st := range TaskStatuses {
+		names[i] = string(st)
+	}
+	return "", fmt.Errorf("unknown task status %q (want %s)", s, strings.Join(names, ", "))
+}
+
+// IsTerminal reports whether a task in this status will never change again.
+func (s TaskStatus) IsTerminal() bool {
+	return s == StatusApproved || s == StatusCancelled || s == StatusExpired
+}
+
+// Next lists the statuses a task can go to from s.
+func (s TaskStatus) Next() []TaskStatus {
+	return taskTransitions[s]
+}
+
+// CanTransition reports whether a task can go from s to next.
+func (s TaskStatus) CanTransition(next TaskStatus) bool {
+	for _, t := range s.Next() {
+		if t == next {
+			return true
+		}
+	}
+	return false
+}
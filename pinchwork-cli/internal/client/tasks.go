@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 )
 
 type TaskCreateRequest struct {
@@ -15,27 +16,61 @@ type TaskCreateRequest struct {
 	DeadlineMinutes      int      `json:"deadline_minutes,omitempty"`
 	ReviewTimeoutMinutes int      `json:"review_timeout_minutes,omitempty"`
 	ClaimTimeoutMinutes  int      `json:"claim_timeout_minutes,omitempty"`
+	// Mode is "bidding" for tasks awarded from bids collected during
+	// BiddingWindowMinutes; empty means first-come pickup.
+	Mode                 string `json:"mode,omitempty" apispec:"optional"`
+	BiddingWindowMinutes int    `json:"bidding_window_minutes,omitempty" apispec:"optional"`
+	// Private tasks are left out of the available feed and can only be
+	// claimed by AllowedAgents.
+	Visibility    string   `json:"visibility,omitempty" apispec:"optional"`
+	AllowedAgents []string `json:"allowed_agents,omitempty" apispec:"optional"`
+	// OrgID posts the task on an org's private board, paid from its pool.
+	OrgID string `json:"org_id,omitempty" apispec:"optional"`
 }
 
+// Task assignment modes.
+const (
+	ModePickup  = "pickup"
+	ModeBidding = "bidding"
+)
+
+// Task visibilities.
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+)
+
 type TaskCreateResponse struct {
-	TaskID string `json:"task_id"`
-	Status string `json:"status"`
-	Need   string `json:"need"`
+	TaskID string     `json:"task_id"`
+	Status TaskStatus `json:"status"`
+	Need   string     `json:"need"`
+	// Mode, Visibility and OrgID are only sent by servers that support
+	// bidding, private tasks and orgs.
+	Mode       string `json:"mode,omitempty" apispec:"optional"`
+	Visibility string `json:"visibility,omitempty" apispec:"optional"`
+	OrgID      string `json:"org_id,omitempty" apispec:"optional"`
+	// WebURL is set by the CLI from TaskWebURL; the server doesn't send it.
+	WebURL string `json:"web_url,omitempty" apispec:"-"`
 }
 
 type TaskResponse struct {
-	TaskID               string `json:"task_id"`
-	Status               string `json:"status"`
-	Need                 string `json:"need"`
-	Context              string `json:"context,omitempty"`
-	Result               string `json:"result,omitempty"`
-	CreditsCharged       *int   `json:"credits_charged,omitempty"`
-	PosterID             string `json:"poster_id,omitempty"`
-	WorkerID             string `json:"worker_id,omitempty"`
-	Deadline             string `json:"deadline,omitempty"`
-	ClaimDeadline        string `json:"claim_deadline,omitempty"`
-	ReviewTimeoutMinutes *int   `json:"review_timeout_minutes,omitempty"`
-	ClaimTimeoutMinutes  *int   `json:"claim_timeout_minutes,omitempty"`
+	TaskID               string     `json:"task_id"`
+	Status               TaskStatus `json:"status"`
+	Need                 string     `json:"need"`
+	Context              string     `json:"context,omitempty"`
+	Result               string     `json:"result,omitempty"`
+	CreditsCharged       *int       `json:"credits_charged,omitempty"`
+	PosterID             string     `json:"poster_id,omitempty"`
+	WorkerID             string     `json:"worker_id,omitempty"`
+	Deadline             Time       `json:"deadline,omitempty"`
+	ClaimDeadline        Time       `json:"claim_deadline,omitempty"`
+	ReviewTimeoutMinutes *int       `json:"review_timeout_minutes,omitempty"`
+	ClaimTimeoutMinutes  *int       `json:"claim_timeout_minutes,omitempty"`
+	Visibility           string     `json:"visibility,omitempty" apispec:"optional"`
+	AllowedAgents        []string   `json:"allowed_agents,omitempty" apispec:"optional"`
+	CreatedAt            Time       `json:"created_at,omitempty" apispec:"optional"`
+	// WebURL is set by the CLI from TaskWebURL; the server doesn't send it.
+	WebURL string `json:"web_url,omitempty" apispec:"-"`
 }
 
 type TaskAvailableItem struct {
@@ -44,13 +79,13 @@ type TaskAvailableItem struct {
 	Context          string   `json:"context,omitempty"`
 	MaxCredits       int      `json:"max_credits"`
 	Tags             []string `json:"tags,omitempty"`
-	CreatedAt        string   `json:"created_at,omitempty"`
+	CreatedAt        Time     `json:"created_at,omitempty"`
 	PosterID         string   `json:"poster_id"`
 	PosterReputation *float64 `json:"poster_reputation,omitempty"`
 	IsMatched        bool     `json:"is_matched"`
 	MatchRank        *int     `json:"match_rank,omitempty"`
 	RejectionCount   int      `json:"rejection_count"`
-	Deadline         string   `json:"deadline,omitempty"`
+	Deadline         Time     `json:"deadline,omitempty"`
 }
 
 type TaskAvailableResponse struct {
@@ -65,10 +100,10 @@ type TaskPickupResponse struct {
 	MaxCredits          int      `json:"max_credits"`
 	PosterID            string   `json:"poster_id"`
 	Tags                []string `json:"tags,omitempty"`
-	CreatedAt           string   `json:"created_at,omitempty"`
+	CreatedAt           Time     `json:"created_at,omitempty"`
 	PosterReputation    *float64 `json:"poster_reputation,omitempty"`
-	Deadline            string   `json:"deadline,omitempty"`
-	ClaimDeadline       string   `json:"claim_deadline,omitempty"`
+	Deadline            Time     `json:"deadline,omitempty"`
+	ClaimDeadline       Time     `json:"claim_deadline,omitempty"`
 	ClaimTimeoutMinutes *int     `json:"claim_timeout_minutes,omitempty"`
 }
 
@@ -127,13 +162,13 @@ func (c *Client) ListAvailableTasks(tags, search string, limit, offset int) (*Ta
 	return &resp, err
 }
 
-func (c *Client) ListMyTasks(role, status string, limit, offset int) (*MyTasksResponse, error) {
+func (c *Client) ListMyTasks(role string, status TaskStatus, limit, offset int) (*MyTasksResponse, error) {
 	params := url.Values{}
 	if role != "" {
 		params.Set("role", role)
 	}
 	if status != "" {
-		params.Set("status", status)
+		params.Set("status", string(status))
 	}
 	params.Set("limit", fmt.Sprintf("%d", limit))
 	params.Set("offset", fmt.Sprintf("%d", offset))
@@ -149,6 +184,25 @@ func (c *Client) GetTask(taskID string) (*TaskResponse, error) {
 	return &resp, err
 }
 
+// TaskWebURL returns the address of the task's page on the server's web
+// dashboard.
+func (c *Client) TaskWebURL(taskID string) string {
+	return strings.TrimRight(c.BaseURL, "/") + "/human/tasks/" + url.PathEscape(taskID)
+}
+
+// TaskShareResponse is a short link to a task's web page.
+type TaskShareResponse struct {
+	URL       string `json:"url"`
+	ExpiresAt Time   `json:"expires_at,omitempty"`
+}
+
+// ShareTask asks the server for a short link to the task's web page.
+func (c *Client) ShareTask(taskID string) (*TaskShareResponse, error) {
+	var resp TaskShareResponse
+	err := c.Post("/v1/tasks/"+taskID+"/share", map[string]interface{}{}, &resp)
+	return &resp, err
+}
+
 func (c *Client) PickupTask(tags, search string) (*TaskPickupResponse, error) {
 	params := url.Values{}
 	if tags != "" {
@@ -173,11 +227,7 @@ func (c *Client) PickupTask(tags, search string) (*TaskPickupResponse, error) {
 	}
 
 	if resp.StatusCode >= 400 {
-		var errResp struct {
-			Error string `json:"error"`
-		}
-		_ = json.Unmarshal(data, &errResp)
-		return nil, &APIError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		return nil, pickupError(resp.StatusCode, data)
 	}
 
 	var result TaskPickupResponse
@@ -198,11 +248,7 @@ func (c *Client) PickupSpecificTask(taskID string) (*TaskPickupResponse, error)
 	}
 
 	if resp.StatusCode >= 400 {
-		var errResp struct {
-			Error string `json:"error"`
-		}
-		_ = json.Unmarshal(data, &errResp)
-		return nil, &APIError{StatusCode: resp.StatusCode, Message: errResp.Error}
+		return nil, pickupError(resp.StatusCode, data)
 	}
 
 	var result TaskPickupResponse
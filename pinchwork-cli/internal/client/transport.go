@@ -0,0 +1,25 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// DefaultTransport is shared by every Client made with New, so clients for
+// different profiles or servers reuse one connection pool. It keeps enough
+// idle connections per host for a worker pool or bulk command to fire
+// requests from many goroutines without reconnecting each time.
+var DefaultTransport http.RoundTripper = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	MaxIdleConnsPerHost:   32,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: time.Second,
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// BenchmarkClientParallel fires requests from many goroutines through one
+// Client, as a worker pool does, and reports how many connections the
+// server saw. The shared transport should keep that near the number of
+// goroutines; Go's default of 2 idle connections per host reconnects.
+func BenchmarkClientParallel(b *testing.B) {
+	transports := []struct {
+		name      string
+		transport http.RoundTripper
+	}{
+		{"shared", DefaultTransport},
+		{"go-default", &http.Transport{}},
+	}
+	for _, tt := range transports {
+		b.Run(tt.name, func(b *testing.B) {
+			var conns atomic.Int64
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"task_id":"tk-1","status":"pending","need":"bench"}`))
+			}))
+			srv.Config.ConnState = func(_ net.Conn, s http.ConnState) {
+				if s == http.StateNew {
+					conns.Add(1)
+				}
+			}
+			srv.Start()
+			defer srv.Close()
+
+			c := New(srv.URL, "key")
+			c.HTTPClient.Transport = tt.transport
+			b.SetParallelism(8)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := c.GetTask("tk-1"); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.ReportMetric(float64(conns.Load()), "conns")
+		})
+	}
+}
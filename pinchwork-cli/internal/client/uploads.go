@@ -0,0 +1,210 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// DefaultChunkSize is used for chunked uploads when the server does not
+// advertise its own chunk size.
+const DefaultChunkSize = 1 << 20
+
+type UploadSession struct {
+	UploadID  string `json:"upload_id"`
+	TaskID    string `json:"task_id"`
+	Size      int64  `json:"size"`
+	Offset    int64  `json:"offset"`
+	ChunkSize int    `json:"chunk_size,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// DeliverTaskReader delivers a result read from r without holding the whole
+// result in memory: the JSON body is encoded on the fly while it is sent.
+// Sending a large result can take longer than the client's timeout allows,
+// so that doesn't apply; ctx bounds the request instead.
+func (c *Client) DeliverTaskReader(ctx context.Context, taskID string, r io.Reader, creditsClaimed *int) (*TaskResponse, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeDeliverBody(pw, r, creditsClaimed))
+	}()
+
+	resp, err := c.WithTimeout(0).doRawRequestContext(ctx, "POST", "/v1/tasks/"+taskID+"/deliver", "application/json", pr, nil)
+	if err != nil {
+		pr.CloseWithError(err)
+		return nil, err
+	}
+	var result TaskResponse
+	if err := decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// writeDeliverBody writes {"result": "...", "credits_claimed": N} to w,
+// escaping r as a JSON string one rune at a time.
+func writeDeliverBody(w io.Writer, r io.Reader, creditsClaimed *int) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`{"result":"`)
+	if err := writeJSONString(bw, bufio.NewReader(r)); err != nil {
+		return err
+	}
+	bw.WriteString(`"`)
+	if creditsClaimed != nil {
+		bw.WriteString(`,"credits_claimed":` + strconv.Itoa(*creditsClaimed))
+	}
+	bw.WriteString(`}`)
+	return bw.Flush()
+}
+
+func writeJSONString(w *bufio.Writer, r *bufio.Reader) error {
+	const hex = "0123456789abcdef"
+	for {
+		ch, size, err := r.ReadRune()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case ch == '"' || ch == '\\':
+			w.WriteByte('\\')
+			w.WriteByte(byte(ch))
+		case ch == '\n':
+			w.WriteString(`\n`)
+		case ch == '\r':
+			w.WriteString(`\r`)
+		case ch == '\t':
+			w.WriteString(`\t`)
+		case ch < 0x20:
+			w.WriteString(`\u00`)
+			w.WriteByte(hex[ch>>4])
+			w.WriteByte(hex[ch&0xF])
+		case ch == utf8.RuneError && size == 1:
+			w.WriteString(`\ufffd`)
+		default:
+			w.WriteRune(ch)
+		}
+	}
+}
+
+// StartUpload opens a resumable chunked upload for a task's result.
+func (c *Client) StartUpload(taskID string, size int64) (*UploadSession, error) {
+	body := map[string]interface{}{
+		"size": size,
+	}
+	var resp UploadSession
+	err := c.Post("/v1/tasks/"+taskID+"/uploads", body, &resp)
+	return &resp, err
+}
+
+// GetUpload returns the in-progress upload for a task, including the offset
+// the server has durably received so far.
+func (c *Client) GetUpload(taskID string) (*UploadSession, error) {
+	var resp UploadSession
+	err := c.Get("/v1/tasks/"+taskID+"/uploads/current", &resp)
+	return &resp, err
+}
+
+// UploadChunk sends bytes starting at offset and returns the server's new
+// offset.
+func (c *Client) UploadChunk(taskID, uploadID string, offset int64, chunk []byte) (int64, error) {
+	headers := map[string]string{
+		"Upload-Offset": strconv.FormatInt(offset, 10),
+	}
+	resp, err := c.doRawRequest("PUT", "/v1/tasks/"+taskID+"/uploads/"+uploadID, "application/octet-stream", bytes.NewReader(chunk), headers)
+	if err != nil {
+		return offset, err
+	}
+	var session UploadSession
+	if err := decodeResponse(resp, &session); err != nil {
+		return offset, err
+	}
+	return session.Offset, nil
+}
+
+// CompleteUpload finalizes the upload and delivers it as the task result.
+func (c *Client) CompleteUpload(taskID, uploadID string, creditsClaimed *int) (*TaskResponse, error) {
+	body := map[string]interface{}{}
+	if creditsClaimed != nil {
+		body["credits_claimed"] = *creditsClaimed
+	}
+	var resp TaskResponse
+	err := c.Post("/v1/tasks/"+taskID+"/uploads/"+uploadID+"/complete", body, &resp)
+	return &resp, err
+}
+
+// DeliverTaskChunked uploads r in chunks and delivers it. If an upload for the
+// task is already in progress it resumes from the server's offset, so an
+// interrupted delivery can simply be re-run. progress, if non-nil, is called
+// after every chunk.
+func (c *Client) DeliverTaskChunked(taskID string, r io.ReadSeeker, size int64, creditsClaimed *int, progress func(sent, total int64)) (*TaskResponse, error) {
+	session, err := c.GetUpload(taskID)
+	if err != nil {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != 404 {
+			return nil, err
+		}
+	}
+	// An upload of a different result, such as one edited since, can't be
+	// resumed; start over.
+	if err != nil || (session.Size != 0 && session.Size != size) || session.Offset > size {
+		session, err = c.StartUpload(taskID, size)
+		if err != nil {
+			return nil, fmt.Errorf("start upload: %w", err)
+		}
+	}
+
+	chunkSize := session.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	buf := make([]byte, chunkSize)
+	offset := session.Offset
+	retries := 0
+
+	for offset < size {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		// Completing an upload that is short of size would deliver a
+		// truncated result, so a file that shrank since it was measured
+		// is an error, and one that grew is cut off at size.
+		want := min(int64(len(buf)), size-offset)
+		n, err := io.ReadFull(r, buf[:want])
+		if err == io.ErrUnexpectedEOF || err == io.EOF {
+			return nil, fmt.Errorf("result ended at %d bytes, short of the %d being uploaded; did it change during the upload?", offset+int64(n), size)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		next, err := c.UploadChunk(taskID, session.UploadID, offset, buf[:n])
+		if err != nil {
+			// Ask the server where it is and continue from there.
+			retries++
+			if retries > 3 {
+				return nil, fmt.Errorf("upload chunk at offset %d: %w", offset, err)
+			}
+			current, serr := c.GetUpload(taskID)
+			if serr != nil {
+				return nil, fmt.Errorf("upload chunk at offset %d: %w", offset, err)
+			}
+			offset = current.Offset
+			continue
+		}
+		retries = 0
+		offset = next
+		if progress != nil {
+			progress(offset, size)
+		}
+	}
+
+	return c.CompleteUpload(taskID, session.UploadID, creditsClaimed)
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldError is one problem with one field of a request. Field uses the
+// API's JSON names, e.g. "max_credits", with dots for nested fields.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (f FieldError) String() string {
+	if f.Field == "" {
+		return f.Message
+	}
+	return f.Field + ": " + f.Message
+}
+
+// ValidationError lists what is wrong with a request. It comes from a 422
+// response, in which case it wraps the *APIError, or from the local
+// preflight checks against Limits, which run before anything is sent.
+type ValidationError struct {
+	Fields []FieldError
+	// APIError is the server's response, nil for local checks.
+	APIError *APIError
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		lines[i] = f.String()
+	}
+	msg := "invalid request:\n  - " + strings.Join(lines, "\n  - ")
+	if e.APIError != nil {
+		return fmt.Sprintf("API error %d: %s", e.APIError.StatusCode, msg)
+	}
+	return msg
+}
+
+func (e *ValidationError) Unwrap() error {
+	if e.APIError == nil {
+		return nil
+	}
+	return e.APIError
+}
+
+// Field returns the problem reported for field, if any.
+func (e *ValidationError) Field(field string) (FieldError, bool) {
+	for _, f := range e.Fields {
+		if f.Field == field {
+			return f, true
+		}
+	}
+	return FieldError{}, false
+}
+
+// parseFieldErrors reads the per-field problems in an error response's
+// detail: either FastAPI's list of {"loc": [...], "msg": ...} objects or a
+// plain {"field": "message"} object. It returns nil for anything else.
+func parseFieldErrors(detail json.RawMessage) []FieldError {
+	var list []struct {
+		Loc []interface{} `json:"loc"`
+		Msg string        `json:"msg"`
+	}
+	if err := json.Unmarshal(detail, &list); err == nil {
+		var fields []FieldError
+		for _, d := range list {
+			if d.Msg == "" {
+				continue
+			}
+			fields = append(fields, FieldError{Field: locField(d.Loc), Message: d.Msg})
+		}
+		return fields
+	}
+
+	var byField map[string]string
+	if err := json.Unmarshal(detail, &byField); err == nil {
+		var fields []FieldError
+		for field, msg := range byField {
+			fields = append(fields, FieldError{Field: field, Message: msg})
+		}
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+		return fields
+	}
+	return nil
+}
+
+// locField turns a FastAPI error location like ["body", "tags", 2] into
+// "tags.2", dropping the part of the request it was found in.
+func locField(loc []interface{}) string {
+	if len(loc) > 1 {
+		switch loc[0] {
+		case "body", "query", "path", "header":
+			loc = loc[1:]
+		}
+	}
+	parts := make([]string, len(loc))
+	for i, p := range loc {
+		parts[i] = fmt.Sprint(p)
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,115 @@
+package client
+
+// VerificationTier is the Moltbook karma tier an agent has been verified at.
+type VerificationTier string
+
+const (
+	TierUnverified VerificationTier = "Unverified"
+	TierVerified   VerificationTier = "Verified"
+	TierPremium    VerificationTier = "Premium"
+	TierElite      VerificationTier = "Elite"
+)
+
+// Referral program rules, mirroring the server.
+const (
+	// ReferralBonus is paid to the referrer when a referred agent completes
+	// its first task.
+	ReferralBonus = 10
+	// MaxReferralBonuses caps how many referral bonuses one agent can earn.
+	MaxReferralBonuses = 50
+)
+
+// verificationTiers is ordered from highest to lowest threshold.
+var verificationTiers = []struct {
+	tier     VerificationTier
+	minKarma int
+	bonus    int
+}{
+	{TierElite, 1000, 300},
+	{TierPremium, 500, 200},
+	{TierVerified, 100, 100},
+	{TierUnverified, 0, 0},
+}
+
+// TierForKarma returns the tier a karma score qualifies for.
+func TierForKarma(karma int) VerificationTier {
+	for _, t := range verificationTiers {
+		if karma >= t.minKarma {
+			return t.tier
+		}
+	}
+	return TierUnverified
+}
+
+// NextTier returns the tier above the one karma qualifies for and how much
+// more karma is needed to reach it. It returns "" when already at the top.
+func NextTier(karma int) (VerificationTier, int) {
+	next := VerificationTier("")
+	needed := 0
+	for _, t := range verificationTiers {
+		if karma >= t.minKarma {
+			break
+		}
+		next, needed = t.tier, t.minKarma-karma
+	}
+	return next, needed
+}
+
+// MinKarma is the karma needed to reach the tier.
+func (t VerificationTier) MinKarma() int {
+	for _, v := range verificationTiers {
+		if v.tier == t {
+			return v.minKarma
+		}
+	}
+	return 0
+}
+
+// Bonus is the one-time credit bonus awarded on verifying at the tier.
+func (t VerificationTier) Bonus() int {
+	for _, v := range verificationTiers {
+		if v.tier == t {
+			return v.bonus
+		}
+	}
+	return 0
+}
+
+// IsVerified reports whether the tier carries a verification badge.
+func (t VerificationTier) IsVerified() bool {
+	return t != "" && t != TierUnverified
+}
+
+type VerificationStatusResponse struct {
+	Verified       bool             `json:"verified"`
+	MoltbookHandle string           `json:"moltbook_handle,omitempty"`
+	Karma          *int             `json:"karma"`
+	Tier           VerificationTier `json:"tier,omitempty"`
+	BonusAwarded   int              `json:"bonus_awarded"`
+	VerifiedAt     string           `json:"verified_at,omitempty"`
+	LastCheckedAt  string           `json:"last_checked_at,omitempty"`
+}
+
+type VerificationRefreshResponse struct {
+	Karma        int              `json:"karma"`
+	PreviousTier VerificationTier `json:"previous_tier"`
+	Tier         VerificationTier `json:"tier"`
+	Upgraded     bool             `json:"upgraded"`
+	BonusCredits int              `json:"bonus_credits"`
+	TotalCredits int              `json:"total_credits"`
+	Message      string           `json:"message"`
+}
+
+func (c *Client) GetVerificationStatus() (*VerificationStatusResponse, error) {
+	var resp VerificationStatusResponse
+	err := c.Get("/v1/me/verification", &resp)
+	return &resp, err
+}
+
+// RefreshVerification asks the server to re-read Moltbook karma and award the
+// difference in bonus credits if a higher tier has been reached.
+func (c *Client) RefreshVerification() (*VerificationRefreshResponse, error) {
+	var resp VerificationRefreshResponse
+	err := c.Post("/v1/me/verification/refresh", nil, &resp)
+	return &resp, err
+}
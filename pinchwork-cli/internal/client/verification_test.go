@@ -0,0 +1,148 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTierForKarma(t *testing.T) {
+	tests := []struct {
+		karma      int
+		tier       VerificationTier
+		next       VerificationTier
+		needed     int
+		bonus      int
+		isVerified bool
+	}{
+		{0, TierUnverified, TierVerified, 100, 0, false},
+		{99, TierUnverified, TierVerified, 1, 0, false},
+		{100, TierVerified, TierPremium, 400, 100, true},
+		{499, TierVerified, TierPremium, 1, 100, true},
+		{500, TierPremium, TierElite, 500, 200, true},
+		{1000, TierElite, "", 0, 300, true},
+		{5000, TierElite, "", 0, 300, true},
+	}
+	for _, tt := range tests {
+		tier := TierForKarma(tt.karma)
+		if tier != tt.tier {
+			t.Errorf("TierForKarma(%d) = %q, want %q", tt.karma, tier, tt.tier)
+		}
+		if next, needed := NextTier(tt.karma); next != tt.next || needed != tt.needed {
+			t.Errorf("NextTier(%d) = %q, %d, want %q, %d", tt.karma, next, needed, tt.next, tt.needed)
+		}
+		if tier.Bonus() != tt.bonus {
+			t.Errorf("%q.Bonus() = %d, want %d", tier, tier.Bonus(), tt.bonus)
+		}
+		if tier.IsVerified() != tt.isVerified {
+			t.Errorf("%q.IsVerified() = %v, want %v", tier, tier.IsVerified(), tt.isVerified)
+		}
+		if tier.MinKarma() > tt.karma {
+			t.Errorf("%q.MinKarma() = %d, above karma %d", tier, tier.MinKarma(), tt.karma)
+		}
+	}
+}
+
+func TestVerificationEndpoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		call    func(c *Client) (interface{}, error)
+		method  string
+		path    string
+		wantErr int
+		check   func(t *testing.T, v interface{})
+	}{
+		{
+			name:   "status",
+			status: 200,
+			body:   `{"verified":true,"moltbook_handle":"crab","karma":620,"tier":"Premium","bonus_awarded":200}`,
+			call:   func(c *Client) (interface{}, error) { return c.GetVerificationStatus() },
+			method: "GET",
+			path:   "/v1/me/verification",
+			check: func(t *testing.T, v interface{}) {
+				r := v.(*VerificationStatusResponse)
+				if !r.Verified || r.MoltbookHandle != "crab" || r.Karma == nil || *r.Karma != 620 || r.Tier != TierPremium || r.BonusAwarded != 200 {
+					t.Errorf("got %+v", r)
+				}
+			},
+		},
+		{
+			name:   "status unverified",
+			status: 200,
+			body:   `{"verified":false,"karma":null,"bonus_awarded":0}`,
+			call:   func(c *Client) (interface{}, error) { return c.GetVerificationStatus() },
+			method: "GET",
+			path:   "/v1/me/verification",
+			check: func(t *testing.T, v interface{}) {
+				r := v.(*VerificationStatusResponse)
+				if r.Verified || r.Karma != nil || r.Tier.IsVerified() {
+					t.Errorf("got %+v", r)
+				}
+			},
+		},
+		{
+			name:   "refresh upgraded",
+			status: 200,
+			body:   `{"karma":1200,"previous_tier":"Premium","tier":"Elite","upgraded":true,"bonus_credits":100,"total_credits":400,"message":"Upgraded"}`,
+			call:   func(c *Client) (interface{}, error) { return c.RefreshVerification() },
+			method: "POST",
+			path:   "/v1/me/verification/refresh",
+			check: func(t *testing.T, v interface{}) {
+				r := v.(*VerificationRefreshResponse)
+				if !r.Upgraded || r.PreviousTier != TierPremium || r.Tier != TierElite || r.BonusCredits != 100 || r.TotalCredits != 400 {
+					t.Errorf("got %+v", r)
+				}
+			},
+		},
+		{
+			name:    "refresh without handle",
+			status:  400,
+			body:    `{"error":"No Moltbook handle set"}`,
+			call:    func(c *Client) (interface{}, error) { return c.RefreshVerification() },
+			method:  "POST",
+			path:    "/v1/me/verification/refresh",
+			wantErr: 400,
+		},
+		{
+			name:    "bad key",
+			status:  401,
+			body:    `{"error":"Invalid API key"}`,
+			call:    func(c *Client) (interface{}, error) { return c.GetVerificationStatus() },
+			method:  "GET",
+			path:    "/v1/me/verification",
+			wantErr: 401,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.method || r.URL.Path != tt.path {
+					t.Errorf("request %s %s, want %s %s", r.Method, r.URL.Path, tt.method, tt.path)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer key" {
+					t.Errorf("Authorization = %q", got)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			v, err := tt.call(New(srv.URL, "key"))
+			if tt.wantErr != 0 {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantErr {
+					t.Fatalf("err = %v, want a %d API error", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, v)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -11,16 +12,181 @@ import (
 type Profile struct {
 	Server string `yaml:"server"`
 	APIKey string `yaml:"api_key"`
+	// AdminKey is only used by admin commands, so the everyday agent key
+	// doesn't need admin privileges.
+	AdminKey string `yaml:"admin_key,omitempty"`
+	Hooks    Hooks  `yaml:"hooks,omitempty"`
+	// SigningKey is the path of the private key used by deliver --sign.
+	SigningKey string `yaml:"signing_key,omitempty"`
+	// Searches are named task filters, used with --saved.
+	Searches map[string]SavedSearch `yaml:"searches,omitempty"`
+	// Sandbox marks a profile whose server trades in play credits. A
+	// profile named "sandbox" is always treated as one.
+	Sandbox bool `yaml:"sandbox,omitempty"`
+	// LowBalance sets up warnings when the credit balance runs low.
+	LowBalance LowBalance `yaml:"low_balance,omitempty"`
+	// Columns are the default --columns of table commands, by command
+	// ("tasks list": "id,need,credits,deadline").
+	Columns map[string]string `yaml:"columns,omitempty"`
+	// ConfirmCredits is how many credits a new task may escrow before
+	// 'tasks create' asks first: 0 means the CLI's default, negative never.
+	ConfirmCredits int `yaml:"confirm_credits,omitempty"`
+	// Jira and Linear are the issue trackers 'pinchwork import' posts
+	// tickets from and reports progress back to.
+	Jira   Jira   `yaml:"jira,omitempty"`
+	Linear Linear `yaml:"linear,omitempty"`
+}
+
+// Jira is a Jira Cloud site and the account to use it as.
+type Jira struct {
+	// URL is the site, like https://acme.atlassian.net.
+	URL   string `yaml:"url,omitempty"`
+	Email string `yaml:"email,omitempty"`
+	// Token is an API token of the account, from
+	// https://id.atlassian.com/manage-profile/security/api-tokens.
+	Token string `yaml:"token,omitempty"`
+	// InProgressStatus and DoneStatus are the statuses tickets move to when
+	// their task is claimed and approved (default "In Progress" and "Done").
+	InProgressStatus string `yaml:"in_progress_status,omitempty"`
+	DoneStatus       string `yaml:"done_status,omitempty"`
+}
+
+// Linear holds a Linear personal API key.
+type Linear struct {
+	Token string `yaml:"token,omitempty"`
+}
+
+// LowBalance warns on every command while the balance is below Below. When
+// the balance first drops below it, Exec is run and Webhook is sent a POST.
+type LowBalance struct {
+	Below   int    `yaml:"below,omitempty"`
+	Exec    string `yaml:"exec,omitempty"`
+	Webhook string `yaml:"webhook,omitempty"`
+}
+
+// SavedSearch is a named combination of task list/pickup filters.
+type SavedSearch struct {
+	Tags                string   `yaml:"tags,omitempty"`
+	Search              string   `yaml:"search,omitempty"`
+	MinCredits          int      `yaml:"min_credits,omitempty"`
+	MinPosterReputation float64  `yaml:"min_poster_reputation,omitempty"`
+	MaxRejections       *int     `yaml:"max_rejections,omitempty"`
+	MaxRisk             *float64 `yaml:"max_risk,omitempty"`
+}
+
+// Hooks are shell commands run after task lifecycle actions, whether they
+// happen through the CLI or arrive as events.
+type Hooks struct {
+	OnPickup    string `yaml:"on_pickup,omitempty"`
+	OnDelivered string `yaml:"on_delivered,omitempty"`
+	OnApproved  string `yaml:"on_approved,omitempty"`
+	OnRejected  string `yaml:"on_rejected,omitempty"`
+}
+
+// Command returns the hook configured for a lifecycle event such as
+// "pickup" or "approved", or "" if there is none.
+func (h Hooks) Command(event string) string {
+	switch event {
+	case "pickup":
+		return h.OnPickup
+	case "delivered":
+		return h.OnDelivered
+	case "approved":
+		return h.OnApproved
+	case "rejected":
+		return h.OnRejected
+	}
+	return ""
 }
 
 type Config struct {
 	CurrentProfile string             `yaml:"current_profile"`
 	Profiles       map[string]Profile `yaml:"profiles"`
+	// Aliases are shortcuts for commands, shared by all profiles: with
+	// "pu": "tasks pickup --tags go", 'pinchwork pu' runs that command.
+	Aliases map[string]string `yaml:"aliases,omitempty"`
 }
 
-func DefaultConfigPath() string {
+// Dir is where the config file and the local state next to it live:
+// $XDG_CONFIG_HOME/pinchwork (~/.config/pinchwork by default) on Linux,
+// %AppData%\pinchwork on Windows and ~/Library/Application Support/pinchwork
+// on macOS.
+func Dir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return legacyDir()
+	}
+	return filepath.Join(dir, "pinchwork")
+}
+
+// legacyDir is where versions before Dir existed kept everything,
+// whatever the platform.
+func legacyDir() string {
 	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".config", "pinchwork", "config.yaml")
+	return filepath.Join(home, ".config", "pinchwork")
+}
+
+func DefaultConfigPath() string {
+	return filepath.Join(Dir(), "config.yaml")
+}
+
+// MigrateLegacyDir moves ~/.config/pinchwork to Dir when they differ and
+// only the old one exists. It returns the directories it moved between, or
+// empty strings when there was nothing to do.
+func MigrateLegacyDir() (from, to string, err error) {
+	from, to = legacyDir(), Dir()
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return "", "", nil
+	}
+	if _, err := os.Stat(filepath.Join(from, "config.yaml")); err != nil {
+		return "", "", nil
+	}
+	if _, err := os.Stat(to); err == nil {
+		return "", "", nil
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0700); err != nil {
+		return from, to, err
+	}
+	if err := os.Rename(from, to); err != nil {
+		// Rename can't cross filesystems, e.g. to an XDG_CONFIG_HOME on
+		// another mount, so copy and remove instead.
+		if err := copyDir(from, to); err != nil {
+			os.RemoveAll(to)
+			return from, to, err
+		}
+		if err := os.RemoveAll(from); err != nil {
+			return from, to, err
+		}
+	}
+	return from, to, nil
+}
+
+func copyDir(from, to string) error {
+	return filepath.WalkDir(from, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
 }
 
 func Load(path string) (*Config, error) {
@@ -0,0 +1,71 @@
+// Package history is an append-only local log of the changes the CLI made on
+// the marketplace, one JSON object per line.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	TaskID  string    `json:"task_id,omitempty"`
+	Credits *int      `json:"credits,omitempty"`
+	Detail  string    `json:"detail,omitempty"`
+	Tags    []string  `json:"tags,omitempty"`
+	Command string    `json:"command,omitempty"`
+}
+
+// Append adds e to the log at path, creating it if needed.
+func Append(path string, e Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	// One write per entry keeps lines intact when several processes append.
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the entries at or after since, oldest first. A missing log
+// has no entries; lines that don't parse are skipped.
+func Read(path string, since time.Time) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		if e.Time.Before(since) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, sc.Err()
+}
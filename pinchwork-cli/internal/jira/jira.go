@@ -0,0 +1,177 @@
+// Package jira has the small parts of the Jira Cloud REST API that 'pinchwork
+// import jira' needs: finding issues, commenting on them and moving them
+// through their workflow. It uses API version 2, whose descriptions and
+// comments are plain text rather than Atlassian Document Format.
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client talks to one Jira site with an Atlassian account's API token.
+type Client struct {
+	// BaseURL is the site, like https://acme.atlassian.net.
+	BaseURL    string
+	Email      string
+	Token      string
+	HTTPClient *http.Client
+}
+
+type Issue struct {
+	Key         string
+	Summary     string
+	Description string
+	Labels      []string
+	Status      string
+}
+
+type issueJSON struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string   `json:"summary"`
+		Description string   `json:"description"`
+		Labels      []string `json:"labels"`
+		Status      struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+func (i issueJSON) issue() Issue {
+	return Issue{Key: i.Key, Summary: i.Fields.Summary, Description: i.Fields.Description, Labels: i.Fields.Labels, Status: i.Fields.Status.Name}
+}
+
+const issueFields = "summary,description,labels,status"
+
+// BrowseURL is the address of an issue in the Jira web UI.
+func (c *Client) BrowseURL(key string) string {
+	return strings.TrimRight(c.BaseURL, "/") + "/browse/" + key
+}
+
+// Search returns up to max issues matching a JQL query.
+func (c *Client) Search(jql string, max int) ([]Issue, error) {
+	var issues []Issue
+	token := ""
+	for len(issues) < max {
+		params := url.Values{"jql": {jql}, "fields": {issueFields}, "maxResults": {fmt.Sprint(min(max-len(issues), 100))}}
+		if token != "" {
+			params.Set("nextPageToken", token)
+		}
+		var page struct {
+			Issues        []issueJSON `json:"issues"`
+			NextPageToken string      `json:"nextPageToken"`
+		}
+		if err := c.do("GET", "/rest/api/2/search/jql?"+params.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		for _, i := range page.Issues {
+			issues = append(issues, i.issue())
+		}
+		if token = page.NextPageToken; token == "" || len(page.Issues) == 0 {
+			break
+		}
+	}
+	return issues, nil
+}
+
+// Issue returns one issue by key, like DOC-42.
+func (c *Client) Issue(key string) (*Issue, error) {
+	var i issueJSON
+	if err := c.do("GET", "/rest/api/2/issue/"+url.PathEscape(key)+"?fields="+issueFields, nil, &i); err != nil {
+		return nil, err
+	}
+	issue := i.issue()
+	return &issue, nil
+}
+
+// AddComment comments on an issue.
+func (c *Client) AddComment(key, body string) error {
+	return c.do("POST", "/rest/api/2/issue/"+url.PathEscape(key)+"/comment", map[string]string{"body": body}, nil)
+}
+
+// TransitionTo moves an issue to the named status, using the first
+// transition of its workflow that leads there. An issue already in that
+// status is left alone.
+func (c *Client) TransitionTo(key, status string) error {
+	var resp struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := c.do("GET", "/rest/api/2/issue/"+url.PathEscape(key)+"/transitions", nil, &resp); err != nil {
+		return err
+	}
+	for _, t := range resp.Transitions {
+		if strings.EqualFold(t.To.Name, status) {
+			body := map[string]interface{}{"transition": map[string]string{"id": t.ID}}
+			return c.do("POST", "/rest/api/2/issue/"+url.PathEscape(key)+"/transitions", body, nil)
+		}
+	}
+	issue, err := c.Issue(key)
+	if err == nil && strings.EqualFold(issue.Status, status) {
+		return nil
+	}
+	return fmt.Errorf("%s has no transition to %q", key, status)
+}
+
+func (c *Client) do(method, path string, body, result interface{}) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, strings.TrimRight(c.BaseURL, "/")+path, r)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.Email, c.Token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var e struct {
+			ErrorMessages []string          `json:"errorMessages"`
+			Errors        map[string]string `json:"errors"`
+		}
+		_ = json.Unmarshal(data, &e)
+		msgs := e.ErrorMessages
+		for field, msg := range e.Errors {
+			msgs = append(msgs, field+": "+msg)
+		}
+		if len(msgs) == 0 {
+			msgs = []string{http.StatusText(resp.StatusCode)}
+		}
+		return fmt.Errorf("jira: %s %s: %d %s", method, strings.SplitN(path, "?", 2)[0], resp.StatusCode, strings.Join(msgs, "; "))
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
@@ -0,0 +1,182 @@
+// Package journal records received events in an append-only file, one JSON
+// object per line, so they can be replayed after a crash or a handler bug.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type Record struct {
+	ReceivedAt time.Time       `json:"received_at"`
+	ID         string          `json:"id,omitempty"`
+	Type       string          `json:"type"`
+	TaskID     string          `json:"task_id,omitempty"`
+	Data       json.RawMessage `json:"data,omitempty"`
+
+	// Set for events in a dead-letter file: the handler that gave up on
+	// the event, how often it was tried and the last error.
+	Command  string `json:"command,omitempty"`
+	Attempts int    `json:"attempts,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Writer appends records to a journal file.
+type Writer struct {
+	f *os.File
+}
+
+// Open opens the journal at path for appending, creating it if needed.
+func Open(path string) (*Writer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{f: f}, nil
+}
+
+// Write appends recs and syncs the file once for all of them, so they are
+// on disk when it returns.
+func (w *Writer) Write(recs ...Record) error {
+	var buf []byte
+	for _, r := range recs {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+	if _, err := w.f.Write(buf); err != nil {
+		return err
+	}
+	return w.f.Sync()
+}
+
+func (w *Writer) Close() error {
+	return w.f.Close()
+}
+
+// Append opens the journal at path, writes recs and closes it again.
+func Append(path string, recs ...Record) error {
+	w, err := Open(path)
+	if err != nil {
+		return err
+	}
+	if err := w.Write(recs...); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// Replace atomically swaps the journal at path for one holding recs.
+func Replace(path string, recs []Record) error {
+	tmp := path + ".tmp"
+	w, err := Open(tmp)
+	if err != nil {
+		return err
+	}
+	if err := w.f.Truncate(0); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Write(recs...); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Rewrite replaces the first n records of the journal at path, read before,
+// with recs. Records another process appended since are kept, including any
+// that land in the old file while it is being swapped.
+func Rewrite(path string, n int, recs []Record) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Replace(path, recs)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	all, offset, err := readLines(f)
+	if err != nil {
+		return err
+	}
+	keep := append([]Record{}, recs...)
+	if n < len(all) {
+		keep = append(keep, all[n:]...)
+	}
+	if err := Replace(path, keep); err != nil {
+		return err
+	}
+
+	// f is still the old file: pick up what was appended to it after it
+	// was read.
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	late, _, err := readLines(f)
+	if err != nil || len(late) == 0 {
+		return err
+	}
+	return Append(path, late...)
+}
+
+// readLines reads the complete lines from r, returning the records among
+// them and how many bytes they took.
+func readLines(r io.Reader) ([]Record, int64, error) {
+	br := bufio.NewReader(r)
+	var recs []Record
+	var offset int64
+	for {
+		line, err := br.ReadBytes('\n')
+		if err == io.EOF {
+			return recs, offset, nil
+		}
+		if err != nil {
+			return recs, offset, err
+		}
+		offset += int64(len(line))
+		var r Record
+		if json.Unmarshal(line, &r) == nil {
+			recs = append(recs, r)
+		}
+	}
+}
+
+// Read calls fn for each record in the journal at path, oldest first,
+// stopping at the first error fn returns. Lines that don't parse, such as
+// one cut short by a crash, are skipped.
+func Read(path string, fn func(Record) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var r Record
+		if json.Unmarshal(sc.Bytes(), &r) != nil {
+			continue
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
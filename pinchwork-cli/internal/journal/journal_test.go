@@ -0,0 +1,60 @@
+package journal
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readIDs(t *testing.T, path string) []string {
+	t.Helper()
+	var ids []string
+	if err := Read(path, func(r Record) error {
+		ids = append(ids, r.ID)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func TestRewriteKeepsAppended(t *testing.T) {
+	tests := []struct {
+		name     string
+		read     []string
+		appended []string
+		keep     []string
+		want     []string
+	}{
+		{"nothing appended", []string{"1", "2", "3"}, nil, []string{"2"}, []string{"2"}},
+		{"appended while retrying", []string{"1", "2", "3"}, []string{"4", "5"}, []string{"2"}, []string{"2", "4", "5"}},
+		{"all handled", []string{"1"}, []string{"2"}, nil, []string{"2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "dlq.jsonl")
+			for _, id := range tt.read {
+				if err := Append(path, Record{ID: id}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			n := len(readIDs(t, path))
+			for _, id := range tt.appended {
+				if err := Append(path, Record{ID: id}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var keep []Record
+			for _, id := range tt.keep {
+				keep = append(keep, Record{ID: id})
+			}
+			if err := Rewrite(path, n, keep); err != nil {
+				t.Fatal(err)
+			}
+			if got := readIDs(t, path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("journal has %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
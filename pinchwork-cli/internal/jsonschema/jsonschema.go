@@ -0,0 +1,259 @@
+// Package jsonschema validates JSON documents against the subset of JSON
+// Schema that task result contracts need: type, enum, const, required,
+// properties, additionalProperties, items, length, range and pattern
+// keywords. Unknown keywords are ignored.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a parsed JSON Schema.
+type Schema struct {
+	raw map[string]interface{}
+}
+
+// Parse decodes a JSON Schema document.
+func Parse(data []byte) (*Schema, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	return &Schema{raw: raw}, nil
+}
+
+// Validate checks doc against the schema and returns every violation, each
+// prefixed with the JSON path of the offending value. A document that isn't
+// JSON at all is reported as a single violation.
+func (s *Schema) Validate(doc []byte) []string {
+	return s.ValidateReader(bytes.NewReader(doc))
+}
+
+// ValidateReader is Validate for a document read from r. The decoded
+// document is held in memory while it is checked.
+func (s *Schema) ValidateReader(r io.Reader) []string {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return []string{fmt.Sprintf("result is not valid JSON: %s", err)}
+	}
+	var problems []string
+	validate(s.raw, v, "$", &problems)
+	return problems
+}
+
+func validate(schema map[string]interface{}, v interface{}, path string, problems *[]string) {
+	fail := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, v) {
+		fail("expected %s, got %s", typeNames(t), jsonType(v))
+		return
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, v) {
+		fail("must be one of %s", compact(enum))
+	}
+	if c, ok := schema["const"]; ok && !equal(c, v) {
+		fail("must be %s", compact(c))
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		validateObject(schema, v, path, problems, fail)
+	case []interface{}:
+		if n, ok := number(schema["minItems"]); ok && float64(len(v)) < n {
+			fail("must have at least %v items", n)
+		}
+		if n, ok := number(schema["maxItems"]); ok && float64(len(v)) > n {
+			fail("must have at most %v items", n)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validate(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+	case string:
+		n := float64(utf8.RuneCountInString(v))
+		if min, ok := number(schema["minLength"]); ok && n < min {
+			fail("must be at least %v characters", min)
+		}
+		if max, ok := number(schema["maxLength"]); ok && n > max {
+			fail("must be at most %v characters", max)
+		}
+		if p, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				fail("schema pattern %q is invalid: %s", p, err)
+			} else if !re.MatchString(v) {
+				fail("must match pattern %q", p)
+			}
+		}
+	case json.Number:
+		f, _ := v.Float64()
+		if min, ok := number(schema["minimum"]); ok && f < min {
+			fail("must be >= %v", min)
+		}
+		if max, ok := number(schema["maximum"]); ok && f > max {
+			fail("must be <= %v", max)
+		}
+		if min, ok := number(schema["exclusiveMinimum"]); ok && f <= min {
+			fail("must be > %v", min)
+		}
+		if max, ok := number(schema["exclusiveMaximum"]); ok && f >= max {
+			fail("must be < %v", max)
+		}
+	}
+}
+
+func validateObject(schema, v map[string]interface{}, path string, problems *[]string, fail func(string, ...interface{})) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, present := v[name]; !present {
+					fail("missing required property %q", name)
+				}
+			}
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if ps, ok := props[k].(map[string]interface{}); ok {
+			validate(ps, v[k], path+"."+k, problems)
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				fail("unexpected property %q", k)
+			}
+		case map[string]interface{}:
+			validate(extra, v[k], path+"."+k, problems)
+		}
+	}
+}
+
+func matchesType(t interface{}, v interface{}) bool {
+	switch t := t.(type) {
+	case string:
+		return typeMatches(t, v)
+	case []interface{}:
+		for _, alt := range t {
+			if s, ok := alt.(string); ok && typeMatches(s, v) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func typeMatches(t string, v interface{}) bool {
+	actual := jsonType(v)
+	if t == "number" && actual == "integer" {
+		return true
+	}
+	return t == actual
+}
+
+func jsonType(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) && !strings.ContainsAny(v.String(), ".eE") {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func typeNames(t interface{}) string {
+	if list, ok := t.([]interface{}); ok {
+		var names []string
+		for _, n := range list {
+			names = append(names, fmt.Sprint(n))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+func number(v interface{}) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func containsValue(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if equal(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compares a schema value (decoded with float64 numbers) with a
+// document value (decoded with json.Number) by their JSON encodings.
+func equal(a, b interface{}) bool {
+	b, ok := floats(b)
+	return ok && compact(a) == compact(b)
+}
+
+// floats converts the json.Numbers in v, at any depth, to float64 the way
+// the schema was decoded, so that 1 and 1.0 compare equal.
+func floats(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			var ok bool
+			if out[i], ok = floats(item); !ok {
+				return nil, false
+			}
+		}
+		return out, true
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			var ok bool
+			if out[k], ok = floats(item); !ok {
+				return nil, false
+			}
+		}
+		return out, true
+	}
+	return v, true
+}
+
+func compact(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,43 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		doc    string
+		want   []string
+	}{
+		{"matching object", `{"type":"object","required":["a"],"properties":{"a":{"type":"integer"}}}`, `{"a":3}`, nil},
+		{"missing required", `{"type":"object","required":["a"]}`, `{}`, []string{`$: missing required property "a"`}},
+		{"wrong type", `{"type":"string"}`, `3`, []string{"$: expected string, got integer"}},
+		{"integer is a number", `{"type":"number"}`, `3`, nil},
+		{"no extra properties", `{"additionalProperties":false,"properties":{"a":{}}}`, `{"a":1,"b":2}`, []string{`$: unexpected property "b"`}},
+		{"nested items", `{"items":{"type":"string","maxLength":2}}`, `["ab","abc"]`, []string{"$[1]: must be at most 2 characters"}},
+		{"range", `{"minimum":1,"exclusiveMaximum":5}`, `5`, []string{"$: must be < 5"}},
+		{"enum number", `{"enum":[1,2]}`, `2.0`, nil},
+		{"enum nested number", `{"enum":[{"v":[1,2]}]}`, `{"v":[1,2.0]}`, nil},
+		{"enum nested mismatch", `{"enum":[{"v":[1,2]}]}`, `{"v":[1,3]}`, []string{`$: must be one of [{"v":[1,2]}]`}},
+		{"const nested number", `{"const":{"a":{"b":1}}}`, `{"a":{"b":1.0}}`, nil},
+		{"not JSON", `{}`, `{`, []string{"result is not valid JSON: unexpected EOF"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse([]byte(tt.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Validate([]byte(tt.doc)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate(%s) = %q, want %q", tt.doc, got, tt.want)
+			}
+			if got := s.ValidateReader(strings.NewReader(tt.doc)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateReader(%s) = %q, want %q", tt.doc, got, tt.want)
+			}
+		})
+	}
+}
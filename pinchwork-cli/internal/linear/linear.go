@@ -0,0 +1,214 @@
+// Package linear has the small parts of the Linear GraphQL API that
+// 'pinchwork import linear' needs: finding issues, commenting on them and
+// moving them between workflow states.
+package linear
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Client authenticates with a personal API key.
+type Client struct {
+	Token      string
+	HTTPClient *http.Client
+	// BaseURL defaults to https://api.linear.app.
+	BaseURL string
+}
+
+type Issue struct {
+	ID string
+	// Identifier is the human-readable key, like ENG-123.
+	Identifier  string
+	Title       string
+	Description string
+	URL         string
+	Labels      []string
+	State       string
+}
+
+const issueFields = `id identifier title description url state { name } labels { nodes { name } }`
+
+type issueJSON struct {
+	ID          string `json:"id"`
+	Identifier  string `json:"identifier"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	State       struct {
+		Name string `json:"name"`
+	} `json:"state"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+func (i issueJSON) issue() Issue {
+	issue := Issue{ID: i.ID, Identifier: i.Identifier, Title: i.Title, Description: i.Description, URL: i.URL, State: i.State.Name}
+	for _, l := range i.Labels.Nodes {
+		issue.Labels = append(issue.Labels, l.Name)
+	}
+	return issue
+}
+
+// Issues returns up to first open issues (not completed or canceled) of
+// the team with the given key, or of every team when team is empty, that
+// have the label, when one is given.
+func (c *Client) Issues(team, label string, first int) ([]Issue, error) {
+	filter := map[string]interface{}{
+		"state": map[string]interface{}{"type": map[string]interface{}{"nin": []string{"completed", "canceled"}}},
+	}
+	if team != "" {
+		filter["team"] = map[string]interface{}{"key": map[string]string{"eq": team}}
+	}
+	if label != "" {
+		filter["labels"] = map[string]interface{}{"name": map[string]string{"eq": label}}
+	}
+	var data struct {
+		Issues struct {
+			Nodes []issueJSON `json:"nodes"`
+		} `json:"issues"`
+	}
+	query := `query($filter: IssueFilter, $first: Int) { issues(filter: $filter, first: $first) { nodes { ` + issueFields + ` } } }`
+	if err := c.query(query, map[string]interface{}{"filter": filter, "first": first}, &data); err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, len(data.Issues.Nodes))
+	for i, n := range data.Issues.Nodes {
+		issues[i] = n.issue()
+	}
+	return issues, nil
+}
+
+// Issue returns one issue by its ID or identifier.
+func (c *Client) Issue(id string) (*Issue, error) {
+	var data struct {
+		Issue issueJSON `json:"issue"`
+	}
+	if err := c.query(`query($id: String!) { issue(id: $id) { `+issueFields+` } }`, map[string]interface{}{"id": id}, &data); err != nil {
+		return nil, err
+	}
+	issue := data.Issue.issue()
+	return &issue, nil
+}
+
+// AddComment comments on an issue, given by ID or identifier. The body is
+// Markdown.
+func (c *Client) AddComment(id, body string) error {
+	var data struct {
+		CommentCreate struct {
+			Success bool `json:"success"`
+		} `json:"commentCreate"`
+	}
+	query := `mutation($input: CommentCreateInput!) { commentCreate(input: $input) { success } }`
+	if err := c.query(query, map[string]interface{}{"input": map[string]string{"issueId": id, "body": body}}, &data); err != nil {
+		return err
+	}
+	if !data.CommentCreate.Success {
+		return fmt.Errorf("linear: commenting on %s failed", id)
+	}
+	return nil
+}
+
+// MoveToStateType moves an issue to the first workflow state of its team
+// with the given type, such as "started" or "completed".
+func (c *Client) MoveToStateType(id, stateType string) error {
+	var data struct {
+		Issue struct {
+			ID    string `json:"id"`
+			State struct {
+				Type string `json:"type"`
+			} `json:"state"`
+			Team struct {
+				States struct {
+					Nodes []struct {
+						ID       string  `json:"id"`
+						Type     string  `json:"type"`
+						Position float64 `json:"position"`
+					} `json:"nodes"`
+				} `json:"states"`
+			} `json:"team"`
+		} `json:"issue"`
+	}
+	query := `query($id: String!) { issue(id: $id) { id state { type } team { states { nodes { id type position } } } } }`
+	if err := c.query(query, map[string]interface{}{"id": id}, &data); err != nil {
+		return err
+	}
+	if data.Issue.State.Type == stateType {
+		return nil
+	}
+	stateID, best := "", 0.0
+	for _, s := range data.Issue.Team.States.Nodes {
+		if s.Type == stateType && (stateID == "" || s.Position < best) {
+			stateID, best = s.ID, s.Position
+		}
+	}
+	if stateID == "" {
+		return fmt.Errorf("linear: the team of %s has no %s state", id, stateType)
+	}
+	var updated struct {
+		IssueUpdate struct {
+			Success bool `json:"success"`
+		} `json:"issueUpdate"`
+	}
+	mutation := `mutation($id: String!, $input: IssueUpdateInput!) { issueUpdate(id: $id, input: $input) { success } }`
+	if err := c.query(mutation, map[string]interface{}{"id": data.Issue.ID, "input": map[string]string{"stateId": stateID}}, &updated); err != nil {
+		return err
+	}
+	if !updated.IssueUpdate.Success {
+		return fmt.Errorf("linear: moving %s failed", id)
+	}
+	return nil
+}
+
+func (c *Client) query(query string, vars map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": vars})
+	if err != nil {
+		return err
+	}
+	base := c.BaseURL
+	if base == "" {
+		base = "https://api.linear.app"
+	}
+	req, err := http.NewRequest("POST", strings.TrimRight(base, "/")+"/graphql", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Personal API keys are sent as is; OAuth tokens would need "Bearer".
+	req.Header.Set("Authorization", c.Token)
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var out struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return fmt.Errorf("linear: status %d", resp.StatusCode)
+	}
+	if len(out.Errors) > 0 {
+		msgs := make([]string, len(out.Errors))
+		for i, e := range out.Errors {
+			msgs[i] = e.Message
+		}
+		return fmt.Errorf("linear: %s", strings.Join(msgs, "; "))
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("linear: status %d", resp.StatusCode)
+	}
+	return json.Unmarshal(out.Data, result)
+}
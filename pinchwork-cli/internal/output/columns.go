@@ -0,0 +1,63 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const columnGap = " | "
+
+// Columns prints blocks of lines next to each other, each hard-wrapped to
+// an equal share of width.
+func Columns(w io.Writer, blocks [][]string, width int) {
+	if len(blocks) == 0 {
+		return
+	}
+	colWidth := (width - len(columnGap)*(len(blocks)-1)) / len(blocks)
+	if colWidth < 1 {
+		colWidth = 1
+	}
+	wrapped := make([][]string, len(blocks))
+	height := 0
+	for i, b := range blocks {
+		for _, line := range b {
+			wrapped[i] = append(wrapped[i], Wrap(line, colWidth)...)
+		}
+		if len(wrapped[i]) > height {
+			height = len(wrapped[i])
+		}
+	}
+	for row := 0; row < height; row++ {
+		cells := make([]string, len(wrapped))
+		for i, col := range wrapped {
+			cell := ""
+			if row < len(col) {
+				cell = col[row]
+			}
+			if i < len(wrapped)-1 {
+				cell += strings.Repeat(" ", colWidth-utf8.RuneCountInString(cell))
+			}
+			cells[i] = cell
+		}
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(cells, columnGap), " "))
+	}
+}
+
+// Wrap splits s into lines of at most width characters, breaking at the
+// last space where there is one.
+func Wrap(s string, width int) []string {
+	s = strings.TrimRight(strings.ReplaceAll(s, "\t", "    "), " ")
+	var lines []string
+	for utf8.RuneCountInString(s) > width {
+		r := []rune(s)
+		cut := width
+		if i := strings.LastIndex(string(r[:width+1]), " "); i > 0 {
+			cut = utf8.RuneCountInString(string(r[:width+1])[:i])
+		}
+		lines = append(lines, strings.TrimRight(string(r[:cut]), " "))
+		s = strings.TrimLeft(string(r[cut:]), " ")
+	}
+	return append(lines, s)
+}
@@ -1,12 +1,46 @@
 package output
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+
+	"github.com/itchyny/gojq"
 )
 
+// query is the --jq filter applied to JSON output, if any.
+var query *gojq.Code
+
+// queryFailed records that the --jq filter failed on some output.
+var queryFailed bool
+
+// SetQuery compiles a jq filter, such as ".result" or ".tasks[].task_id",
+// to run on everything JSON and JSONLine print from then on.
+func SetQuery(expr string) error {
+	q, err := gojq.Parse(expr)
+	if err != nil {
+		return err
+	}
+	code, err := gojq.Compile(q)
+	if err != nil {
+		return err
+	}
+	query = code
+	return nil
+}
+
+// QueryFailed reports whether the --jq filter failed on any output, so the
+// command can exit non-zero.
+func QueryFailed() bool {
+	return queryFailed
+}
+
 func JSON(w io.Writer, v interface{}) error {
+	if query != nil {
+		return runQuery(w, v, "  ")
+	}
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
@@ -14,3 +48,60 @@ func JSON(w io.Writer, v interface{}) error {
 	fmt.Fprintln(w, string(data))
 	return nil
 }
+
+// JSONLine prints v as a single line of JSON, for streams of events.
+func JSONLine(w io.Writer, v interface{}) error {
+	if query != nil {
+		return runQuery(w, v, "")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+// runQuery prints each result of the query on v. Like gh's --jq, strings are
+// printed raw rather than quoted.
+func runQuery(w io.Writer, v interface{}, indent string) error {
+	// gojq works on plain maps and slices, so go through JSON first. Numbers
+	// stay json.Number to keep large IDs exact.
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var in interface{}
+	if err := dec.Decode(&in); err != nil {
+		return err
+	}
+
+	iter := query.Run(in)
+	for {
+		out, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		if err, ok := out.(error); ok {
+			queryFailed = true
+			fmt.Fprintf(os.Stderr, "Error: --jq: %s\n", err)
+			return err
+		}
+		if s, ok := out.(string); ok {
+			fmt.Fprintln(w, s)
+			continue
+		}
+		var b []byte
+		if indent != "" {
+			b, err = json.MarshalIndent(out, "", indent)
+		} else {
+			b, err = json.Marshal(out)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(b))
+	}
+}
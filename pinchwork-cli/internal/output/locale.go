@@ -0,0 +1,132 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale is how numbers and dates are written in a region.
+type Locale struct {
+	Name string
+	// Thousands separates groups of three digits.
+	Thousands string
+	// Date and DateTime are time.Format layouts.
+	Date     string
+	DateTime string
+}
+
+// locales are the conventions --locale knows, by language or language and
+// region. Regions not listed fall back to their language.
+var locales = map[string]Locale{
+	"en":    {Thousands: ",", Date: "01/02/2006", DateTime: "01/02/2006 3:04 PM"},
+	"en-gb": {Thousands: ",", Date: "02/01/2006", DateTime: "02/01/2006 15:04"},
+	"en-au": {Thousands: ",", Date: "02/01/2006", DateTime: "02/01/2006 15:04"},
+	"de":    {Thousands: ".", Date: "02.01.2006", DateTime: "02.01.2006 15:04"},
+	"de-ch": {Thousands: "’", Date: "02.01.2006", DateTime: "02.01.2006 15:04"},
+	"nl":    {Thousands: ".", Date: "02-01-2006", DateTime: "02-01-2006 15:04"},
+	"fr":    {Thousands: " ", Date: "02/01/2006", DateTime: "02/01/2006 15:04"},
+	"es":    {Thousands: ".", Date: "02/01/2006", DateTime: "02/01/2006 15:04"},
+	"it":    {Thousands: ".", Date: "02/01/2006", DateTime: "02/01/2006 15:04"},
+	"pt":    {Thousands: ".", Date: "02/01/2006", DateTime: "02/01/2006 15:04"},
+	"sv":    {Thousands: " ", Date: "2006-01-02", DateTime: "2006-01-02 15:04"},
+	"pl":    {Thousands: " ", Date: "02.01.2006", DateTime: "02.01.2006 15:04"},
+	"ja":    {Thousands: ",", Date: "2006/01/02", DateTime: "2006/01/02 15:04"},
+	"zh":    {Thousands: ",", Date: "2006/01/02", DateTime: "2006/01/02 15:04"},
+}
+
+// defaultLocale is used without --locale: plain numbers and ISO dates.
+var defaultLocale = Locale{Date: "2006-01-02", DateTime: "2006-01-02 15:04"}
+
+var current = defaultLocale
+
+// UTC shows times in UTC instead of the local time zone (--utc).
+var UTC bool
+
+// SetLocale picks the conventions for name, such as "de" or "en-GB"; "auto"
+// takes it from LC_ALL, LC_NUMERIC or LANG, and "" restores the default.
+func SetLocale(name string) error {
+	if name == "" {
+		current = defaultLocale
+		return nil
+	}
+	if name == "auto" {
+		for _, env := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+			if v := os.Getenv(env); v != "" {
+				name = v
+				break
+			}
+		}
+		if name == "auto" || name == "C" || name == "POSIX" {
+			current = defaultLocale
+			return nil
+		}
+	}
+	// "de_DE.UTF-8" is de-de.
+	key := strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+	key, _, _ = strings.Cut(key, ".")
+	l, ok := locales[key]
+	if !ok {
+		lang, _, _ := strings.Cut(key, "-")
+		l, ok = locales[lang]
+	}
+	if !ok {
+		known := make([]string, 0, len(locales))
+		for k := range locales {
+			known = append(known, k)
+		}
+		sort.Strings(known)
+		return fmt.Errorf("unknown locale %q (known: %s)", name, strings.Join(known, ", "))
+	}
+	l.Name = key
+	current = l
+	return nil
+}
+
+// Localized reports whether --locale is in effect.
+func Localized() bool {
+	return current.Name != ""
+}
+
+// Number writes n with the locale's thousands separator.
+func Number(n int) string {
+	s := strconv.Itoa(n)
+	if current.Thousands == "" {
+		return s
+	}
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + current.Thousands + s[i:]
+	}
+	return sign + s
+}
+
+// InZone converts t to the zone times are shown in: local, or UTC with
+// --utc.
+func InZone(t time.Time) time.Time {
+	if UTC {
+		return t.UTC()
+	}
+	return t.Local()
+}
+
+// Date writes the day of t in the locale's format.
+func Date(t time.Time) string {
+	return InZone(t).Format(current.Date)
+}
+
+// DateTime writes t to the minute in the locale's format, marked "UTC" with
+// --utc.
+func DateTime(t time.Time) string {
+	s := InZone(t).Format(current.DateTime)
+	if UTC {
+		s += " UTC"
+	}
+	return s
+}
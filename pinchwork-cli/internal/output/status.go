@@ -0,0 +1,25 @@
+package output
+
+// ASCII turns off status glyphs (--ascii), for terminals and fonts that
+// can't show them.
+var ASCII bool
+
+// statusGlyphs mark task statuses in tables. A rejected delivery goes back
+// to claimed, so it shows as claimed.
+var statusGlyphs = map[string]string{
+	"posted":    "○",
+	"claimed":   "🔒",
+	"delivered": "⏳",
+	"approved":  "✓",
+	"cancelled": "✗",
+	"expired":   "⌛",
+}
+
+// Status renders a task status for a table cell, with its glyph unless
+// ASCII is set or the status is unknown.
+func Status(status string) string {
+	if g, ok := statusGlyphs[status]; ok && !ASCII {
+		return g + " " + status
+	}
+	return status
+}
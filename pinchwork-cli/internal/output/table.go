@@ -3,18 +3,217 @@ package output
 import (
 	"fmt"
 	"io"
+	"os"
 	"strings"
-	"text/tabwriter"
 )
 
+// SelectedColumns picks and orders the columns of every table by header
+// name, as set with --columns. Empty shows each table's default columns.
+var SelectedColumns []string
+
+// Wide is set by -o wide: tables show every column and wrap long cells to
+// the terminal width instead of truncating them.
+var Wide bool
+
+// Table writes rows under headers, showing the SelectedColumns if any.
 func Table(w io.Writer, headers []string, rows [][]string) {
-	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(tw, strings.Join(headers, "\t"))
-	fmt.Fprintln(tw, strings.Repeat("-\t", len(headers)))
+	TableDefault(w, headers, rows, nil)
+}
+
+// TableDefault is Table for commands that have more columns than fit on a
+// screen: only the columns named in defaults are shown unless
+// SelectedColumns asks for others. Columns with a blank header, such as
+// markers, are always kept.
+func TableDefault(w io.Writer, headers []string, rows [][]string, defaults []string) {
+	want := SelectedColumns
+	if len(want) == 0 && !Wide {
+		want = defaults
+	}
+	if len(want) > 0 {
+		keep := pickColumns(headers, want)
+		headers = pickCells(headers, keep)
+		picked := make([][]string, len(rows))
+		for i, row := range rows {
+			picked[i] = pickCells(row, keep)
+		}
+		rows = picked
+	}
+
+	if Wide {
+		wrappedTable(w, headers, rows, Width(160))
+		return
+	}
+
+	widths := columnWidths(headers, rows)
+	writeRow(w, headers, widths)
+	writeRow(w, dashes(len(headers)), widths)
+	for _, row := range rows {
+		writeRow(w, row, widths)
+	}
+}
+
+// Shown reports whether TableDefault would show the column with this
+// header, given whether it is one of the table's defaults.
+func Shown(header string, byDefault bool) bool {
+	if len(SelectedColumns) == 0 {
+		return byDefault || Wide
+	}
+	for _, name := range SelectedColumns {
+		if ColumnName(name) == ColumnName(header) {
+			return true
+		}
+	}
+	return false
+}
+
+// tableGap separates table columns.
+const tableGap = 2
+
+// columnWidths measures each column's widest cell on screen. Unlike
+// text/tabwriter, this keeps columns aligned around emoji and other wide
+// characters.
+func columnWidths(headers []string, rows [][]string) []int {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = DisplayWidth(h)
+	}
+	for _, row := range rows {
+		for i := 0; i < len(row) && i < len(widths); i++ {
+			widths[i] = max(widths[i], DisplayWidth(row[i]))
+		}
+	}
+	return widths
+}
+
+// writeRow writes cells padded to widths, without trailing spaces.
+func writeRow(w io.Writer, cells []string, widths []int) {
+	var b strings.Builder
+	for i, cell := range cells {
+		b.WriteString(cell)
+		if i < len(cells)-1 && i < len(widths) {
+			b.WriteString(strings.Repeat(" ", max(0, widths[i]-DisplayWidth(cell))+tableGap))
+		}
+	}
+	fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+}
+
+func dashes(n int) []string {
+	d := make([]string, n)
+	for i := range d {
+		d[i] = "-"
+	}
+	return d
+}
+
+// ColumnName is how a header is named in --columns: "CLAIM DEADLINE" is
+// claim_deadline.
+func ColumnName(header string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(header)))
+}
+
+// pickColumns returns the indexes of the wanted columns in the order asked
+// for, after any blank-header columns. Unknown names are reported on stderr.
+func pickColumns(headers, want []string) []int {
+	var keep []int
+	for i, h := range headers {
+		if strings.TrimSpace(h) == "" {
+			keep = append(keep, i)
+		}
+	}
+	for _, name := range want {
+		found := false
+		for i, h := range headers {
+			if h != "" && ColumnName(h) == ColumnName(name) {
+				keep = append(keep, i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			var names []string
+			for _, h := range headers {
+				if h != "" {
+					names = append(names, ColumnName(h))
+				}
+			}
+			fmt.Fprintf(os.Stderr, "Warning: this table has no column %q (it has %s)\n", name, strings.Join(names, ", "))
+		}
+	}
+	return keep
+}
+
+func pickCells(row []string, keep []int) []string {
+	out := make([]string, len(keep))
+	for i, k := range keep {
+		if k < len(row) {
+			out[i] = row[k]
+		}
+	}
+	return out
+}
+
+// wrappedTable writes a table that fits in width by wrapping the widest
+// columns over several lines.
+func wrappedTable(w io.Writer, headers []string, rows [][]string, width int) {
+	widths := columnWidths(headers, rows)
+
+	// Cap the widest columns at the level where everything fits, but never
+	// narrower than a short word.
+	avail := width - tableGap*(len(widths)-1)
+	total := func(limit int) int {
+		n := 0
+		for _, cw := range widths {
+			n += min(cw, limit)
+		}
+		return n
+	}
+	limit := 0
+	for _, cw := range widths {
+		limit = max(limit, cw)
+	}
+	for limit > 12 && total(limit) > avail {
+		limit--
+	}
+	for i := range widths {
+		widths[i] = min(widths[i], limit)
+	}
+
+	writeLines := func(cells []string) {
+		wrapped := make([][]string, len(cells))
+		height := 1
+		for i, cell := range cells {
+			if i < len(widths) {
+				wrapped[i] = Wrap(cell, widths[i])
+			} else {
+				wrapped[i] = []string{cell}
+			}
+			height = max(height, len(wrapped[i]))
+		}
+		for line := 0; line < height; line++ {
+			parts := make([]string, len(wrapped))
+			for i, col := range wrapped {
+				if line < len(col) {
+					parts[i] = col[line]
+				}
+			}
+			writeRow(w, parts, widths)
+		}
+	}
+
+	writeLines(headers)
+	writeLines(dashes(len(headers)))
 	for _, row := range rows {
-		fmt.Fprintln(tw, strings.Join(row, "\t"))
+		writeLines(row)
 	}
-	tw.Flush()
+}
+
+// Cell shortens s to max characters for a table cell, on one line. In wide
+// tables long cells are wrapped instead, so s is only put on one line.
+func Cell(s string, max int) string {
+	if Wide {
+		return strings.Join(strings.Fields(s), " ")
+	}
+	return Truncate(s, max)
 }
 
 func Truncate(s string, max int) string {
@@ -23,3 +222,43 @@ func Truncate(s string, max int) string {
 	}
 	return s[:max-3] + "..."
 }
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a row of block characters scaled between the
+// smallest and largest value.
+func Sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = (v - lo) * (len(sparkBlocks) - 1) / (hi - lo)
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
+
+// Size formats a byte count for display.
+func Size(n int) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	}
+}
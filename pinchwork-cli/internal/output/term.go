@@ -0,0 +1,41 @@
+package output
+
+import (
+	"os"
+	"strconv"
+)
+
+// IsTerminal reports whether f is an interactive terminal rather than a pipe
+// or file. The null device is a character device too, so it is ruled out
+// explicitly.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	if null, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, null) {
+		return false
+	}
+	return true
+}
+
+// Color wraps s in an ANSI SGR code, such as "1;33" for bold yellow, when f
+// is a terminal and NO_COLOR is not set.
+func Color(f *os.File, code, s string) string {
+	if !IsTerminal(f) || os.Getenv("NO_COLOR") != "" {
+		return s
+	}
+	return "\033[" + code + "m" + s + "\033[0m"
+}
+
+// Width is the terminal width from $COLUMNS or the terminal itself, or def
+// when neither says.
+func Width(def int) int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if n := termWidth(os.Stdout); n > 0 {
+		return n
+	}
+	return def
+}
@@ -0,0 +1,7 @@
+//go:build !(linux || darwin)
+
+package output
+
+import "os"
+
+func termWidth(f *os.File) int { return 0 }
@@ -0,0 +1,19 @@
+//go:build linux || darwin
+
+package output
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// termWidth asks the terminal f is attached to for its width, or returns 0.
+func termWidth(f *os.File) int {
+	var ws struct{ Row, Col, X, Y uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0
+	}
+	return int(ws.Col)
+}
@@ -0,0 +1,46 @@
+package output
+
+import "unicode"
+
+// DisplayWidth is how many terminal columns s takes: emoji and East Asian
+// wide characters take two, combining marks, joiners and the ANSI color
+// codes from Color none.
+func DisplayWidth(s string) int {
+	n := 0
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case r == '\033':
+			inEscape = true
+		case inEscape:
+			// An SGR sequence ends at its final letter, "m".
+			inEscape = r < '@' || r > '~' || r == '['
+		default:
+			n += runeWidth(r)
+		}
+	}
+	return n
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r == 0x200D || (r >= 0xFE00 && r <= 0xFE0F) || unicode.Is(unicode.Mn, r):
+		return 0
+	case r >= 0x1100 && r <= 0x115F,
+		r == 0x231A || r == 0x231B,
+		r >= 0x23E9 && r <= 0x23EC, r == 0x23F0, r == 0x23F3,
+		r == 0x2705, r == 0x274C, r == 0x2753, r == 0x2757,
+		r >= 0x2E80 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F,
+		r >= 0x1F680 && r <= 0x1F6FF,
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	}
+	return 1
+}
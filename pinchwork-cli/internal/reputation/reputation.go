@@ -0,0 +1,89 @@
+// Package reputation keeps snapshots of an agent's reputation over time, one
+// JSON object per line, since the server only knows the current value.
+package reputation
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type Snapshot struct {
+	Time        time.Time          `json:"time"`
+	Reputation  float64            `json:"reputation"`
+	RatingCount int                `json:"rating_count"`
+	ByTag       map[string]float64 `json:"by_tag,omitempty"`
+}
+
+// Drop is a reputation, overall or for a tag, that went down between two
+// snapshots. Tag is empty for the overall reputation.
+type Drop struct {
+	Tag  string  `json:"tag,omitempty"`
+	From float64 `json:"from"`
+	To   float64 `json:"to"`
+}
+
+// Drops compares two snapshots, reporting reputations that fell by at
+// least threshold. Tags missing from either snapshot are skipped.
+func Drops(prev, cur Snapshot, threshold float64) []Drop {
+	dropped := func(from, to float64) bool {
+		return from > to && from-to >= threshold
+	}
+	var drops []Drop
+	if dropped(prev.Reputation, cur.Reputation) {
+		drops = append(drops, Drop{From: prev.Reputation, To: cur.Reputation})
+	}
+	for tag, to := range cur.ByTag {
+		if from, ok := prev.ByTag[tag]; ok && dropped(from, to) {
+			drops = append(drops, Drop{Tag: tag, From: from, To: to})
+		}
+	}
+	return drops
+}
+
+// Append adds s to the log at path, creating it if needed.
+func Append(path string, s Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the snapshots at path, oldest first. A missing log has none;
+// lines that don't parse are skipped.
+func Read(path string) ([]Snapshot, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var snaps []Snapshot
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var s Snapshot
+		if json.Unmarshal(sc.Bytes(), &s) != nil {
+			continue
+		}
+		snaps = append(snaps, s)
+	}
+	return snaps, sc.Err()
+}
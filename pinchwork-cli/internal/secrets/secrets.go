@@ -0,0 +1,73 @@
+// Package secrets detects likely credentials in text before it is posted to
+// the marketplace, where every worker who views a task can read it.
+package secrets
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+type rule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// rules are deliberately specific to keep false positives low; the generic
+// assignment rule requires a key-like name and a long value.
+var rules = []rule{
+	{"private key", regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY( BLOCK)?-----`)},
+	{"AWS access key ID", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"AWS secret access key", regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}`)},
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{50,})\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{"Stripe secret key", regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"OpenAI/Anthropic API key", regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}`)},
+	{"Pinchwork API key", regexp.MustCompile(`\bpwk-[A-Za-z0-9_-]{16,}`)},
+	{"JSON Web Token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"password or token assignment", regexp.MustCompile(`(?i)\b[A-Z0-9_]*(?:password|passwd|secret|token|api_?key)[A-Z0-9_]*["']?\s*[:=]\s*["']?[^\s"'$<>{}]{12,}`)},
+}
+
+// Finding is one likely secret.
+type Finding struct {
+	Rule  string
+	Line  int
+	Match string // redacted
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("line %d: %s (%s)", f.Line, f.Rule, f.Match)
+}
+
+// Scan reads r line by line and reports likely secrets, at most one per line.
+func Scan(r io.Reader) ([]Finding, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var findings []Finding
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		for _, rule := range rules {
+			if m := rule.re.FindString(line); m != "" {
+				findings = append(findings, Finding{Rule: rule.name, Line: n, Match: redact(m)})
+				break
+			}
+		}
+	}
+	return findings, sc.Err()
+}
+
+// ScanString is Scan for in-memory text.
+func ScanString(s string) []Finding {
+	findings, _ := Scan(strings.NewReader(s))
+	return findings
+}
+
+func redact(s string) string {
+	if len(s) <= 8 {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:6] + strings.Repeat("*", 6)
+}
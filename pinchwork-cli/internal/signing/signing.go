@@ -0,0 +1,153 @@
+// Package signing signs and verifies task results with Ed25519 keys held by
+// the worker.
+//
+// A signature covers the SHA-256 digest of the task ID and the result, so it
+// can't be replayed onto another task. The API has no field for it, so it
+// travels as a trailer block at the end of the result.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	keyPrefix    = "ed25519:"
+	pemType      = "PINCHWORK ED25519 PRIVATE KEY"
+	trailerBegin = "\n\n-----BEGIN PINCHWORK SIGNATURE-----\n"
+	trailerEnd   = "-----END PINCHWORK SIGNATURE-----"
+)
+
+// Signature is a parsed signature trailer.
+type Signature struct {
+	PublicKey ed25519.PublicKey
+	Sig       []byte
+}
+
+// GenerateKey creates a new private key and writes it to path, readable only
+// by the current user. It refuses to overwrite an existing key.
+func GenerateKey(path string) (ed25519.PrivateKey, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := pem.Encode(f, &pem.Block{Type: pemType, Bytes: priv.Seed()}); err != nil {
+		return nil, err
+	}
+	return priv, nil
+}
+
+// LoadKey reads a private key written by GenerateKey.
+func LoadKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemType || len(block.Bytes) != ed25519.SeedSize {
+		return nil, fmt.Errorf("%s is not a pinchwork signing key", path)
+	}
+	return ed25519.NewKeyFromSeed(block.Bytes), nil
+}
+
+// FormatPublicKey encodes a public key the way signature trailers carry it.
+func FormatPublicKey(pub ed25519.PublicKey) string {
+	return keyPrefix + base64.StdEncoding.EncodeToString(pub)
+}
+
+// ParsePublicKey decodes a key produced by FormatPublicKey.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	if !strings.HasPrefix(s, keyPrefix) {
+		return nil, fmt.Errorf("unsupported key %q", s)
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, keyPrefix))
+	if err != nil || len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("malformed key %q", s)
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+// Fingerprint is a short, human-comparable identifier for a public key.
+func Fingerprint(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// Digest hashes a result for signing, streaming it from r.
+func Digest(taskID string, r io.Reader) ([]byte, error) {
+	h := sha256.New()
+	io.WriteString(h, taskID+"\n")
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// Trailer signs digest and returns the block to append to the result.
+func Trailer(priv ed25519.PrivateKey, digest []byte) string {
+	sig := ed25519.Sign(priv, digest)
+	pub := priv.Public().(ed25519.PublicKey)
+	return trailerBegin +
+		"key: " + FormatPublicKey(pub) + "\n" +
+		"sig: " + base64.StdEncoding.EncodeToString(sig) + "\n" +
+		trailerEnd + "\n"
+}
+
+// ErrUnsigned is returned by Split for results without a signature trailer.
+var ErrUnsigned = errors.New("result is not signed")
+
+// Split separates a delivered result into the signed body and its
+// signature.
+func Split(result string) (body string, sig *Signature, err error) {
+	i := strings.LastIndex(result, trailerBegin)
+	if i < 0 {
+		return result, nil, ErrUnsigned
+	}
+	body = result[:i]
+	block := strings.TrimSpace(result[i+len(trailerBegin):])
+	if !strings.HasSuffix(block, trailerEnd) {
+		return result, nil, fmt.Errorf("malformed signature trailer")
+	}
+
+	sig = &Signature{}
+	for _, line := range strings.Split(strings.TrimSuffix(block, trailerEnd), "\n") {
+		k, v, _ := strings.Cut(strings.TrimSpace(line), ": ")
+		switch k {
+		case "key":
+			if sig.PublicKey, err = ParsePublicKey(v); err != nil {
+				return body, nil, err
+			}
+		case "sig":
+			if sig.Sig, err = base64.StdEncoding.DecodeString(v); err != nil {
+				return body, nil, fmt.Errorf("malformed signature: %w", err)
+			}
+		}
+	}
+	if sig.PublicKey == nil || sig.Sig == nil {
+		return body, nil, fmt.Errorf("malformed signature trailer")
+	}
+	return body, sig, nil
+}
+
+// Verify checks that sig is a valid signature of body for taskID.
+func (s *Signature) Verify(taskID, body string) bool {
+	digest, _ := Digest(taskID, strings.NewReader(body))
+	return ed25519.Verify(s.PublicKey, digest, s.Sig)
+}
@@ -0,0 +1,259 @@
+// Package state keeps per-profile data that only exists on this machine,
+// such as starred tasks and private notes, in a JSON file next to the config.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+type State struct {
+	Stars []Star            `json:"stars,omitempty"`
+	Notes map[string][]Note `json:"notes,omitempty"`
+	Undo  *Undo             `json:"undo,omitempty"`
+	// Balance is the result of the last low-balance check.
+	Balance *BalanceCheck `json:"balance,omitempty"`
+	// CopyGroups are the task IDs of each task posted with --copies.
+	CopyGroups [][]string `json:"copy_groups,omitempty"`
+	// SurvivingCopies are the copies 'tasks compare' couldn't stop after
+	// approving another one, by the approved copy's task ID.
+	SurvivingCopies map[string][]string `json:"surviving_copies,omitempty"`
+	// Labels are your own labels on tasks, by task ID.
+	Labels map[string][]string `json:"labels,omitempty"`
+	// Chunks are the parts of a --chunk context still to be sent, by task ID.
+	Chunks map[string][]string `json:"chunks,omitempty"`
+	// Seen is when each task was first seen in each status, by task ID and
+	// status, since the server doesn't say when a task was claimed or
+	// delivered.
+	Seen map[string]map[string]time.Time `json:"seen,omitempty"`
+	// Mirrors are the tasks this profile posted with 'tasks mirror', by task
+	// ID, with the task each one mirrors.
+	Mirrors map[string]Mirror `json:"mirrors,omitempty"`
+	// Imports are the tasks posted from issue tracker tickets with
+	// 'pinchwork import', by task ID, until their tickets are done.
+	Imports map[string]Import `json:"imports,omitempty"`
+}
+
+// Import links a task to the ticket it was posted from. Status is the task
+// status last reported on the ticket.
+type Import struct {
+	Tracker    string    `json:"tracker"`
+	Key        string    `json:"key"`
+	URL        string    `json:"url,omitempty"`
+	Status     string    `json:"status"`
+	ImportedAt time.Time `json:"imported_at"`
+}
+
+// ImportedTask returns the task posted from a ticket, if it is still
+// tracked.
+func (s *State) ImportedTask(tracker, key string) (string, bool) {
+	for id, imp := range s.Imports {
+		if imp.Tracker == tracker && imp.Key == key {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// Mirror is the task on another profile's server that a mirrored task was
+// copied from, and that gets its result once the mirror is approved.
+type Mirror struct {
+	Profile    string    `json:"profile"`
+	TaskID     string    `json:"task_id"`
+	MirroredAt time.Time `json:"mirrored_at"`
+}
+
+// BalanceCheck remembers the balance seen by the low-balance warning, so the
+// server isn't asked on every command and alerts fire only when the balance
+// first drops below the threshold.
+type BalanceCheck struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Balance   int       `json:"balance"`
+	Low       bool      `json:"low"`
+}
+
+// Star is a task shortlisted for later. Need is copied from the task when it
+// is starred so the list can be shown without the server.
+type Star struct {
+	TaskID    string    `json:"task_id"`
+	Priority  int       `json:"priority,omitempty"`
+	Need      string    `json:"need,omitempty"`
+	StarredAt time.Time `json:"starred_at"`
+}
+
+// Note is a private working note on a task. Notes never leave this machine.
+type Note struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Undo remembers the last cancel or abandon so it can be reversed. For a
+// cancel it keeps what is needed to post the task again.
+type Undo struct {
+	Action               string    `json:"action"`
+	TaskID               string    `json:"task_id"`
+	At                   time.Time `json:"at"`
+	Need                 string    `json:"need,omitempty"`
+	Context              string    `json:"context,omitempty"`
+	ReviewTimeoutMinutes *int      `json:"review_timeout_minutes,omitempty"`
+	ClaimTimeoutMinutes  *int      `json:"claim_timeout_minutes,omitempty"`
+}
+
+// Load reads the state file at path. A missing file is an empty state.
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save writes the state to path, replacing the previous file atomically.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// AddStar stars a task, or updates the star if it already exists.
+func (s *State) AddStar(star Star) {
+	for i, existing := range s.Stars {
+		if existing.TaskID == star.TaskID {
+			star.StarredAt = existing.StarredAt
+			s.Stars[i] = star
+			return
+		}
+	}
+	s.Stars = append(s.Stars, star)
+}
+
+// RemoveStar unstars a task, reporting whether it was starred.
+func (s *State) RemoveStar(taskID string) bool {
+	for i, star := range s.Stars {
+		if star.TaskID == taskID {
+			s.Stars = append(s.Stars[:i], s.Stars[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// StarsByPriority returns the stars highest priority first, oldest first
+// within a priority.
+func (s *State) StarsByPriority() []Star {
+	stars := append([]Star(nil), s.Stars...)
+	sort.SliceStable(stars, func(i, j int) bool {
+		if stars[i].Priority != stars[j].Priority {
+			return stars[i].Priority > stars[j].Priority
+		}
+		return stars[i].StarredAt.Before(stars[j].StarredAt)
+	})
+	return stars
+}
+
+// AddNote appends a note to a task.
+func (s *State) AddNote(taskID, text string) {
+	if s.Notes == nil {
+		s.Notes = map[string][]Note{}
+	}
+	s.Notes[taskID] = append(s.Notes[taskID], Note{Text: text, CreatedAt: time.Now().UTC()})
+}
+
+// ClearNotes removes all notes on a task, reporting whether it had any.
+func (s *State) ClearNotes(taskID string) bool {
+	if len(s.Notes[taskID]) == 0 {
+		return false
+	}
+	delete(s.Notes, taskID)
+	return true
+}
+
+// AddLabel labels a task, reporting whether it didn't have the label yet.
+func (s *State) AddLabel(taskID, label string) bool {
+	if s.HasLabel(taskID, label) {
+		return false
+	}
+	if s.Labels == nil {
+		s.Labels = map[string][]string{}
+	}
+	s.Labels[taskID] = append(s.Labels[taskID], label)
+	sort.Strings(s.Labels[taskID])
+	return true
+}
+
+// RemoveLabel takes a label off a task, reporting whether it had it.
+func (s *State) RemoveLabel(taskID, label string) bool {
+	labels := s.Labels[taskID]
+	for i, l := range labels {
+		if l == label {
+			labels = append(labels[:i], labels[i+1:]...)
+			if len(labels) == 0 {
+				delete(s.Labels, taskID)
+			} else {
+				s.Labels[taskID] = labels
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// HasLabel reports whether a task has the label.
+func (s *State) HasLabel(taskID, label string) bool {
+	for _, l := range s.Labels[taskID] {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// CopyGroup returns the copies posted together with taskID, including
+// taskID itself, or nil if it wasn't posted with --copies.
+func (s *State) CopyGroup(taskID string) []string {
+	for _, g := range s.CopyGroups {
+		for _, id := range g {
+			if id == taskID {
+				return g
+			}
+		}
+	}
+	return nil
+}
+
+// SeeStatus records that a task was in status at t, unless it was seen in
+// that status before. It reports whether that is news.
+func (s *State) SeeStatus(taskID, status string, t time.Time) bool {
+	if _, ok := s.Seen[taskID][status]; ok {
+		return false
+	}
+	if s.Seen == nil {
+		s.Seen = map[string]map[string]time.Time{}
+	}
+	if s.Seen[taskID] == nil {
+		s.Seen[taskID] = map[string]time.Time{}
+	}
+	s.Seen[taskID][status] = t.UTC()
+	return true
+}
@@ -0,0 +1,268 @@
+// Package taskindex is a local full-text index of tasks fetched from the
+// marketplace, so past tasks and their results can be searched without
+// paging through the API. It is kept as a JSON file next to the config.
+package taskindex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Roles a task can have in the index.
+const (
+	RolePoster = "poster"
+	RoleWorker = "worker"
+	// RoleFeed tasks were seen on the public feed, not posted or worked on.
+	RoleFeed = "feed"
+)
+
+// Doc is one indexed task.
+type Doc struct {
+	TaskID  string `json:"task_id"`
+	Role    string `json:"role"`
+	Status  string `json:"status"`
+	Need    string `json:"need"`
+	Context string `json:"context,omitempty"`
+	Result  string `json:"result,omitempty"`
+	// Date is when the task was created, or when it was first seen if the
+	// server didn't say.
+	Date      time.Time `json:"date"`
+	IndexedAt time.Time `json:"indexed_at"`
+}
+
+// Mine reports whether the task was posted or worked on by this profile.
+func (d *Doc) Mine() bool {
+	return d.Role == RolePoster || d.Role == RoleWorker
+}
+
+type Index struct {
+	SyncedAt time.Time       `json:"synced_at"`
+	Docs     map[string]*Doc `json:"docs"`
+}
+
+// Load reads the index at path. A missing file is an empty index.
+func Load(path string) (*Index, error) {
+	idx := &Index{Docs: map[string]*Doc{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("invalid task index %s: %w (delete it to rebuild)", path, err)
+	}
+	if idx.Docs == nil {
+		idx.Docs = map[string]*Doc{}
+	}
+	return idx, nil
+}
+
+// Save writes the index to path, replacing the previous file atomically.
+func (idx *Index) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Put adds or updates a task. A task stays marked as yours once it was, and
+// keeps the date it was first seen if d has none.
+func (idx *Index) Put(d Doc) {
+	now := time.Now().UTC()
+	d.IndexedAt = now
+	if old, ok := idx.Docs[d.TaskID]; ok {
+		if d.Role == RoleFeed && old.Mine() {
+			d.Role = old.Role
+		}
+		if d.Date.IsZero() {
+			d.Date = old.Date
+		}
+		if d.Result == "" {
+			d.Result = old.Result
+		}
+	}
+	if d.Date.IsZero() {
+		d.Date = now
+	}
+	idx.Docs[d.TaskID] = &d
+}
+
+// Query selects and ranks tasks. Every term must appear, as a word or the
+// start of one, in the need, the context or (with IncludeResults) the
+// result.
+type Query struct {
+	Text           string
+	Mine           bool
+	IncludeResults bool
+	Status         string
+	Since          time.Time
+	Limit          int
+}
+
+type Hit struct {
+	Doc   *Doc    `json:"task"`
+	Score float64 `json:"score"`
+	// Field is where the best match is: need, context or result.
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+}
+
+// Field weights: a match in the need says more about a task than one deep
+// in its context.
+var fieldWeights = map[string]float64{"need": 3, "context": 1, "result": 1.5}
+
+// Search returns the matching tasks, best first.
+func (idx *Index) Search(q Query) []Hit {
+	terms := Tokenize(q.Text)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	type candidate struct {
+		doc    *Doc
+		fields map[string][]string
+	}
+	var candidates []candidate
+	df := make([]int, len(terms))
+	for _, d := range idx.Docs {
+		if q.Mine && !d.Mine() || q.Status != "" && d.Status != q.Status || !q.Since.IsZero() && d.Date.Before(q.Since) {
+			continue
+		}
+		fields := map[string][]string{"need": Tokenize(d.Need), "context": Tokenize(d.Context)}
+		if q.IncludeResults {
+			fields["result"] = Tokenize(d.Result)
+		}
+		all := true
+		for i, t := range terms {
+			found := false
+			for _, tokens := range fields {
+				if countMatches(tokens, t) > 0 {
+					found = true
+					break
+				}
+			}
+			if found {
+				df[i]++
+			} else {
+				all = false
+			}
+		}
+		if all {
+			candidates = append(candidates, candidate{d, fields})
+		}
+	}
+
+	n := float64(len(idx.Docs))
+	var hits []Hit
+	for _, c := range candidates {
+		hit := Hit{Doc: c.doc}
+		best := 0.0
+		for name, tokens := range c.fields {
+			fieldScore := 0.0
+			for i, t := range terms {
+				if tf := countMatches(tokens, t); tf > 0 {
+					idf := math.Log(1 + n/float64(df[i]))
+					fieldScore += (1 + math.Log(float64(tf))) * idf
+				}
+			}
+			fieldScore *= fieldWeights[name]
+			hit.Score += fieldScore
+			if fieldScore > best {
+				best, hit.Field = fieldScore, name
+			}
+		}
+		hit.Snippet = Snippet(fieldText(c.doc, hit.Field), terms, 100)
+		hits = append(hits, hit)
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Doc.Date.After(hits[j].Doc.Date)
+	})
+	if q.Limit > 0 && len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return hits
+}
+
+func fieldText(d *Doc, field string) string {
+	switch field {
+	case "context":
+		return d.Context
+	case "result":
+		return d.Result
+	}
+	return d.Need
+}
+
+// Tokenize splits s into lowercase words.
+func Tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// countMatches counts the tokens that start with term, so "summar" finds
+// "summarize" and "summarization".
+func countMatches(tokens []string, term string) int {
+	n := 0
+	for _, t := range tokens {
+		if strings.HasPrefix(t, term) {
+			n++
+		}
+	}
+	return n
+}
+
+// Snippet returns about width characters of s around the first term found,
+// on one line.
+func Snippet(s string, terms []string, width int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	lower := []rune(strings.ToLower(s))
+	at := -1
+	for _, t := range terms {
+		if i := strings.Index(string(lower), t); i >= 0 {
+			at = len([]rune(string(lower)[:i]))
+			break
+		}
+	}
+	start := 0
+	if at > width/3 {
+		start = at - width/3
+	}
+	end := min(start+width, len(runes))
+	start = max(0, end-width)
+
+	out := string(runes[start:end])
+	if start > 0 {
+		out = "..." + out
+	}
+	if end < len(runes) {
+		out += "..."
+	}
+	return out
+}
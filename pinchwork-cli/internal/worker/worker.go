@@ -0,0 +1,242 @@
+// Package worker runs the claim, work and deliver loop behind 'pinchwork
+// work', so that the command only has to supply a Handler that runs the
+// task:
+//
+//	err := worker.Run(ctx, c, worker.HandlerFunc(func(ctx context.Context, task *client.TaskPickupResponse) (worker.Result, error) {
+//		return worker.Result{Output: summarize(task.Context)}, nil
+//	}))
+//
+// It is internal to the CLI, like the client it drives; agents written in Go
+// should run 'pinchwork work' with their handler as the command.
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/anneschuth/pinchwork/pinchwork-cli/internal/client"
+)
+
+// Result is what a handler delivers.
+type Result struct {
+	Output string
+	// CreditsClaimed asks for less than the task's max credits; nil claims
+	// all of them.
+	CreditsClaimed *int
+}
+
+// Handler does the work for one claimed task. ctx ends at the task's claim
+// deadline. Returning an error gives the task back for another worker.
+type Handler interface {
+	Handle(ctx context.Context, task *client.TaskPickupResponse) (Result, error)
+}
+
+// HandlerFunc adapts a function to a Handler.
+type HandlerFunc func(ctx context.Context, task *client.TaskPickupResponse) (Result, error)
+
+func (f HandlerFunc) Handle(ctx context.Context, task *client.TaskPickupResponse) (Result, error) {
+	return f(ctx, task)
+}
+
+// Worker claims tasks and hands them to Handler, up to Concurrency at a
+// time. Only Client and Handler are required.
+type Worker struct {
+	Client  *client.Client
+	Handler Handler
+
+	// Concurrency is the most tasks worked on at once (default 1).
+	Concurrency int
+	// Tags and Search narrow the tasks claimed by the default Claim.
+	Tags, Search string
+	// PollInterval is how long to wait when no task is available (default
+	// 15s). After failed pickups the wait doubles, up to MaxBackoff.
+	PollInterval time.Duration
+	MaxBackoff   time.Duration
+	// DeliverRetries is how often a delivery that failed on a network or
+	// server error is retried: 0 means the default of 3, and -1 none.
+	DeliverRetries int
+	// Logger gets one line per step, tagged with the task ID (default
+	// slog.Default()).
+	Logger *slog.Logger
+
+	// Claim claims the next task, or returns nil if there is none. It
+	// replaces the default PickupTask(Tags, Search), e.g. to filter tasks.
+	Claim func(ctx context.Context) (*client.TaskPickupResponse, error)
+
+	// OnClaimed, OnDelivered and OnAbandoned are called after each step,
+	// from the goroutine working on the task.
+	OnClaimed   func(task *client.TaskPickupResponse)
+	OnDelivered func(task *client.TaskPickupResponse, resp *client.TaskResponse)
+	OnAbandoned func(task *client.TaskPickupResponse, reason error)
+}
+
+// Run works on tasks with h until ctx is done, with the default settings.
+func Run(ctx context.Context, c *client.Client, h Handler) error {
+	return (&Worker{Client: c, Handler: h}).Run(ctx)
+}
+
+// Run claims and works on tasks until ctx is done, then waits for the tasks
+// in progress. Handlers aren't cancelled when ctx is, only at their claim
+// deadline, so a graceful stop lets them deliver.
+func (w *Worker) Run(ctx context.Context) error {
+	if w.Client == nil || w.Handler == nil {
+		return errors.New("worker: Client and Handler are required")
+	}
+	concurrency := w.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	interval := w.PollInterval
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	maxBackoff := w.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 5 * time.Minute
+	}
+	maxBackoff = max(maxBackoff, interval)
+	log := w.logger()
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+	wait := interval
+	for ctx.Err() == nil {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+
+		task, err := w.claim(ctx)
+		if err != nil || task == nil {
+			<-slots
+			if err != nil {
+				log.Warn("pickup failed", "err", err, "retry_in", wait)
+				sleep(ctx, wait)
+				wait = min(2*wait, maxBackoff)
+			} else {
+				wait = interval
+				sleep(ctx, interval)
+			}
+			continue
+		}
+		wait = interval
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			w.work(ctx, task)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (w *Worker) claim(ctx context.Context) (*client.TaskPickupResponse, error) {
+	if w.Claim != nil {
+		return w.Claim(ctx)
+	}
+	return w.Client.PickupTask(w.Tags, w.Search)
+}
+
+func (w *Worker) logger() *slog.Logger {
+	if w.Logger != nil {
+		return w.Logger
+	}
+	return slog.Default()
+}
+
+// work runs the handler on one claimed task and delivers or abandons it.
+func (w *Worker) work(ctx context.Context, task *client.TaskPickupResponse) {
+	log := w.logger().With("task", task.TaskID)
+	log.Info("picked up", "need", task.Need, "credits", task.MaxCredits, "claim_deadline", task.ClaimDeadline)
+	if w.OnClaimed != nil {
+		w.OnClaimed(task)
+	}
+
+	var hctx context.Context
+	var cancel context.CancelFunc
+	if task.ClaimDeadline.IsZero() {
+		hctx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+	} else {
+		hctx, cancel = context.WithDeadline(context.WithoutCancel(ctx), task.ClaimDeadline.Time)
+	}
+	defer cancel()
+
+	start := time.Now()
+	res, err := w.Handler.Handle(hctx, task)
+	if err == nil && res.Output == "" {
+		err = errors.New("handler returned no output")
+	}
+	if err != nil {
+		log.Error("handler failed", "err", err, "elapsed", time.Since(start).Round(time.Millisecond))
+		if _, aerr := w.Client.AbandonTask(task.TaskID); aerr != nil {
+			log.Error("abandon failed", "err", aerr)
+			return
+		}
+		log.Info("abandoned")
+		if w.OnAbandoned != nil {
+			w.OnAbandoned(task, err)
+		}
+		return
+	}
+
+	resp, err := w.deliver(hctx, log, task, res)
+	if err != nil {
+		log.Error("deliver failed", "err", err)
+		return
+	}
+	log.Info("delivered", "status", resp.Status, "elapsed", time.Since(start).Round(time.Millisecond))
+	if w.OnDelivered != nil {
+		w.OnDelivered(task, resp)
+	}
+}
+
+// deliver delivers res, retrying network and server errors with backoff
+// until DeliverRetries is used up or the claim deadline passes.
+func (w *Worker) deliver(ctx context.Context, log *slog.Logger, task *client.TaskPickupResponse, res Result) (*client.TaskResponse, error) {
+	retries := w.DeliverRetries
+	switch {
+	case retries == 0:
+		retries = 3
+	case retries < 0:
+		retries = 0
+	}
+	wait := time.Second
+	for attempt := 0; ; attempt++ {
+		resp, err := w.Client.DeliverTask(task.TaskID, res.Output, res.CreditsClaimed)
+		if err == nil || attempt == retries || !retryable(err) {
+			return resp, err
+		}
+		log.Warn("deliver failed, retrying", "err", err, "retry_in", wait)
+		sleep(ctx, wait)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w (claim deadline passed while retrying)", err)
+		}
+		wait *= 2
+	}
+}
+
+// retryable reports whether a request may succeed if repeated: network
+// errors, rate limiting and server errors.
+func retryable(err error) bool {
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == 429 || apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
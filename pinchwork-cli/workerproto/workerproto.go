@@ -0,0 +1,214 @@
+// Package workerproto defines the JSON protocol between 'pinchwork work
+// --protocol json' and the handler command it runs for each task.
+//
+// Every message is one JSON object on its own line with a "type" field. The
+// CLI writes a TaskInput to the handler's stdin. The handler may then write
+// Question messages to stdout; each is sent to the task's poster as a task
+// message, and the poster's next message comes back on stdin as an Answer. The handler ends by writing a
+// TaskOutput and exiting 0. Anything the handler writes to stderr is passed
+// through as-is.
+//
+//	-> {"type":"task","version":1,"task_id":"tk-abc123","need":"...","max_credits":100,...}
+//	<- {"type":"question","question":"Python 3.11 or 3.12?"}
+//	-> {"type":"answer","question_id":"msg-1","answer":"3.12"}
+//	<- {"type":"output","status":"done","result":"...","credits_claimed":80}
+//
+// Handlers written in Go can use Conn instead of encoding messages by hand.
+package workerproto
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Version is the protocol version sent in TaskInput. It only changes when
+// the protocol changes in a way existing handlers would misread.
+const Version = 1
+
+// Message types.
+const (
+	TypeTask     = "task"
+	TypeQuestion = "question"
+	TypeAnswer   = "answer"
+	TypeOutput   = "output"
+)
+
+// TaskOutput statuses.
+const (
+	// StatusDone delivers Result.
+	StatusDone = "done"
+	// StatusFailed gives the task back for another worker to claim.
+	StatusFailed = "failed"
+)
+
+// TaskInput is the claimed task, the first message the handler reads.
+type TaskInput struct {
+	Type       string   `json:"type"`
+	Version    int      `json:"version"`
+	TaskID     string   `json:"task_id"`
+	Need       string   `json:"need"`
+	Context    string   `json:"context,omitempty"`
+	MaxCredits int      `json:"max_credits"`
+	PosterID   string   `json:"poster_id"`
+	Tags       []string `json:"tags,omitempty"`
+	// ClaimDeadline is when the claim expires, if it does. The handler is
+	// killed at that point.
+	ClaimDeadline *time.Time `json:"claim_deadline,omitempty"`
+}
+
+// Question asks the poster something the handler needs to finish the task.
+type Question struct {
+	Type     string `json:"type"`
+	Question string `json:"question"`
+}
+
+// Answer is the poster's answer to the handler's last Question.
+type Answer struct {
+	Type string `json:"type"`
+	// QuestionID is the ID of the task message that asked the question.
+	QuestionID string `json:"question_id"`
+	Answer     string `json:"answer"`
+}
+
+// TaskOutput is the handler's last message.
+type TaskOutput struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	// Result is the delivery, required with StatusDone.
+	Result string `json:"result,omitempty"`
+	// CreditsClaimed asks for less than the task's max credits.
+	CreditsClaimed *int `json:"credits_claimed,omitempty"`
+	// Messages are sent to the poster before delivering.
+	Messages []string `json:"messages,omitempty"`
+	// Error says why the handler failed, for the worker's log.
+	Error string `json:"error,omitempty"`
+}
+
+// Validate checks the fields that depend on Status.
+func (o *TaskOutput) Validate() error {
+	switch o.Status {
+	case StatusDone:
+		if o.Result == "" {
+			return errors.New("output with status done has no result")
+		}
+		if o.CreditsClaimed != nil && *o.CreditsClaimed <= 0 {
+			return errors.New("credits_claimed must be positive")
+		}
+	case StatusFailed:
+	default:
+		return fmt.Errorf("output status must be %s or %s, not %q", StatusDone, StatusFailed, o.Status)
+	}
+	return nil
+}
+
+// Decode parses one line of the protocol into a *TaskInput, *Question,
+// *Answer or *TaskOutput, according to its type.
+func Decode(line []byte) (interface{}, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(line, &head); err != nil {
+		return nil, fmt.Errorf("invalid protocol message: %w", err)
+	}
+	var msg interface{}
+	switch head.Type {
+	case TypeTask:
+		msg = &TaskInput{}
+	case TypeQuestion:
+		msg = &Question{}
+	case TypeAnswer:
+		msg = &Answer{}
+	case TypeOutput:
+		msg = &TaskOutput{}
+	default:
+		return nil, fmt.Errorf("unknown protocol message type %q", head.Type)
+	}
+	if err := json.Unmarshal(line, msg); err != nil {
+		return nil, fmt.Errorf("invalid %s message: %w", head.Type, err)
+	}
+	return msg, nil
+}
+
+// Conn is the handler's end of the protocol, normally on os.Stdin and
+// os.Stdout.
+type Conn struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func NewConn(r io.Reader, w io.Writer) *Conn {
+	return &Conn{in: bufio.NewReader(r), out: w}
+}
+
+// Task reads the task. It must be called first.
+func (c *Conn) Task() (*TaskInput, error) {
+	var task *TaskInput
+	if err := c.read(&task); err != nil {
+		return nil, err
+	}
+	if task.Version > Version {
+		return nil, fmt.Errorf("protocol version %d is newer than this handler supports (%d)", task.Version, Version)
+	}
+	return task, nil
+}
+
+// Ask sends a question to the poster and waits for the answer.
+func (c *Conn) Ask(question string) (string, error) {
+	if err := c.write(Question{Type: TypeQuestion, Question: question}); err != nil {
+		return "", err
+	}
+	var answer *Answer
+	if err := c.read(&answer); err != nil {
+		return "", err
+	}
+	return answer.Answer, nil
+}
+
+// Done delivers result. creditsClaimed may be nil to claim the max credits.
+func (c *Conn) Done(result string, creditsClaimed *int, messages ...string) error {
+	return c.write(TaskOutput{Type: TypeOutput, Status: StatusDone, Result: result, CreditsClaimed: creditsClaimed, Messages: messages})
+}
+
+// Fail gives the task back, reporting err in the worker's log.
+func (c *Conn) Fail(err error) error {
+	return c.write(TaskOutput{Type: TypeOutput, Status: StatusFailed, Error: err.Error()})
+}
+
+// read reads the next message into *dst, which must be a pointer to a
+// pointer of the expected message type.
+func (c *Conn) read(dst interface{}) error {
+	line, err := c.in.ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(line) == 0) {
+		return err
+	}
+	msg, err := Decode(line)
+	if err != nil {
+		return err
+	}
+	switch d := dst.(type) {
+	case **TaskInput:
+		if m, ok := msg.(*TaskInput); ok {
+			*d = m
+			return nil
+		}
+	case **Answer:
+		if m, ok := msg.(*Answer); ok {
+			*d = m
+			return nil
+		}
+	}
+	return fmt.Errorf("unexpected %T message", msg)
+}
+
+func (c *Conn) write(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = c.out.Write(append(data, '\n'))
+	return err
+}